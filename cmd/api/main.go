@@ -34,6 +34,11 @@ func main() {
 		Addr:    ":8007",
 		Handler: voyageRouter,
 	}
+	grpcRouter := routers.GRPCRouter()
+	grpcServer := &http.Server{
+		Addr:    ":8009",
+		Handler: grpcRouter,
+	}
 	go func() {
 		log.Info("Starting HTTP Server on port 8001 for health check")
 		if err := healthServer.ListenAndServe(); err != http.ErrServerClosed {
@@ -62,6 +67,13 @@ func main() {
 		}
 	}()
 
+	go func() {
+		log.Info("Starting gRPC Server(h2c) on port 8009 for p2p schedule and master vessel voyage")
+		if err := grpcServer.ListenAndServe(); err != http.ErrServerClosed {
+			log.Error("Server Error: ", err)
+		}
+	}()
+
 	//Listen for SIGINT/ SIGTERM signal to trigger shutdown
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
@@ -76,6 +88,7 @@ func main() {
 	_ = configServer.Shutdown(ctx)
 	_ = scheduleServer.Shutdown(ctx)
 	_ = voyageServer.Shutdown(ctx)
+	_ = grpcServer.Shutdown(ctx)
 
 	log.Info("Server gracefully stopped")
 }
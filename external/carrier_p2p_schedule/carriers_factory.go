@@ -21,6 +21,7 @@ type CarrierConfig struct {
 	RequiresLocation bool `default:"false"`
 	CacheDuration    time.Duration
 	CacheKey         string
+	NoRouteDuration  time.Duration
 	RequiresAuth     bool
 	AuthExpiration   time.Duration
 	AuthSchema       interfaces.TokenProvider
@@ -37,40 +38,43 @@ func NewP2PScheduleServiceFactory(e *env.Manager) *P2PScheduleServiceFactory {
 	return &P2PScheduleServiceFactory{
 		configs: map[schema.CarrierCode]CarrierConfig{
 			schema.ZIMU: {
-				Name:           "ZIM",
-				BaseURL:        *e.ZimURL,
-				AuthURL:        *e.ZimTURL,
-				Method:         http.MethodGet,
-				CacheDuration:  6 * time.Hour,
-				CacheKey:       "zim schedule",
-				RequiresAuth:   true,
-				AuthExpiration: 55 * time.Minute,
-				AuthSchema:     &ZimScheduleResponse{},
-				BaseSchema:     &ZimScheduleResponse{},
+				Name:            "ZIM",
+				BaseURL:         *e.ZimURL,
+				AuthURL:         *e.ZimTURL,
+				Method:          http.MethodGet,
+				CacheDuration:   6 * time.Hour,
+				CacheKey:        "zim schedule",
+				NoRouteDuration: 3 * time.Hour,
+				RequiresAuth:    true,
+				AuthExpiration:  55 * time.Minute,
+				AuthSchema:      &ZimScheduleResponse{},
+				BaseSchema:      &ZimScheduleResponse{},
 			},
 			schema.ONEY: {
-				Name:           "ONE DCSA",
-				BaseURL:        *e.OneDCSAURL,
-				AuthURL:        *e.OneTURL,
-				Method:         http.MethodGet,
-				CacheDuration:  6 * time.Hour,
-				CacheKey:       "one dcsa schedule",
-				RequiresAuth:   true,
-				AuthExpiration: 55 * time.Minute,
-				AuthSchema:     &OneDCSAScheduleResponse{},
-				BaseSchema:     &OneDCSAScheduleResponse{},
+				Name:            "ONE DCSA",
+				BaseURL:         *e.OneDCSAURL,
+				AuthURL:         *e.OneTURL,
+				Method:          http.MethodGet,
+				CacheDuration:   6 * time.Hour,
+				CacheKey:        "one dcsa schedule",
+				NoRouteDuration: 1 * time.Hour,
+				RequiresAuth:    true,
+				AuthExpiration:  55 * time.Minute,
+				AuthSchema:      &OneDCSAScheduleResponse{},
+				BaseSchema:      &OneDCSAScheduleResponse{},
 			},
 			schema.MSCU: {
-				Name:           "MSC",
-				BaseURL:        *e.MscURL,
-				AuthURL:        *e.MscOauth,
-				Method:         http.MethodGet,
-				CacheDuration:  6 * time.Hour,
-				CacheKey:       "msc schedule",
-				RequiresAuth:   true,
-				AuthExpiration: 55 * time.Minute,
-				AuthSchema:     &MscScheduleResponse{},
-				BaseSchema:     &MscScheduleResponse{},
+				Name:            "MSC",
+				BaseURL:         *e.MscURL,
+				AuthURL:         *e.MscOauth,
+				Method:          http.MethodGet,
+				CacheDuration:   6 * time.Hour,
+				CacheKey:        "msc schedule",
+				NoRouteDuration: 3 * time.Hour,
+				RequiresAuth:    true,
+				AuthExpiration:  55 * time.Minute,
+				AuthSchema:      &MscScheduleResponse{},
+				BaseSchema:      &MscScheduleResponse{},
 			},
 			schema.CMDU: {
 				Name:             "CMA",
@@ -78,63 +82,70 @@ func NewP2PScheduleServiceFactory(e *env.Manager) *P2PScheduleServiceFactory {
 				Method:           http.MethodGet,
 				CacheDuration:    6 * time.Hour,
 				CacheKey:         "cma schedule",
+				NoRouteDuration:  1 * time.Hour,
 				RequiresAuth:     false,
 				RequiresLocation: false,
 				BaseSchema:       &CmaScheduleResponse{},
 			},
 			schema.APLU: {
-				Name:          "APL",
-				BaseURL:       *e.CmaURL,
-				Method:        http.MethodGet,
-				CacheDuration: 6 * time.Hour,
-				CacheKey:      "apl schedule",
-				RequiresAuth:  false,
-				BaseSchema:    &CmaScheduleResponse{},
+				Name:            "APL",
+				BaseURL:         *e.CmaURL,
+				Method:          http.MethodGet,
+				CacheDuration:   6 * time.Hour,
+				CacheKey:        "apl schedule",
+				NoRouteDuration: 1 * time.Hour,
+				RequiresAuth:    false,
+				BaseSchema:      &CmaScheduleResponse{},
 			},
 			schema.ANNU: {
-				Name:          "ANL",
-				BaseURL:       *e.CmaURL,
-				Method:        http.MethodGet,
-				CacheDuration: 6 * time.Hour,
-				CacheKey:      "anl schedule",
-				RequiresAuth:  false,
-				BaseSchema:    &CmaScheduleResponse{},
+				Name:            "ANL",
+				BaseURL:         *e.CmaURL,
+				Method:          http.MethodGet,
+				CacheDuration:   6 * time.Hour,
+				CacheKey:        "anl schedule",
+				NoRouteDuration: 1 * time.Hour,
+				RequiresAuth:    false,
+				BaseSchema:      &CmaScheduleResponse{},
 			},
 			schema.CHNL: {
-				Name:          "CHL",
-				BaseURL:       *e.CmaURL,
-				Method:        http.MethodGet,
-				CacheDuration: 6 * time.Hour,
-				CacheKey:      "cnl schedule",
-				RequiresAuth:  false,
-				BaseSchema:    &CmaScheduleResponse{},
+				Name:            "CHL",
+				BaseURL:         *e.CmaURL,
+				Method:          http.MethodGet,
+				CacheDuration:   6 * time.Hour,
+				CacheKey:        "cnl schedule",
+				NoRouteDuration: 1 * time.Hour,
+				RequiresAuth:    false,
+				BaseSchema:      &CmaScheduleResponse{},
 			},
 			schema.HLCU: {
-				Name:          "HAPAG",
-				BaseURL:       *e.HapagURL,
-				Method:        http.MethodGet,
-				CacheDuration: 6 * time.Hour,
-				CacheKey:      "hapag schedule",
-				RequiresAuth:  false,
-				BaseSchema:    &HapagScheduleResponse{},
+				Name:            "HAPAG",
+				BaseURL:         *e.HapagURL,
+				Method:          http.MethodGet,
+				CacheDuration:   6 * time.Hour,
+				CacheKey:        "hapag schedule",
+				NoRouteDuration: 1 * time.Hour,
+				RequiresAuth:    false,
+				BaseSchema:      &HapagScheduleResponse{},
 			},
 			schema.COSU: {
-				Name:          "Cosco",
-				BaseURL:       *e.IqaxURL + "/" + string(schema.COSU),
-				Method:        http.MethodGet,
-				CacheDuration: 6 * time.Hour,
-				CacheKey:      "cosco schedule",
-				RequiresAuth:  false,
-				BaseSchema:    &IqaxScheduleResponse{},
+				Name:            "Cosco",
+				BaseURL:         *e.IqaxURL + "/" + string(schema.COSU),
+				Method:          http.MethodGet,
+				CacheDuration:   6 * time.Hour,
+				CacheKey:        "cosco schedule",
+				NoRouteDuration: 1 * time.Hour,
+				RequiresAuth:    false,
+				BaseSchema:      &IqaxScheduleResponse{},
 			},
 			schema.OOLU: {
-				Name:          "OOCL",
-				BaseURL:       *e.IqaxURL + "/" + string(schema.OOLU),
-				Method:        http.MethodGet,
-				CacheDuration: 6 * time.Hour,
-				CacheKey:      "oocl schedule",
-				RequiresAuth:  false,
-				BaseSchema:    &IqaxScheduleResponse{},
+				Name:            "OOCL",
+				BaseURL:         *e.IqaxURL + "/" + string(schema.OOLU),
+				Method:          http.MethodGet,
+				CacheDuration:   6 * time.Hour,
+				CacheKey:        "oocl schedule",
+				NoRouteDuration: 1 * time.Hour,
+				RequiresAuth:    false,
+				BaseSchema:      &IqaxScheduleResponse{},
 			},
 			schema.MAEU: {
 				Name:             "MAEU",
@@ -143,6 +154,7 @@ func NewP2PScheduleServiceFactory(e *env.Manager) *P2PScheduleServiceFactory {
 				Method:           http.MethodGet,
 				CacheDuration:    6 * time.Hour,
 				CacheKey:         "maersk a/s schedule",
+				NoRouteDuration:  1 * time.Hour,
 				LocationDuration: 8000 * time.Hour,
 				LocationKey:      "maersk location",
 				RequiresLocation: true,
@@ -157,6 +169,7 @@ func NewP2PScheduleServiceFactory(e *env.Manager) *P2PScheduleServiceFactory {
 				Method:           http.MethodGet,
 				CacheDuration:    6 * time.Hour,
 				CacheKey:         "maersk line schedule",
+				NoRouteDuration:  1 * time.Hour,
 				LocationDuration: 8000 * time.Hour,
 				LocationKey:      "maersk location",
 				RequiresLocation: true,
@@ -170,6 +183,12 @@ func NewP2PScheduleServiceFactory(e *env.Manager) *P2PScheduleServiceFactory {
 	}
 }
 
+// CarrierConfig returns the registered config of the carrier
+func (f *P2PScheduleServiceFactory) CarrierConfig(carrier schema.CarrierCode) (CarrierConfig, bool) {
+	config, exists := f.configs[carrier]
+	return config, exists
+}
+
 func (f *P2PScheduleServiceFactory) CreateScheduleService(carrier schema.CarrierCode) (interfaces.Schedule[[]*schema.P2PSchedule, *schema.QueryParams], error) {
 	config, exists := f.configs[carrier]
 	if !exists {
@@ -112,25 +112,29 @@ var getLocationCode = func(cmaSchedule *CmaSchedule, portType string) string {
 
 const cmaDateFormat string = "2006-01-02T15:04:05Z"
 
+// cmaEventDate returns the local time of the event(the GMT one when CMA has no local time) and the UTC time. The dates are passed by
+// value so that the GMT date of the response is left as it is for the legs
+func cmaEventDate(local, gmt string) (string, string) {
+	utc := external.ConvertUTCDate(gmt, cmaDateFormat)
+	return cmp.Or(external.ConvertDateFormat(&local, cmaDateFormat), external.ConvertDateFormat(&gmt, cmaDateFormat)), utc
+}
+
 func (csp *CmaScheduleResponse) GenerateSchedule(responseJson []byte) ([]*schema.P2PSchedule, error) {
-	var getFirstEtdLastEta = func(cmaSchedule *CmaSchedule, portType string) string {
-		var reformatDate string
+	var getFirstEtdLastEta = func(cmaSchedule *CmaSchedule, portType string) (string, string) {
 		if portType == "etd" {
 			for _, routeDetails := range cmaSchedule.RoutingDetails {
 				if routeDetails.PointFrom.DepartureDateGmt != "" {
-					reformatDate = external.ConvertDateFormat(&routeDetails.PointFrom.DepartureDateGmt, cmaDateFormat)
-					break
+					return cmaEventDate(routeDetails.PointFrom.DepartureDateLocal, routeDetails.PointFrom.DepartureDateGmt)
 				}
 			}
 		} else {
 			for i := len(cmaSchedule.RoutingDetails) - 1; i >= 0; i-- {
 				if cmaSchedule.RoutingDetails[i].PointTo.ArrivalDateGmt != "" {
-					reformatDate = external.ConvertDateFormat(&cmaSchedule.RoutingDetails[i].PointTo.ArrivalDateGmt, cmaDateFormat)
-					break
+					return cmaEventDate(cmaSchedule.RoutingDetails[i].PointTo.ArrivalDateLocal, cmaSchedule.RoutingDetails[i].PointTo.ArrivalDateGmt)
 				}
 			}
 		}
-		return reformatDate
+		return "", ""
 	}
 	var CMAScheduleData CmaScheduleResponse
 	if err := json.Unmarshal(responseJson, &CMAScheduleData); err != nil {
@@ -138,12 +142,16 @@ func (csp *CmaScheduleResponse) GenerateSchedule(responseJson []byte) ([]*schema
 	}
 	var cmaScheduleList = make([]*schema.P2PSchedule, 0, len(CMAScheduleData))
 	for _, route := range CMAScheduleData {
+		etd, etdUTC := getFirstEtdLastEta(&route, "etd")
+		eta, etaUTC := getFirstEtdLastEta(&route, "eta")
 		scheduleResult := &schema.P2PSchedule{
 			Scac:          string(schema.InternalCodeToScac[route.ShippingCompany]),
 			PointFrom:     getLocationCode(&route, "pol"),
 			PointTo:       getLocationCode(&route, "pod"),
-			Etd:           cmp.Or(getFirstEtdLastEta(&route, "etd"), time.Now().Format("2006-01-02T15:04:05")),
-			Eta:           cmp.Or(getFirstEtdLastEta(&route, "eta"), time.Now().Format("2006-01-02T15:04:05")),
+			Etd:           cmp.Or(etd, time.Now().Format("2006-01-02T15:04:05")),
+			Eta:           cmp.Or(eta, time.Now().Format("2006-01-02T15:04:05")),
+			EtdUTC:        etdUTC,
+			EtaUTC:        etaUTC,
 			TransitTime:   route.TransitTime,
 			Transshipment: len(route.RoutingDetails) > 1,
 			Legs:          csp.GenerateScheduleLeg(route.RoutingDetails),
@@ -165,6 +173,8 @@ func (csp *CmaScheduleResponse) GenerateScheduleLeg(legResponse []*RoutingDetail
 			PointTo:         pointBase.PointTo,
 			Etd:             eventDate.Etd,
 			Eta:             eventDate.Eta,
+			EtdUTC:          eventDate.EtdUTC,
+			EtaUTC:          eventDate.EtaUTC,
 			TransitTime:     eventDate.TransitTime,
 			Cutoffs:         eventDate.Cutoffs,
 			Transportations: csp.GenerateTransport(&leg.Transportation).Transportations,
@@ -208,20 +218,22 @@ func (csp *CmaScheduleResponse) GenerateLegPoints(legDetails *RoutingDetail) *sc
 }
 
 func (csp *CmaScheduleResponse) GenerateEventDate(legDetails *RoutingDetail) *schema.Leg {
-	etd := cmp.Or(external.ConvertDateFormat(&legDetails.PointFrom.DepartureDateGmt, cmaDateFormat), time.Now().Format("2006-01-02T15:04:05"))
-	eta := cmp.Or(external.ConvertDateFormat(&legDetails.PointTo.ArrivalDateGmt, cmaDateFormat), time.Now().Format("2006-01-02T15:04:05"))
+	etd, etdUTC := cmaEventDate(legDetails.PointFrom.DepartureDateLocal, legDetails.PointFrom.DepartureDateGmt)
+	eta, etaUTC := cmaEventDate(legDetails.PointTo.ArrivalDateLocal, legDetails.PointTo.ArrivalDateGmt)
+	etd = cmp.Or(etd, time.Now().Format("2006-01-02T15:04:05"))
+	eta = cmp.Or(eta, time.Now().Format("2006-01-02T15:04:05"))
 	transitTime := legDetails.LegTransitTime
 	var cutoffs *schema.Cutoff
 	var cyCutoffDate, docCutoffDate, vgmCutoffDate string
 	c := &legDetails.PointFrom.CutOff
 	if c.PortCutoff != nil {
-		cyCutoffDate = external.ConvertDateFormat(&c.PortCutoff.Utc, cmaDateFormat)
+		cyCutoffDate, _ = cmaEventDate(c.PortCutoff.Local, c.PortCutoff.Utc)
 	}
 	if c.ShippingInstructionAcceptance != nil {
-		docCutoffDate = external.ConvertDateFormat(&c.ShippingInstructionAcceptance.Utc, cmaDateFormat)
+		docCutoffDate, _ = cmaEventDate(c.ShippingInstructionAcceptance.Local, c.ShippingInstructionAcceptance.Utc)
 	}
 	if c.Vgm != nil {
-		vgmCutoffDate = external.ConvertDateFormat(&c.Vgm.Utc, cmaDateFormat)
+		vgmCutoffDate, _ = cmaEventDate(c.Vgm.Local, c.Vgm.Utc)
 	}
 
 	if cyCutoffDate != "" && docCutoffDate != "" && vgmCutoffDate != "" {
@@ -235,6 +247,8 @@ func (csp *CmaScheduleResponse) GenerateEventDate(legDetails *RoutingDetail) *sc
 	eventTime := &schema.Leg{
 		Etd:         etd,
 		Eta:         eta,
+		EtdUTC:      etdUTC,
+		EtaUTC:      etaUTC,
 		TransitTime: transitTime,
 		Cutoffs:     cutoffs,
 	}
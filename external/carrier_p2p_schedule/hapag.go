@@ -121,12 +121,14 @@ func (hsp *HapagScheduleResponse) GenerateLegPoints(legDetails *hleg) *schema.Le
 		LocationName: legDetails.Departure.Location.LocationName,
 		LocationCode: legDetails.Departure.Location.UNLocationCode,
 		TerminalCode: legDetails.Departure.Location.FacilitySMDGCode,
+		TimeZone:     external.DateOffset(legDetails.Departure.DateTime, dcsaDateFormat),
 	}
 
 	pointTo := schema.PointBase{
 		LocationName: legDetails.Arrival.Location.LocationName,
 		LocationCode: legDetails.Arrival.Location.UNLocationCode,
 		TerminalCode: legDetails.Arrival.Location.FacilitySMDGCode,
+		TimeZone:     external.DateOffset(legDetails.Arrival.DateTime, dcsaDateFormat),
 	}
 
 	portPairs := &schema.Leg{
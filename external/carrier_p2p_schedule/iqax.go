@@ -247,6 +247,8 @@ func (isp *IqaxScheduleResponse) GenerateScheduleLeg(legResponse []*IqaxLeg, sch
 				PointTo:         destination,
 				Etd:             eventDate.Etd,
 				Eta:             eventDate.Eta,
+				EtdUTC:          eventDate.EtdUTC,
+				EtaUTC:          eventDate.EtaUTC,
 				TransitTime:     eventDate.TransitTime,
 				Cutoffs:         eventDate.Cutoffs,
 				Transportations: isp.GenerateTransport(leg).Transportations,
@@ -265,6 +267,7 @@ func (isp *IqaxScheduleResponse) GenerateLegPoints(legResponse *IqaxLeg) *schema
 		LocationCode: legResponse.FromPoint.Location.Unlocode,
 		TerminalName: legResponse.FromPoint.Location.Facility.Name,
 		TerminalCode: legResponse.FromPoint.Location.Facility.Code,
+		TimeZone:     legResponse.FromPoint.Location.Timezone,
 	}
 
 	pointTo := schema.PointBase{
@@ -272,6 +275,7 @@ func (isp *IqaxScheduleResponse) GenerateLegPoints(legResponse *IqaxLeg) *schema
 		LocationCode: legResponse.ToPoint.Location.Unlocode,
 		TerminalName: legResponse.ToPoint.Location.Facility.Name,
 		TerminalCode: legResponse.ToPoint.Location.Facility.Code,
+		TimeZone:     legResponse.ToPoint.Location.Timezone,
 	}
 
 	portPairs := &schema.Leg{
@@ -306,9 +310,20 @@ func (isp *IqaxScheduleResponse) GenerateEventDate(index int, scheduleDate First
 		etd = cmp.Or(external.ConvertDateFormat(&legResponse.FromPoint.Etd, iqaxDateFormat), parseEta.AddDate(0, 0, -legResponse.TransitTime).Format(parseDateFormat))
 	}
 
+	// the GMT times only hold for the dates given by IQAX, the derived ones get theirs out of the zone of the point
+	var etdUTC, etaUTC string
+	if etd != "" && etd == legResponse.FromPoint.Etd {
+		etdUTC = external.ConvertUTCDate(legResponse.FromPoint.GmtEtd, iqaxDateFormat)
+	}
+	if eta != "" && eta == legResponse.ToPoint.Eta {
+		etaUTC = external.ConvertUTCDate(legResponse.ToPoint.GmtEta, iqaxDateFormat)
+	}
+
 	eventTime := &schema.Leg{
 		Etd:         etd,
 		Eta:         eta,
+		EtdUTC:      etdUTC,
+		EtaUTC:      etaUTC,
 		TransitTime: legResponse.TransitTime,
 		Cutoffs:     cutoffs,
 	}
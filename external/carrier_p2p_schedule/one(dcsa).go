@@ -141,6 +141,7 @@ func (ocr *OneDCSAScheduleResponse) GenerateLegPoints(legDetails *OneDCSALegs) *
 		LocationCode: legDetails.Departure.Location.UNLocationCode,
 		TerminalCode: legDetails.Departure.Location.Facility.FacilityCode,
 		TerminalName: legDetails.Departure.Location.LocationName,
+		TimeZone:     external.DateOffset(legDetails.Departure.DateTime, dcsaDateFormat),
 	}
 
 	pointTo := schema.PointBase{
@@ -148,6 +149,7 @@ func (ocr *OneDCSAScheduleResponse) GenerateLegPoints(legDetails *OneDCSALegs) *
 		LocationCode: legDetails.Arrival.Location.UNLocationCode,
 		TerminalCode: legDetails.Arrival.Location.Facility.FacilityCode,
 		TerminalName: legDetails.Arrival.Location.LocationName,
+		TimeZone:     external.DateOffset(legDetails.Arrival.DateTime, dcsaDateFormat),
 	}
 
 	portPairs := &schema.Leg{
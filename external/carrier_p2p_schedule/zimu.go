@@ -96,11 +96,13 @@ func (zs *ZimScheduleResponse) GenerateLegPoints(legDetails *zleg) *schema.Leg {
 	pointFrom := schema.PointBase{
 		LocationName: legDetails.DeparturePortName,
 		LocationCode: legDetails.DeparturePort,
+		TimeZone:     external.DateOffset(legDetails.DepartureDate, zimDateFormat),
 	}
 
 	pointTo := schema.PointBase{
 		LocationName: legDetails.ArrivalPortName,
 		LocationCode: legDetails.ArrivalPort,
+		TimeZone:     external.DateOffset(legDetails.ArrivalDate, zimDateFormat),
 	}
 
 	portPairs := &schema.Leg{
@@ -113,6 +113,12 @@ func NewVesselScheduleServiceFactory(e *env.Manager) *VesselScheduleServiceFacto
 	}
 }
 
+// CarrierConfig returns the registered config of the carrier
+func (f *VesselScheduleServiceFactory) CarrierConfig(carrier schema.CarrierCode) (CarrierConfig, bool) {
+	config, exists := f.configs[carrier]
+	return config, exists
+}
+
 func (f *VesselScheduleServiceFactory) CreateVesselScheduleService(carrier schema.CarrierCode) (interfaces.Schedule[*schema.MasterVesselSchedule, *schema.QueryParamsForVesselVoyage], error) {
 	config, exists := f.configs[carrier]
 	if !exists {
@@ -162,14 +162,18 @@ func (cvs *CMAVesselScheduleResponse) GenerateVesselCalls(vesselCalls CMAVesselS
 	var cmaPortCalls = make([]schema.PortCalls, 0, len(vesselCalls))
 
 	for _, portCalls := range vesselCalls {
-		var getEventDateTime = func(eventType string) string {
+		// the local time of the event(the UTC one when CMA has no local time) and the UTC time
+		var getEventDateTime = func(eventType string) (string, string) {
+			eventDate := portCalls.BerthDate
 			if eventType == "Load" {
-				return external.ConvertDateFormat(&portCalls.UnberthDate.Utc, cmaDateFormat)
+				eventDate = portCalls.UnberthDate
 			}
-			return external.ConvertDateFormat(&portCalls.BerthDate.Utc, cmaDateFormat)
+			utc := external.ConvertUTCDate(eventDate.Utc, cmaDateFormat)
+			return cmp.Or(external.ConvertDateFormat(&eventDate.Local, cmaDateFormat), external.ConvertDateFormat(&eventDate.Utc, cmaDateFormat)), utc
 		}
 		for _, activity := range slices.Backward(portCalls.Activities) {
 			countPortCall += 1
+			eventDate, eventDateUTC := getEventDateTime(activity)
 			portCallsResult := schema.PortCalls{
 				Seq:       countPortCall,
 				Key:       portCalls.ID,
@@ -183,7 +187,8 @@ func (cvs *CMAVesselScheduleResponse) GenerateVesselCalls(vesselCalls CMAVesselS
 					TerminalName: portCalls.Terminal.Name,
 					TerminalCode: portCalls.Terminal.Code,
 				},
-				EstimatedEventDate: getEventDateTime(activity),
+				EstimatedEventDate:    eventDate,
+				EstimatedEventDateUTC: eventDateUTC,
 			}
 			cmaPortCalls = append(cmaPortCalls, portCallsResult)
 		}
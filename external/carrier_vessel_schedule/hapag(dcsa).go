@@ -161,6 +161,26 @@ func (hvs *HapagVesselScheduleResponse) GenerateVesselCalls(vesselSchedules Hapa
 					}
 					return ""
 				}
+				var getPlannedEventDate = func(eventType string) string {
+					for _, eventDates := range portCalls.Timestamps {
+						switch true {
+						case eventDates.EventTypeCode == "ARRI" && eventDates.EventClassifierCode == "PLN" && eventType == "Unloading":
+							return external.ConvertDateFormat(&eventDates.EventDateTime, hapagDateFormat)
+						case eventDates.EventTypeCode == "DEPA" && eventDates.EventClassifierCode == "PLN" && eventType == "Loading":
+							return external.ConvertDateFormat(&eventDates.EventDateTime, hapagDateFormat)
+						}
+					}
+					return ""
+				}
+				// the timestamps carry the offset of the port, which is kept as the zone of the call
+				var getTimeZone = func() string {
+					for _, eventDates := range portCalls.Timestamps {
+						if offset := external.DateOffset(eventDates.EventDateTime, hapagDateFormat); offset != "" {
+							return offset
+						}
+					}
+					return ""
+				}
 				var getActualEventDate = func(eventType string) string {
 					for _, eventDates := range portCalls.Timestamps {
 						switch true {
@@ -189,7 +209,9 @@ func (hvs *HapagVesselScheduleResponse) GenerateVesselCalls(vesselSchedules Hapa
 							Port: &schema.Port{
 								PortCode:     portCalls.Location.UNLocationCode,
 								TerminalCode: portCalls.Location.FacilitySMDGCode,
+								TimeZone:     getTimeZone(),
 							},
+							PlannedEventDate:   getPlannedEventDate(pe.eventType),
 							EstimatedEventDate: getEstimatedEventDate(pe.eventType),
 							ActualEventDate:    getActualEventDate(pe.eventType),
 						}
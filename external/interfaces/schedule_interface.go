@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	httpclient "github.com/neckchi/schedulehub/internal/http"
 	"github.com/neckchi/schedulehub/internal/schema"
@@ -10,6 +11,9 @@ import (
 	"time"
 )
 
+// ErrLocationUnavailable is returned when the carrier does not know the port of the query, so the schedule was never requested
+var ErrLocationUnavailable = errors.New("location is unavailable")
+
 type HeaderParams struct {
 	Headers map[string]string
 	Params  map[string]string
@@ -56,6 +60,37 @@ type ScheduleService[T ScheduleOutputType, Q any] struct {
 }
 
 func (ss *ScheduleService[T, Q]) FetchSchedule(ctx context.Context, c *httpclient.HttpClient, e *env.Manager, querySchema Q, scac schema.CarrierCode) (T, error) {
+	headerParams, err := ss.headerParams(ctx, c, e, querySchema, scac)
+	if err != nil {
+		return nil, err
+	}
+	if headerParams.Headers != nil {
+		responseJson, err := c.Fetch(ctx, ss.ScheduleConfig.Method, &ss.ScheduleConfig.ScheduleURL, &headerParams.Params, &headerParams.Headers, ss.ScheduleConfig.Namespace, ss.ScheduleConfig.ScheduleExpiry)
+		// The token might have been revoked before it expired. Drop it and try once more with a new one
+		if tokenProvider, ok := ss.Token.(*OAuth2); ok && tokenProvider != nil && errors.Is(err, httpclient.ErrUnauthorized) {
+			log.Warnf("%s rejected the token, requesting a new one", tokenProvider.Namespace)
+			InvalidateToken(tokenProvider.Namespace)
+			headerParams, err = ss.headerParams(ctx, c, e, querySchema, scac)
+			if err != nil {
+				return nil, err
+			}
+			responseJson, err = c.Fetch(ctx, ss.ScheduleConfig.Method, &ss.ScheduleConfig.ScheduleURL, &headerParams.Params, &headerParams.Headers, ss.ScheduleConfig.Namespace, ss.ScheduleConfig.ScheduleExpiry)
+		}
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		finalSchedule, err := ss.ScheduleProvider.GenerateSchedule(responseJson)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		return finalSchedule, nil
+	}
+	return nil, nil
+}
+
+func (ss *ScheduleService[T, Q]) headerParams(ctx context.Context, c *httpclient.HttpClient, e *env.Manager, querySchema Q, scac schema.CarrierCode) (HeaderParams, error) {
 	var headerParams HeaderParams
 
 	switch {
@@ -65,7 +100,7 @@ func (ss *ScheduleService[T, Q]) FetchSchedule(ctx context.Context, c *httpclien
 	}():
 		tokenData, err := GetToken(ss.Token, ctx, c, e)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get auth token: %w", err)
+			return headerParams, fmt.Errorf("failed to get auth token: %w", err)
 		}
 		token := &TokenResponse{Data: tokenData}
 		arguments := &ScheduleArgs[Q]{Token: token, Env: e, Query: querySchema}
@@ -76,32 +111,24 @@ func (ss *ScheduleService[T, Q]) FetchSchedule(ctx context.Context, c *httpclien
 		return location != nil
 	}():
 		if queryLocation, ok := any(querySchema).(*schema.QueryParams); ok {
-			pol, _ := ss.Location.GetLocationDetails(ctx, c, e, queryLocation.PointFrom)
-			pod, _ := ss.Location.GetLocationDetails(ctx, c, e, queryLocation.PointTo)
-			if pod == nil || pol == nil {
-				log.Info("Either POL or POD is unavailable ")
-				break
-			} else {
-				arguments := &ScheduleArgs[Q]{Scac: scac, Env: e, Query: querySchema, Origin: pol, Destination: pod}
-				headerParams = ss.ScheduleProvider.ScheduleHeaderParams(arguments)
+			pol, err := ss.Location.GetLocationDetails(ctx, c, e, queryLocation.PointFrom)
+			if err != nil {
+				return headerParams, fmt.Errorf("failed to look up %s: %w", queryLocation.PointFrom, err)
+			}
+			pod, err := ss.Location.GetLocationDetails(ctx, c, e, queryLocation.PointTo)
+			if err != nil {
+				return headerParams, fmt.Errorf("failed to look up %s: %w", queryLocation.PointTo, err)
 			}
+			if len(pol) == 0 || len(pod) == 0 {
+				log.Infof("Either %s or %s is unavailable", queryLocation.PointFrom, queryLocation.PointTo)
+				return headerParams, fmt.Errorf("%s-%s: %w", queryLocation.PointFrom, queryLocation.PointTo, ErrLocationUnavailable)
+			}
+			arguments := &ScheduleArgs[Q]{Scac: scac, Env: e, Query: querySchema, Origin: pol, Destination: pod}
+			headerParams = ss.ScheduleProvider.ScheduleHeaderParams(arguments)
 		}
 	default:
 		arguments := &ScheduleArgs[Q]{Scac: scac, Env: e, Query: querySchema}
 		headerParams = ss.ScheduleProvider.ScheduleHeaderParams(arguments)
 	}
-	if headerParams.Headers != nil {
-		responseJson, err := c.Fetch(ctx, ss.ScheduleConfig.Method, &ss.ScheduleConfig.ScheduleURL, &headerParams.Params, &headerParams.Headers, ss.ScheduleConfig.Namespace, ss.ScheduleConfig.ScheduleExpiry)
-		if err != nil {
-			log.Error(err)
-			return nil, err
-		}
-		finalSchedule, err := ss.ScheduleProvider.GenerateSchedule(responseJson)
-		if err != nil {
-			log.Error(err)
-			return nil, err
-		}
-		return finalSchedule, nil
-	}
-	return nil, nil
+	return headerParams, nil
 }
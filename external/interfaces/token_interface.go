@@ -2,7 +2,6 @@ package interfaces
 
 import (
 	"context"
-	"encoding/json"
 	httpclient "github.com/neckchi/schedulehub/internal/http"
 	env "github.com/neckchi/schedulehub/internal/secret"
 	"time"
@@ -28,21 +27,13 @@ type OAuth2 struct {
 	TokenUrl    string
 	Method      string
 	Secrets     TokenProvider
-	TokenExpiry time.Duration
+	TokenExpiry time.Duration // used when the carrier does not return expires_in
 	Namespace   string
 }
 
+// GetOAuthToken hands out the token kept by the token manager, requesting a new one only when there is none or it has expired
 func (o *OAuth2) GetOAuthToken(ctx context.Context, c *httpclient.HttpClient, e *env.Manager) (map[string]any, error) {
-	headerParams := o.Secrets.TokenHeaderParams(e)
-	responseJson, err := c.Fetch(ctx, o.Method, &o.TokenUrl, &headerParams.Params, &headerParams.Headers, o.Namespace, o.TokenExpiry)
-	if err != nil {
-		return nil, err
-	}
-	var tokenResponse map[string]any
-	if err := json.Unmarshal(responseJson, &tokenResponse); err != nil {
-		return nil, err
-	}
-	return tokenResponse, nil
+	return tokens.Token(ctx, o, c, e)
 }
 
 func GetToken(t Token, ctx context.Context, client *httpclient.HttpClient, e *env.Manager) (map[string]any, error) {
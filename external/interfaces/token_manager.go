@@ -0,0 +1,201 @@
+package interfaces
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	httpclient "github.com/neckchi/schedulehub/internal/http"
+	env "github.com/neckchi/schedulehub/internal/secret"
+	log "github.com/sirupsen/logrus"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// tokenRefreshMargin is how long before the expiry the token gets refreshed in the background
+	tokenRefreshMargin  = 5 * time.Minute
+	tokenRefreshTimeout = 30 * time.Second
+)
+
+type managedToken struct {
+	data      map[string]any
+	expiresAt time.Time
+	used      bool
+	timer     *time.Timer
+}
+
+// TokenManager keeps one token per carrier namespace in memory. The token lives as long as the carrier says(expires_in) and is
+// refreshed in the background shortly before it expires, as long as it has been used since the last refresh.
+type TokenManager struct {
+	mu     sync.Mutex
+	tokens map[string]*managedToken
+	locks  map[string]*sync.Mutex
+}
+
+var tokens = NewTokenManager()
+
+func NewTokenManager() *TokenManager {
+	return &TokenManager{tokens: make(map[string]*managedToken), locks: make(map[string]*sync.Mutex)}
+}
+
+// InvalidateToken drops the token of the carrier, e.g. after the carrier rejected it with 401, so the next call fetches a new one
+func InvalidateToken(namespace string) {
+	tokens.Invalidate(namespace)
+}
+
+// namespaceLock makes sure only one token request per carrier is in flight
+func (tm *TokenManager) namespaceLock(namespace string) *sync.Mutex {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	lock, ok := tm.locks[namespace]
+	if !ok {
+		lock = &sync.Mutex{}
+		tm.locks[namespace] = lock
+	}
+	return lock
+}
+
+func (tm *TokenManager) lookup(namespace string) (*managedToken, bool) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	token, ok := tm.tokens[namespace]
+	return token, ok
+}
+
+func (tm *TokenManager) Token(ctx context.Context, o *OAuth2, c *httpclient.HttpClient, e *env.Manager) (map[string]any, error) {
+	lock := tm.namespaceLock(o.Namespace)
+	lock.Lock()
+	defer lock.Unlock()
+	current, ok := tm.lookup(o.Namespace)
+	if ok && time.Now().Before(current.expiresAt) {
+		tm.mu.Lock()
+		current.used = true
+		tm.mu.Unlock()
+		return current.data, nil
+	}
+	token, err := tm.fetch(ctx, o, c, e, current)
+	if err != nil {
+		return nil, err
+	}
+	tm.mu.Lock()
+	token.used = true
+	tm.mu.Unlock()
+	return token.data, nil
+}
+
+func (tm *TokenManager) Invalidate(namespace string) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if token, ok := tm.tokens[namespace]; ok {
+		token.timer.Stop()
+		delete(tm.tokens, namespace)
+	}
+}
+
+// fetch asks the carrier for a new token. The refresh token of the previous one is tried first when the carrier issued one
+func (tm *TokenManager) fetch(ctx context.Context, o *OAuth2, c *httpclient.HttpClient, e *env.Manager, previous *managedToken) (*managedToken, error) {
+	var tokenData map[string]any
+	var err error
+	if refreshToken := stringClaim(previous, "refresh_token"); refreshToken != "" {
+		tokenData, err = o.requestToken(ctx, c, e, refreshToken)
+		if err != nil {
+			log.Warnf("Failed to refresh %s with the refresh token, requesting a new one: %v", o.Namespace, err)
+		}
+	}
+	if tokenData == nil {
+		tokenData, err = o.requestToken(ctx, c, e, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Some carriers only return the refresh token on the first grant
+	if _, ok := tokenData["refresh_token"]; !ok && previous != nil {
+		if refreshToken := stringClaim(previous, "refresh_token"); refreshToken != "" {
+			tokenData["refresh_token"] = refreshToken
+		}
+	}
+	lifetime := expiresIn(tokenData, o.TokenExpiry)
+	token := &managedToken{data: tokenData, expiresAt: time.Now().Add(lifetime)}
+	refreshIn := max(lifetime-tokenRefreshMargin, lifetime/2)
+	token.timer = time.AfterFunc(refreshIn, func() { tm.refresh(o, c, e, token) })
+
+	tm.mu.Lock()
+	if previous != nil {
+		previous.timer.Stop()
+	}
+	tm.tokens[o.Namespace] = token
+	tm.mu.Unlock()
+	return token, nil
+}
+
+// refresh runs in the background before the token expires. An idle carrier is left alone so that its token simply runs out
+func (tm *TokenManager) refresh(o *OAuth2, c *httpclient.HttpClient, e *env.Manager, token *managedToken) {
+	lock := tm.namespaceLock(o.Namespace)
+	lock.Lock()
+	defer lock.Unlock()
+	current, ok := tm.lookup(o.Namespace)
+	if !ok || current != token {
+		return
+	}
+	tm.mu.Lock()
+	used := token.used
+	tm.mu.Unlock()
+	if !used {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), tokenRefreshTimeout)
+	defer cancel()
+	if _, err := tm.fetch(ctx, o, c, e, token); err != nil {
+		log.Errorf("Failed to refresh %s in the background: %v", o.Namespace, err)
+	}
+}
+
+func stringClaim(token *managedToken, key string) string {
+	if token == nil {
+		return ""
+	}
+	value, _ := token.data[key].(string)
+	return value
+}
+
+// expiresIn reads the token lifetime returned by the carrier and falls back to the configured one
+func expiresIn(tokenData map[string]any, fallback time.Duration) time.Duration {
+	var seconds float64
+	switch v := tokenData["expires_in"].(type) {
+	case float64:
+		seconds = v
+	case string:
+		seconds, _ = strconv.ParseFloat(v, 64)
+	}
+	if seconds <= 0 {
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (o *OAuth2) requestToken(ctx context.Context, c *httpclient.HttpClient, e *env.Manager, refreshToken string) (map[string]any, error) {
+	headerParams := o.Secrets.TokenHeaderParams(e)
+	if refreshToken != "" {
+		params := make(map[string]string, len(headerParams.Params)+1)
+		for k, v := range headerParams.Params {
+			params[k] = v
+		}
+		params["grant_type"] = "refresh_token"
+		params["refresh_token"] = refreshToken
+		headerParams.Params = params
+	}
+	// The token is kept by the token manager so it must not end up in the redis cache
+	responseJson, err := c.Fetch(ctx, o.Method, &o.TokenUrl, &headerParams.Params, &headerParams.Headers, o.Namespace, 0)
+	if err != nil {
+		return nil, err
+	}
+	var tokenResponse map[string]any
+	if err := json.Unmarshal(responseJson, &tokenResponse); err != nil {
+		return nil, err
+	}
+	if _, ok := tokenResponse["access_token"].(string); !ok {
+		return nil, fmt.Errorf("%s response has no access token", o.Namespace)
+	}
+	return tokenResponse, nil
+}
@@ -0,0 +1,216 @@
+package external
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/neckchi/schedulehub/internal/schema"
+	"time"
+	_ "time/tzdata" // the zones of the ports are resolved without relying on the zoneinfo of the host
+)
+
+const (
+	localLayout = "2006-01-02T15:04:05"
+	UTCLayout   = "2006-01-02T15:04:05Z"
+)
+
+// portTimeZones are the zones of the ports lying in a country spanning several zones or handling most of the volume
+var portTimeZones = map[string]string{
+	// North America
+	"USNYC": "America/New_York", "USORF": "America/New_York", "USSAV": "America/New_York", "USCHS": "America/New_York",
+	"USBAL": "America/New_York", "USPHL": "America/New_York", "USBOS": "America/New_York", "USJAX": "America/New_York",
+	"USMIA": "America/New_York", "USPEF": "America/New_York", "USWIL": "America/New_York", "USILM": "America/New_York",
+	"USCHI": "America/Chicago", "USHOU": "America/Chicago", "USMSY": "America/Chicago", "USMOB": "America/Chicago",
+	"USMEM": "America/Chicago", "USKCK": "America/Chicago", "USDAL": "America/Chicago", "USDEN": "America/Denver",
+	"USSLC": "America/Denver", "USPHX": "America/Phoenix", "USLAX": "America/Los_Angeles", "USLGB": "America/Los_Angeles",
+	"USOAK": "America/Los_Angeles", "USSEA": "America/Los_Angeles", "USTIW": "America/Los_Angeles", "USPDX": "America/Los_Angeles",
+	"USANC": "America/Anchorage", "USHNL": "Pacific/Honolulu", "CAVAN": "America/Vancouver", "CAPRR": "America/Vancouver",
+	"CAMTR": "America/Toronto", "CATOR": "America/Toronto", "CAHAL": "America/Halifax", "CASJB": "America/Moncton",
+	"CAYYC": "America/Edmonton", "CAWNP": "America/Winnipeg", "MXZLO": "America/Mexico_City", "MXLZC": "America/Mexico_City",
+	"MXVER": "America/Mexico_City", "MXATM": "America/Mexico_City", "MXMEX": "America/Mexico_City", "MXESE": "America/Tijuana",
+	"MXTIJ": "America/Tijuana",
+	// South America
+	"BRSSZ": "America/Sao_Paulo", "BRRIG": "America/Sao_Paulo", "BRPNG": "America/Sao_Paulo", "BRITJ": "America/Sao_Paulo",
+	"BRNVT": "America/Sao_Paulo", "BRRIO": "America/Sao_Paulo", "BRIOA": "America/Sao_Paulo", "BRSSA": "America/Bahia",
+	"BRSUA": "America/Recife", "BRPEC": "America/Recife", "BRFOR": "America/Fortaleza", "BRMAO": "America/Manaus",
+	"BRVDC": "America/Belem", "CLSAI": "America/Santiago", "CLVAP": "America/Santiago", "CLSVE": "America/Santiago",
+	"ECGYE": "America/Guayaquil", "ARBUE": "America/Argentina/Buenos_Aires",
+	// Europe, Africa and the Middle East
+	"ESALG": "Europe/Madrid", "ESVLC": "Europe/Madrid", "ESBCN": "Europe/Madrid", "ESBIO": "Europe/Madrid",
+	"ESLPA": "Atlantic/Canary", "ESSCT": "Atlantic/Canary", "PTLIS": "Europe/Lisbon", "PTSIE": "Europe/Lisbon",
+	"PTLEI": "Europe/Lisbon", "PTFNC": "Atlantic/Madeira", "PTPDL": "Atlantic/Azores", "RULED": "Europe/Moscow",
+	"RUNVS": "Europe/Moscow", "RUKGD": "Europe/Kaliningrad", "RUVVO": "Asia/Vladivostok", "RUVYP": "Asia/Vladivostok",
+	"RUNJK": "Asia/Vladivostok", "TRIST": "Europe/Istanbul", "TRMER": "Europe/Istanbul", "UAODS": "Europe/Kyiv",
+	"EGPSD": "Africa/Cairo", "EGALY": "Africa/Cairo", "EGSOK": "Africa/Cairo", "MAPTM": "Africa/Casablanca",
+	"MACAS": "Africa/Casablanca", "ZADUR": "Africa/Johannesburg", "ZACPT": "Africa/Johannesburg", "ZAPLZ": "Africa/Johannesburg",
+	"CDMAT": "Africa/Kinshasa", "CDFIH": "Africa/Kinshasa", "CDLUB": "Africa/Lubumbashi", "AEJEA": "Asia/Dubai",
+	"AEAUH": "Asia/Dubai", "AEKHL": "Asia/Dubai", "SAJED": "Asia/Riyadh", "SADMM": "Asia/Riyadh", "OMSLL": "Asia/Muscat",
+	// Asia and Oceania
+	"CNSHA": "Asia/Shanghai", "CNNGB": "Asia/Shanghai", "CNSZX": "Asia/Shanghai", "CNYTN": "Asia/Shanghai",
+	"CNCAN": "Asia/Shanghai", "CNTAO": "Asia/Shanghai", "CNTSN": "Asia/Shanghai", "CNXMN": "Asia/Shanghai",
+	"CNDLC": "Asia/Shanghai", "HKHKG": "Asia/Hong_Kong", "TWKHH": "Asia/Taipei", "SGSIN": "Asia/Singapore",
+	"MYPKG": "Asia/Kuala_Lumpur", "MYTPP": "Asia/Kuala_Lumpur", "IDJKT": "Asia/Jakarta", "IDSUB": "Asia/Jakarta",
+	"IDSRG": "Asia/Jakarta", "IDBLW": "Asia/Jakarta", "IDMAK": "Asia/Makassar", "IDBPN": "Asia/Makassar",
+	"IDJAP": "Asia/Jayapura", "LKCMB": "Asia/Colombo", "INNSA": "Asia/Kolkata", "INMUN": "Asia/Kolkata",
+	"INMAA": "Asia/Kolkata", "PKKHI": "Asia/Karachi", "VNSGN": "Asia/Ho_Chi_Minh", "VNHPH": "Asia/Ho_Chi_Minh",
+	"THLCH": "Asia/Bangkok", "THBKK": "Asia/Bangkok", "KRPUS": "Asia/Seoul", "JPTYO": "Asia/Tokyo", "JPYOK": "Asia/Tokyo",
+	"JPUKB": "Asia/Tokyo", "JPNGO": "Asia/Tokyo", "AUSYD": "Australia/Sydney", "AUMEL": "Australia/Melbourne",
+	"AUBNE": "Australia/Brisbane", "AUADL": "Australia/Adelaide", "AUFRE": "Australia/Perth", "AUDRW": "Australia/Darwin",
+	"NZAKL": "Pacific/Auckland", "NZTRG": "Pacific/Auckland",
+}
+
+// countryTimeZones are the countries observing a single zone, the ports missing in portTimeZones fall back on them
+var countryTimeZones = map[string]string{
+	"AE": "Asia/Dubai", "AT": "Europe/Vienna", "BD": "Asia/Dhaka", "BE": "Europe/Brussels", "BG": "Europe/Sofia",
+	"BH": "Asia/Bahrain", "CH": "Europe/Zurich", "CN": "Asia/Shanghai", "CO": "America/Bogota", "CZ": "Europe/Prague",
+	"DE": "Europe/Berlin", "DJ": "Africa/Djibouti", "DK": "Europe/Copenhagen", "DZ": "Africa/Algiers", "EE": "Europe/Tallinn",
+	"EG": "Africa/Cairo", "FI": "Europe/Helsinki", "FR": "Europe/Paris", "GB": "Europe/London", "GH": "Africa/Accra",
+	"GR": "Europe/Athens", "GT": "America/Guatemala", "HK": "Asia/Hong_Kong", "HR": "Europe/Zagreb", "HU": "Europe/Budapest",
+	"IE": "Europe/Dublin", "IL": "Asia/Jerusalem", "IN": "Asia/Kolkata", "IQ": "Asia/Baghdad", "IR": "Asia/Tehran",
+	"IS": "Atlantic/Reykjavik", "IT": "Europe/Rome", "JM": "America/Jamaica", "JO": "Asia/Amman", "JP": "Asia/Tokyo",
+	"KE": "Africa/Nairobi", "KH": "Asia/Phnom_Penh", "KR": "Asia/Seoul", "KW": "Asia/Kuwait", "LB": "Asia/Beirut",
+	"LK": "Asia/Colombo", "LT": "Europe/Vilnius", "LV": "Europe/Riga", "LY": "Africa/Tripoli", "MA": "Africa/Casablanca",
+	"MM": "Asia/Yangon", "MT": "Europe/Malta", "MU": "Indian/Mauritius", "MY": "Asia/Kuala_Lumpur", "NG": "Africa/Lagos",
+	"NL": "Europe/Amsterdam", "NO": "Europe/Oslo", "OM": "Asia/Muscat", "PA": "America/Panama", "PE": "America/Lima",
+	"PH": "Asia/Manila", "PK": "Asia/Karachi", "PL": "Europe/Warsaw", "QA": "Asia/Qatar", "RO": "Europe/Bucharest",
+	"SA": "Asia/Riyadh", "SE": "Europe/Stockholm", "SG": "Asia/Singapore", "SI": "Europe/Ljubljana", "SN": "Africa/Dakar",
+	"TH": "Asia/Bangkok", "TN": "Africa/Tunis", "TR": "Europe/Istanbul", "TW": "Asia/Taipei", "TZ": "Africa/Dar_es_Salaam",
+	"UY": "America/Montevideo", "VN": "Asia/Ho_Chi_Minh", "ZA": "Africa/Johannesburg",
+}
+
+// PortTimeZone looks up the IANA zone of the UN/LOCODE, it is empty when the port is unknown
+func PortTimeZone(locode string) string {
+	if zone, ok := portTimeZones[locode]; ok {
+		return zone
+	}
+	if len(locode) == 5 {
+		return countryTimeZones[locode[:2]]
+	}
+	return ""
+}
+
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	return fmt.Sprintf("%s%02d:%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+// DateOffset keeps the UTC offset of a date whose layout carries one before ConvertDateFormat drops it
+func DateOffset(date, layout string) string {
+	parsedTime, err := time.Parse(layout, date)
+	if err != nil {
+		return ""
+	}
+	_, seconds := parsedTime.Zone()
+	return formatOffset(seconds)
+}
+
+// ConvertUTCDate formats the date of a layout carrying an offset(or the GMT date of the carriers) as a UTC time
+func ConvertUTCDate(date, layout string) string {
+	parsedTime, err := time.Parse(layout, date)
+	if err != nil {
+		return ""
+	}
+	return parsedTime.UTC().Format(UTCLayout)
+}
+
+// LocalToUTC converts the local time of the port into UTC
+func LocalToUTC(local, zone string) string {
+	location := schema.ZoneLocation(zone)
+	if location == nil {
+		return ""
+	}
+	localTime, err := time.ParseInLocation(localLayout, local, location)
+	if err != nil {
+		return ""
+	}
+	return localTime.UTC().Format(UTCLayout)
+}
+
+// utcOffset derives the offset of the port from the local and the UTC time of the same event
+func utcOffset(local, utc string) string {
+	localTime, err1 := time.Parse(localLayout, local)
+	utcTime, err2 := time.Parse(UTCLayout, utc)
+	if err1 != nil || err2 != nil {
+		return ""
+	}
+	return formatOffset(int(localTime.Sub(utcTime).Seconds()))
+}
+
+// CalculateUTCTransitTime is CalculateTransitTime on the UTC times so that the zones crossed by the sailing do not count
+func CalculateUTCTransitTime(etd, eta string) (int, bool) {
+	etdTime, err1 := time.Parse(UTCLayout, etd)
+	etaTime, err2 := time.Parse(UTCLayout, eta)
+	if err1 != nil || err2 != nil {
+		return 0, false
+	}
+	return int(etaTime.Sub(etdTime).Hours() / 24), true
+}
+
+// applyEventZone completes the zone of the point and the UTC time of its event out of each other or of the port table
+func applyEventZone(point *schema.PointBase, local string, utc *string) {
+	if point == nil || local == "" {
+		return
+	}
+	if point.TimeZone == "" && *utc == "" {
+		point.TimeZone = PortTimeZone(point.LocationCode)
+	}
+	switch {
+	case *utc == "":
+		*utc = LocalToUTC(local, point.TimeZone)
+	case point.TimeZone == "":
+		point.TimeZone = utcOffset(local, *utc)
+	}
+}
+
+// ApplyTimeZones puts the zone on every point of the schedule and the UTC time next to every local ETD/ETA. The transit times are
+// then counted on the UTC times. It runs once per carrier answer, so the carriers only fill what they know(an offset, a zone or GMT)
+func ApplyTimeZones(schedule *schema.P2PSchedule) {
+	if schedule == nil {
+		return
+	}
+	for _, leg := range schedule.Legs {
+		applyEventZone(leg.PointFrom, leg.Etd, &leg.EtdUTC)
+		applyEventZone(leg.PointTo, leg.Eta, &leg.EtaUTC)
+		if transitTime, ok := CalculateUTCTransitTime(leg.EtdUTC, leg.EtaUTC); ok {
+			leg.TransitTime = transitTime
+		}
+	}
+	if len(schedule.Legs) > 0 {
+		first, last := schedule.Legs[0], schedule.Legs[len(schedule.Legs)-1]
+		if schedule.EtdUTC == "" && first.Etd == schedule.Etd {
+			schedule.EtdUTC = first.EtdUTC
+		}
+		if schedule.EtaUTC == "" && last.Eta == schedule.Eta {
+			schedule.EtaUTC = last.EtaUTC
+		}
+	}
+	schedule.EtdUTC = cmp.Or(schedule.EtdUTC, LocalToUTC(schedule.Etd, PortTimeZone(schedule.PointFrom)))
+	schedule.EtaUTC = cmp.Or(schedule.EtaUTC, LocalToUTC(schedule.Eta, PortTimeZone(schedule.PointTo)))
+	if transitTime, ok := CalculateUTCTransitTime(schedule.EtdUTC, schedule.EtaUTC); ok {
+		schedule.TransitTime = transitTime
+	}
+}
+
+// ApplyPortCallTimeZones does the same for the estimated and actual times of the vessel calls
+func ApplyPortCallTimeZones(schedule *schema.MasterVesselSchedule) {
+	if schedule == nil {
+		return
+	}
+	for i := range schedule.Calls {
+		call := &schedule.Calls[i]
+		if call.Port == nil {
+			continue
+		}
+		if call.Port.TimeZone == "" {
+			call.Port.TimeZone = PortTimeZone(call.Port.PortCode)
+		}
+		if call.EstimatedEventDate != "" && call.EstimatedEventDateUTC == "" {
+			call.EstimatedEventDateUTC = LocalToUTC(call.EstimatedEventDate, call.Port.TimeZone)
+		}
+		if call.ActualEventDate != "" && call.ActualEventDateUTC == "" {
+			call.ActualEventDateUTC = LocalToUTC(call.ActualEventDate, call.Port.TimeZone)
+		}
+	}
+}
@@ -15,6 +15,11 @@ type RedisRepository interface {
 	Get(namespace, key string) ([]byte, bool)
 	AddToChannel(namespace, key string, value []byte, expiry time.Duration)
 	Set(watchKey string) error
+	Increment(namespace, key string, expiry time.Duration) (int64, error)
+	Put(namespace string, entries map[string][]byte, expiry time.Duration) error
+	AddMembers(namespace, key string, members []string, expiry time.Duration) error
+	Members(namespace, key string) ([]string, bool)
+	RemoveMembers(namespace, key string, members ...string) error
 }
 
 type RedisSettings struct {
@@ -148,3 +153,74 @@ func (r *RedisConnection) Get(namespace, key string) ([]byte, bool) {
 	log.Infof("Background Task: %s with key: %s exist", namespace, hashKey)
 	return storedValue, true
 }
+
+// Increment bumps the counter stored under the key and refreshes its expiry. it is used as a generation number to invalidate derived cache
+func (r *RedisConnection) Increment(namespace, key string, expiry time.Duration) (int64, error) {
+	hashKey := GenerateUUIDFromString(namespace, key)
+	pipe := r.client.TxPipeline()
+	counter := pipe.Incr(r.ctx, hashKey)
+	pipe.Expire(r.ctx, hashKey, expiry)
+	if _, err := pipe.Exec(r.ctx); err != nil {
+		log.Errorf("error incrementing %s for %v: %v", hashKey, namespace, err)
+		return 0, err
+	}
+	return counter.Val(), nil
+}
+
+// Put writes the entries straight away and overwrites the existing ones, unlike AddToChannel which never replaces a cached value
+func (r *RedisConnection) Put(namespace string, entries map[string][]byte, expiry time.Duration) error {
+	pipe := r.client.TxPipeline()
+	for key, value := range entries {
+		pipe.Set(r.ctx, GenerateUUIDFromString(namespace, key), value, expiry)
+	}
+	if _, err := pipe.Exec(r.ctx); err != nil {
+		log.Errorf("error caching %d entries for %v: %v", len(entries), namespace, err)
+		return err
+	}
+	log.Infof("Background Task: Successfully cached %d entries for %v", len(entries), namespace)
+	return nil
+}
+
+// AddMembers adds to the set stored under the key and refreshes its expiry. SADD is atomic so concurrent writers never drop each
+// other's members like a read-modify-write through Put would
+func (r *RedisConnection) AddMembers(namespace, key string, members []string, expiry time.Duration) error {
+	hashKey := GenerateUUIDFromString(namespace, key)
+	values := make([]interface{}, 0, len(members))
+	for _, member := range members {
+		values = append(values, member)
+	}
+	pipe := r.client.TxPipeline()
+	pipe.SAdd(r.ctx, hashKey, values...)
+	pipe.Expire(r.ctx, hashKey, expiry)
+	if _, err := pipe.Exec(r.ctx); err != nil {
+		log.Errorf("error adding members to %s for %v: %v", hashKey, namespace, err)
+		return err
+	}
+	return nil
+}
+
+func (r *RedisConnection) Members(namespace, key string) ([]string, bool) {
+	hashKey := GenerateUUIDFromString(namespace, key)
+	members, err := r.client.SMembers(r.ctx, hashKey).Result()
+	if err != nil {
+		log.Errorf("error getting members of %s for %v: %v", hashKey, namespace, err)
+		return nil, false
+	}
+	return members, len(members) > 0
+}
+
+func (r *RedisConnection) RemoveMembers(namespace, key string, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+	hashKey := GenerateUUIDFromString(namespace, key)
+	values := make([]interface{}, 0, len(members))
+	for _, member := range members {
+		values = append(values, member)
+	}
+	if err := r.client.SRem(r.ctx, hashKey, values...).Err(); err != nil {
+		log.Errorf("error removing members of %s for %v: %v", hashKey, namespace, err)
+		return err
+	}
+	return nil
+}
@@ -88,6 +88,10 @@ var (
 		log.Error(err)
 		writeError(w, []error{err}, SeverityError, http.StatusBadRequest)
 	}
+	NotFoundErrorHandler = func(w http.ResponseWriter, err error) {
+		log.Error(err)
+		writeError(w, []error{err}, SeverityWarning, http.StatusNotFound)
+	}
 	InternalErrorHandler = func(w http.ResponseWriter, err error) {
 		log.Error(err)
 		writeError(w, []error{err}, SeverityError, http.StatusInternalServerError)
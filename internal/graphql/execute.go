@@ -0,0 +1,365 @@
+package graphql
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Request is the body of a POST as sent by the GraphQL clients
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Error is a field error, the path points at the field that resolved to null
+type Error struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
+
+type Response struct {
+	Data   any      `json:"data"`
+	Errors []*Error `json:"errors,omitempty"`
+}
+
+// orderedMap keeps the fields in the order of the selection set like the spec asks
+type orderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+func (m *orderedMap) set(key string, value any) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		value, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// defaultConcurrency bounds the goroutines of a query when the schema leaves MaxConcurrency out
+const defaultConcurrency = 16
+
+type executor struct {
+	schema    *Schema
+	doc       *Document
+	variables map[string]any
+	slots     chan struct{}
+	mu        sync.Mutex
+	errors    []*Error
+}
+
+// Do parses, validates and runs the query. The returned error is a request error(syntax, unknown field, bad variable) and
+// nothing has been resolved, the errors of the resolvers end up in the response next to the partial data
+func Do(ctx context.Context, schema *Schema, req Request) (*Response, error) {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return nil, err
+	}
+	operation, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return nil, err
+	}
+	variables, defined, err := coerceVariables(operation, req.Variables)
+	if err != nil {
+		return nil, err
+	}
+	v := &validator{schema: schema, doc: doc, variables: variables, defined: defined}
+	if err := v.operation(operation); err != nil {
+		return nil, err
+	}
+	e := &executor{schema: schema, doc: doc, variables: variables, slots: make(chan struct{}, cmp.Or(schema.MaxConcurrency, defaultConcurrency))}
+	data, _ := e.selectionSet(ctx, schema.Query, nil, nil, nil, operation.SelectionSet, true)
+	response := &Response{Errors: e.errors}
+	if data != nil {
+		response.Data = data
+	}
+	return response, nil
+}
+
+func selectOperation(doc *Document, name string) (*Operation, error) {
+	if name == "" {
+		if len(doc.Operations) > 1 {
+			return nil, fmt.Errorf("operationName is required when the document has several operations")
+		}
+		return doc.Operations[0], nil
+	}
+	for _, operation := range doc.Operations {
+		if operation.Name == name {
+			return operation, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+// coerceVariables applies the defaults and types of the definitions on the variables sent
+func coerceVariables(operation *Operation, values map[string]any) (map[string]any, map[string]bool, error) {
+	variables := make(map[string]any, len(operation.Variables))
+	defined := make(map[string]bool, len(operation.Variables))
+	for _, definition := range operation.Variables {
+		if defined[definition.Name] {
+			return nil, nil, fmt.Errorf("there can be only one variable named \"$%s\"", definition.Name)
+		}
+		defined[definition.Name] = true
+		t, err := typeFromRef(definition.Type)
+		if err != nil {
+			return nil, nil, fmt.Errorf("variable \"$%s\": %w", definition.Name, err)
+		}
+		value, ok := values[definition.Name]
+		if !ok && definition.Default != nil {
+			value, ok = definition.Default, true
+		}
+		if !ok {
+			if definition.Type.NonNull {
+				return nil, nil, fmt.Errorf("variable \"$%s\" of type %s is required", definition.Name, t)
+			}
+			continue
+		}
+		coerced, err := coerceInput(t, value, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("variable \"$%s\": %w", definition.Name, err)
+		}
+		variables[definition.Name] = coerced
+	}
+	return variables, defined, nil
+}
+
+// spawn runs the resolution on its own goroutine while a slot is free and inline otherwise. The caller holding a slot may wait for
+// the work it spawns, so blocking on a slot could leave every holder waiting on the others
+func (e *executor) spawn(wg *sync.WaitGroup, run func()) {
+	select {
+	case e.slots <- struct{}{}:
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-e.slots
+				wg.Done()
+			}()
+			run()
+		}()
+	default:
+		run()
+	}
+}
+
+func (e *executor) fail(path []any, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.errors = append(e.errors, &Error{Message: err.Error(), Path: path})
+}
+
+// collectFields flattens the fragments and groups the fields per response key, the same key selected twice merges its subfields
+func (e *executor) collectFields(selections []Selection, keys *[]string, fields map[string][]*FieldSelection) {
+	for _, selection := range selections {
+		if ok, _ := included(selection.directives(), e.variables); !ok {
+			continue
+		}
+		switch s := selection.(type) {
+		case *FieldSelection:
+			key := s.ResponseKey()
+			if _, ok := fields[key]; !ok {
+				*keys = append(*keys, key)
+			}
+			fields[key] = append(fields[key], s)
+		case *InlineFragment:
+			e.collectFields(s.SelectionSet, keys, fields)
+		case *FragmentSpread:
+			e.collectFields(e.doc.Fragments[s.Name].SelectionSet, keys, fields)
+		}
+	}
+}
+
+// selectionSet resolves the fields of the object. The root fields run concurrently(e.g. a p2p search next to a vessel voyage) as
+// far as the slots allow, the nested ones in order. A nil result means a non-null field was null and the object itself turns into null
+func (e *executor) selectionSet(ctx context.Context, o *Object, source any, parents []any, path []any, selections []Selection, concurrent bool) (*orderedMap, bool) {
+	var keys []string
+	fields := make(map[string][]*FieldSelection)
+	e.collectFields(selections, &keys, fields)
+	values := make([]any, len(keys))
+	valid := make([]bool, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		resolve := func() {
+			values[i], valid[i] = e.field(ctx, o, source, parents, appendPath(path, key), fields[key])
+		}
+		if !concurrent {
+			resolve()
+			continue
+		}
+		e.spawn(&wg, resolve)
+	}
+	wg.Wait()
+	result := &orderedMap{values: make(map[string]any, len(keys))}
+	for i, key := range keys {
+		if !valid[i] {
+			return nil, false
+		}
+		result.set(key, values[i])
+	}
+	return result, true
+}
+
+func appendPath(path []any, key any) []any {
+	return append(append(make([]any, 0, len(path)+1), path...), key)
+}
+
+// field resolves one response key, it reports false when the null has to bubble up to the parent
+func (e *executor) field(ctx context.Context, o *Object, source any, parents []any, path []any, selections []*FieldSelection) (any, bool) {
+	s := selections[0]
+	if s.Name == "__typename" {
+		return o.Name, true
+	}
+	f := o.Fields[s.Name]
+	args, err := coerceArguments(f.Args, s.Arguments, e.variables)
+	if err != nil {
+		e.fail(path, err)
+		return e.null(f.Type)
+	}
+	var value any
+	if f.Resolve != nil {
+		value, err = e.resolve(f, ResolveParams{Context: ctx, Source: source, Args: args, Parents: parents}, path)
+		if err != nil {
+			e.fail(path, err)
+			return e.null(f.Type)
+		}
+	}
+	var subfields []Selection
+	for _, selection := range selections {
+		subfields = append(subfields, selection.SelectionSet...)
+	}
+	return e.complete(ctx, f.Type, value, append([]any{source}, parents...), path, subfields)
+}
+
+// resolve turns a panic of the resolver into a field error, one bad carrier payload should not take the others down
+func (e *executor) resolve(f *Field, p ResolveParams, path []any) (value any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("resolver of %q panicked: %v", f.Name, r)
+		}
+	}()
+	return f.Resolve(p)
+}
+
+func (e *executor) null(t Type) (any, bool) {
+	_, nonNull := t.(*NonNull)
+	return nil, !nonNull
+}
+
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
+}
+
+// complete shapes the resolved value after the type of the field
+func (e *executor) complete(ctx context.Context, t Type, value any, parents []any, path []any, selections []Selection) (any, bool) {
+	if nonNull, ok := t.(*NonNull); ok {
+		completed, valid := e.complete(ctx, nonNull.OfType, value, parents, path, selections)
+		if valid && completed == nil {
+			if !isNil(value) {
+				return nil, false // the error is already recorded further down
+			}
+			e.fail(path, fmt.Errorf("cannot return null for non-nullable field"))
+			return nil, false
+		}
+		return completed, valid
+	}
+	if isNil(value) {
+		return nil, true
+	}
+	switch t := t.(type) {
+	case *Scalar:
+		return t.Serialize(basic(value)), true
+	case *Object:
+		result, valid := e.selectionSet(ctx, t, value, parents, path, selections, false)
+		if !valid {
+			return nil, true
+		}
+		return result, true
+	case *List:
+		return e.list(ctx, t, value, parents, path, selections)
+	}
+	return nil, true
+}
+
+// list completes the items concurrently when they are objects, a nested resolver of an item may call a carrier. The slots of the
+// executor keep a list of a thousand schedules from starting a thousand goroutines
+func (e *executor) list(ctx context.Context, t *List, value any, parents []any, path []any, selections []Selection) (any, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		e.fail(path, fmt.Errorf("expected a list, got %T", value))
+		return nil, true
+	}
+	items := make([]any, v.Len())
+	valid := make([]bool, v.Len())
+	_, objects := unwrap(t.OfType).(*Object)
+	var wg sync.WaitGroup
+	for i := range items {
+		complete := func() {
+			items[i], valid[i] = e.complete(ctx, t.OfType, v.Index(i).Interface(), parents, appendPath(path, i), selections)
+		}
+		if !objects {
+			complete()
+			continue
+		}
+		e.spawn(&wg, complete)
+	}
+	wg.Wait()
+	for _, ok := range valid {
+		if !ok {
+			return nil, true
+		}
+	}
+	return items, true
+}
+
+// basic converts the named Go types(e.g. schema.TransportType) to their underlying kind
+func basic(value any) any {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	return value
+}
@@ -0,0 +1,239 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testItem struct {
+	Name string
+	Tags []string
+}
+
+// testSchema is Query { items(count: Int = 3): [Item!], item(name: String!): Item, greet(name: String!): String }, an item has
+// children of its own, a field that fails and a non-null field that fails
+func testSchema(slow func()) *Schema {
+	item := NewObject("Item", "")
+	item.AddField(&Field{Name: "name", Type: &NonNull{OfType: String}, Resolve: func(p ResolveParams) (any, error) {
+		return p.Source.(*testItem).Name, nil
+	}})
+	item.AddField(&Field{Name: "tags", Type: &List{OfType: String}, Resolve: func(p ResolveParams) (any, error) {
+		return p.Source.(*testItem).Tags, nil
+	}})
+	item.AddField(&Field{Name: "parent", Type: String, Resolve: func(p ResolveParams) (any, error) {
+		if len(p.Parents) == 0 {
+			return nil, nil
+		}
+		if parent, ok := p.Parents[0].(*testItem); ok {
+			return parent.Name, nil
+		}
+		return nil, nil
+	}})
+	item.AddField(&Field{Name: "broken", Type: String, Resolve: func(ResolveParams) (any, error) {
+		return nil, fmt.Errorf("carrier timed out")
+	}})
+	item.AddField(&Field{Name: "fragile", Type: &NonNull{OfType: String}, Resolve: func(ResolveParams) (any, error) {
+		panic("bad payload")
+	}})
+	item.AddField(&Field{Name: "slow", Type: String, Resolve: func(p ResolveParams) (any, error) {
+		if slow != nil {
+			slow()
+		}
+		return p.Source.(*testItem).Name, nil
+	}})
+	items := func(prefix string, count int) []*testItem {
+		list := make([]*testItem, count)
+		for i := range list {
+			list[i] = &testItem{Name: fmt.Sprintf("%s%d", prefix, i), Tags: []string{"a", "b"}}
+		}
+		return list
+	}
+	item.AddField(&Field{
+		Name: "children",
+		Type: &List{OfType: &NonNull{OfType: item}},
+		Args: []*Argument{{Name: "count", Type: Int, Default: 2}},
+		Resolve: func(p ResolveParams) (any, error) {
+			return items(p.Source.(*testItem).Name+".", p.Args["count"].(int)), nil
+		},
+		Cost: 10,
+	})
+	query := NewObject("Query", "")
+	query.AddField(&Field{
+		Name:    "items",
+		Type:    &List{OfType: &NonNull{OfType: item}},
+		Args:    []*Argument{{Name: "count", Type: Int, Default: 3}},
+		Resolve: func(p ResolveParams) (any, error) { return items("i", p.Args["count"].(int)), nil },
+		Cost:    10,
+	})
+	query.AddField(&Field{
+		Name:    "item",
+		Type:    item,
+		Args:    []*Argument{{Name: "name", Type: &NonNull{OfType: String}}},
+		Resolve: func(p ResolveParams) (any, error) { return &testItem{Name: p.Args["name"].(string)}, nil },
+	})
+	query.AddField(&Field{
+		Name:    "greet",
+		Type:    String,
+		Args:    []*Argument{{Name: "name", Type: &NonNull{OfType: String}}},
+		Resolve: func(p ResolveParams) (any, error) { return "hello " + p.Args["name"].(string), nil },
+	})
+	return &Schema{Query: query, MaxDepth: 4, MaxCost: 60}
+}
+
+func TestDo(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		operation string
+		want      string
+	}{
+		{
+			name:  "fields in selection order",
+			query: `{ items(count: 2) { tags name } greet(name: "hub") }`,
+			want:  `{"data":{"items":[{"tags":["a","b"],"name":"i0"},{"tags":["a","b"],"name":"i1"}],"greet":"hello hub"}}`,
+		},
+		{
+			name:  "aliases and typename",
+			query: `{ a: item(name: "x") { __typename name } b: item(name: "y") { name } }`,
+			want:  `{"data":{"a":{"__typename":"Item","name":"x"},"b":{"name":"y"}}}`,
+		},
+		{
+			name:  "fragments merge",
+			query: `{ item(name: "x") { ...names ... on Item { tags } name } } fragment names on Item { name }`,
+			want:  `{"data":{"item":{"name":"x","tags":null}}}`,
+		},
+		{
+			name:      "variables, defaults and directives",
+			query:     `query Q($name: String!, $withTags: Boolean = false) { item(name: $name) { name tags @include(if: $withTags) } }`,
+			variables: map[string]any{"name": "x"},
+			want:      `{"data":{"item":{"name":"x"}}}`,
+		},
+		{
+			name:      "operation name",
+			query:     `query A { greet(name: "a") } query B { greet(name: "b") }`,
+			operation: "B",
+			want:      `{"data":{"greet":"hello b"}}`,
+		},
+		{
+			name:  "parents",
+			query: `{ item(name: "x") { children(count: 1) { parent } } }`,
+			want:  `{"data":{"item":{"children":[{"parent":"x"}]}}}`,
+		},
+		{
+			name:  "resolver error nulls the field",
+			query: `{ item(name: "x") { name broken } }`,
+			want:  `{"data":{"item":{"name":"x","broken":null}},"errors":[{"message":"carrier timed out","path":["item","broken"]}]}`,
+		},
+		{
+			name:  "non-null error bubbles to the nullable parent",
+			query: `{ item(name: "x") { name fragile } greet(name: "y") }`,
+			want:  `{"data":{"item":null,"greet":"hello y"},"errors":[{"message":"resolver of \"fragile\" panicked: bad payload","path":["item","fragile"]}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := Do(context.Background(), testSchema(nil), Request{Query: tt.query, Variables: tt.variables, OperationName: tt.operation})
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			got, err := json.Marshal(response)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Do() = %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoRequestErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		want      string
+	}{
+		{name: "syntax", query: `{ items { name }`, want: "syntax error"},
+		{name: "unknown field", query: `{ items { color } }`, want: `cannot query field "color"`},
+		{name: "unknown argument", query: `{ items(size: 1) { name } }`, want: `unknown argument "size"`},
+		{name: "missing argument", query: `{ greet }`, want: `argument "name" of type String! is required`},
+		{name: "wrong argument type", query: `{ greet(name: 1) }`, want: "String cannot represent 1"},
+		{name: "missing selection", query: `{ items }`, want: "must have a selection of subfields"},
+		{name: "selection on scalar", query: `{ greet(name: "a") { x } }`, want: "can not have a selection of subfields"},
+		{name: "undefined variable", query: `{ greet(name: $name) }`, want: `variable "$name" is not defined`},
+		{name: "missing variable", query: `query ($name: String!) { greet(name: $name) }`, want: `variable "$name" of type String! is required`},
+		{name: "variable type", query: `query ($n: Int) { items(count: $n) { name } }`, variables: map[string]any{"n": 1.5}, want: "Int cannot represent 1.5"},
+		{name: "unknown fragment", query: `{ items { ...parts } }`, want: `unknown fragment "parts"`},
+		{name: "fragment cycle", query: `{ items { ...a } } fragment a on Item { children { ...a } }`, want: `fragment "a" spreads itself`},
+		{name: "operation name required", query: `query A { greet(name: "a") } query B { greet(name: "b") }`, want: "operationName is required"},
+		{name: "unknown directive", query: `{ greet(name: "a") @defer }`, want: "unknown directive @defer"},
+		{
+			name:  "too deep",
+			query: `{ items { children { children { children { children { name } } } } } }`,
+			want:  "nested deeper than 4 levels",
+		},
+		{
+			name:  "too costly",
+			query: `{ a: items { name } b: items { name } c: items { name } d: items { name } e: items { name } f: items { name } }`,
+			want:  "query costs 72, more than the limit of 60",
+		},
+		{
+			name:  "fragments count every spread",
+			query: `{ items { ...c ...c ...c ...c ...c } } fragment c on Item { children { name } }`,
+			want:  "more than the limit of 60",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Do(context.Background(), testSchema(nil), Request{Query: tt.query, Variables: tt.variables})
+			if err == nil {
+				t.Fatalf("Do(%q) succeeded, want an error", tt.query)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Do() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoBoundsConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	schema := testSchema(func() {
+		current := running.Add(1)
+		for {
+			seen := peak.Load()
+			if current <= seen || peak.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+	})
+	schema.MaxConcurrency, schema.MaxCost = 4, 0
+	response, err := Do(context.Background(), schema, Request{Query: `{ items(count: 20) { slow children(count: 10) { slow } } }`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Errors) > 0 {
+		t.Fatalf("errors = %v", response.Errors[0].Message)
+	}
+	// the goroutine of the request resolves inline when every slot is taken
+	if got := peak.Load(); got > int32(schema.MaxConcurrency)+1 {
+		t.Errorf("%d resolvers ran at the same time, want at most %d", got, schema.MaxConcurrency+1)
+	}
+	items := response.Data.(*orderedMap).values["items"].([]any)
+	if len(items) != 20 {
+		t.Fatalf("got %d items, want 20", len(items))
+	}
+	children := items[19].(*orderedMap).values["children"].([]any)
+	if got := children[9].(*orderedMap).values["slow"]; got != "i19.9" {
+		t.Errorf("last child = %v, want i19.9", got)
+	}
+}
@@ -0,0 +1,172 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// lexer splits the document into tokens, commas and comments are insignificant in GraphQL
+type lexer struct {
+	source string
+	pos    int
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.source) {
+		switch c := l.source[l.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.pos++
+		case c == '#':
+			for l.pos < len(l.source) && l.source[l.pos] != '\n' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.source[l.pos:], "\uFEFF"):
+			l.pos += len("\uFEFF")
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	start := l.pos
+	if l.pos >= len(l.source) {
+		return token{kind: tokenEOF, pos: start}, nil
+	}
+	c := l.source[l.pos]
+	switch {
+	case strings.HasPrefix(l.source[l.pos:], "..."):
+		l.pos += 3
+		return token{kind: tokenPunct, value: "...", pos: start}, nil
+	case strings.ContainsRune("!$()[]{}:=@|&", rune(c)):
+		l.pos++
+		return token{kind: tokenPunct, value: string(c), pos: start}, nil
+	case isNameStart(c):
+		for l.pos < len(l.source) && (isNameStart(l.source[l.pos]) || isDigit(l.source[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokenName, value: l.source[start:l.pos], pos: start}, nil
+	case c == '-' || isDigit(c):
+		return l.number()
+	case c == '"':
+		return l.string()
+	}
+	return token{}, fmt.Errorf("syntax error: unexpected character %q at %d", c, start)
+}
+
+func (l *lexer) digits() {
+	for l.pos < len(l.source) && isDigit(l.source[l.pos]) {
+		l.pos++
+	}
+}
+
+func (l *lexer) number() (token, error) {
+	start, kind := l.pos, tokenInt
+	if l.source[l.pos] == '-' {
+		l.pos++
+	}
+	l.digits()
+	if l.pos < len(l.source) && l.source[l.pos] == '.' {
+		kind = tokenFloat
+		l.pos++
+		l.digits()
+	}
+	if l.pos < len(l.source) && (l.source[l.pos] == 'e' || l.source[l.pos] == 'E') {
+		kind = tokenFloat
+		l.pos++
+		if l.pos < len(l.source) && (l.source[l.pos] == '+' || l.source[l.pos] == '-') {
+			l.pos++
+		}
+		l.digits()
+	}
+	value := l.source[start:l.pos]
+	if value == "-" || strings.HasSuffix(value, ".") {
+		return token{}, fmt.Errorf("syntax error: invalid number %q at %d", value, start)
+	}
+	return token{kind: kind, value: value, pos: start}, nil
+}
+
+// string reads a quoted string, the block strings are read as they are without the common indentation removed
+func (l *lexer) string() (token, error) {
+	start := l.pos
+	if strings.HasPrefix(l.source[l.pos:], `"""`) {
+		end := strings.Index(l.source[l.pos+3:], `"""`)
+		if end < 0 {
+			return token{}, fmt.Errorf("syntax error: unterminated string at %d", start)
+		}
+		value := l.source[l.pos+3 : l.pos+3+end]
+		l.pos += end + 6
+		return token{kind: tokenString, value: strings.TrimSpace(value), pos: start}, nil
+	}
+	l.pos++
+	var value strings.Builder
+	for l.pos < len(l.source) {
+		c := l.source[l.pos]
+		switch {
+		case c == '"':
+			l.pos++
+			return token{kind: tokenString, value: value.String(), pos: start}, nil
+		case c == '\n' || c == '\r':
+			return token{}, fmt.Errorf("syntax error: unterminated string at %d", start)
+		case c == '\\' && l.pos+1 < len(l.source):
+			escaped := l.source[l.pos+1]
+			l.pos += 2
+			switch escaped {
+			case 'n':
+				value.WriteByte('\n')
+			case 't':
+				value.WriteByte('\t')
+			case 'r':
+				value.WriteByte('\r')
+			case 'b':
+				value.WriteByte('\b')
+			case 'f':
+				value.WriteByte('\f')
+			case 'u':
+				var r rune
+				if l.pos+4 > len(l.source) {
+					return token{}, fmt.Errorf("syntax error: invalid unicode escape at %d", l.pos)
+				}
+				if _, err := fmt.Sscanf(l.source[l.pos:l.pos+4], "%04x", &r); err != nil {
+					return token{}, fmt.Errorf("syntax error: invalid unicode escape at %d", l.pos)
+				}
+				value.WriteRune(r)
+				l.pos += 4
+			default:
+				value.WriteByte(escaped)
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(l.source[l.pos:])
+			value.WriteRune(r)
+			l.pos += size
+		}
+	}
+	return token{}, fmt.Errorf("syntax error: unterminated string at %d", start)
+}
@@ -0,0 +1,491 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Document is a parsed request, only query operations and fragments are supported
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+type Operation struct {
+	Name         string
+	Variables    []*VariableDefinition
+	SelectionSet []Selection
+}
+
+type VariableDefinition struct {
+	Name    string
+	Type    TypeRef
+	Default Value
+}
+
+// TypeRef is the type written in a variable definition(e.g. [String!]!)
+type TypeRef struct {
+	Name    string
+	Elem    *TypeRef
+	NonNull bool
+}
+
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	SelectionSet  []Selection
+}
+
+// Selection is one of *FieldSelection, *FragmentSpread or *InlineFragment
+type Selection interface {
+	directives() []*Directive
+}
+
+type FieldSelection struct {
+	Alias        string
+	Name         string
+	Arguments    map[string]Value
+	Directives   []*Directive
+	SelectionSet []Selection
+}
+
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+}
+
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []*Directive
+	SelectionSet  []Selection
+}
+
+type Directive struct {
+	Name      string
+	Arguments map[string]Value
+}
+
+func (f *FieldSelection) directives() []*Directive { return f.Directives }
+func (f *FragmentSpread) directives() []*Directive { return f.Directives }
+func (f *InlineFragment) directives() []*Directive { return f.Directives }
+
+// ResponseKey is the name of the field in the result, the alias when there is one
+func (f *FieldSelection) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// Value is a literal of the document, Variable is resolved at execution
+type Value = any
+
+type Variable string
+
+type EnumValue string
+
+type parser struct {
+	lexer *lexer
+	token token
+}
+
+// Parse reads the query document
+func Parse(query string) (*Document, error) {
+	p := &parser{lexer: &lexer{source: query}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	doc := &Document{Fragments: make(map[string]*Fragment)}
+	for p.token.kind != tokenEOF {
+		switch {
+		case p.peek("{"):
+			selections, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, &Operation{SelectionSet: selections})
+		case p.token.kind == tokenName && p.token.value == "query":
+			operation, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, operation)
+		case p.token.kind == tokenName && p.token.value == "fragment":
+			fragment, err := p.fragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.Fragments[fragment.Name]; ok {
+				return nil, fmt.Errorf("there can be only one fragment named %q", fragment.Name)
+			}
+			doc.Fragments[fragment.Name] = fragment
+		case p.token.kind == tokenName && (p.token.value == "mutation" || p.token.value == "subscription"):
+			return nil, fmt.Errorf("%s operations are not supported", p.token.value)
+		default:
+			return nil, p.unexpected()
+		}
+	}
+	if len(doc.Operations) == 0 {
+		return nil, fmt.Errorf("the document has no operation")
+	}
+	return doc, nil
+}
+
+func (p *parser) advance() error {
+	t, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.token = t
+	return nil
+}
+
+func (p *parser) peek(punct string) bool {
+	return p.token.kind == tokenPunct && p.token.value == punct
+}
+
+func (p *parser) unexpected() error {
+	if p.token.kind == tokenEOF {
+		return fmt.Errorf("syntax error: unexpected end of document")
+	}
+	return fmt.Errorf("syntax error: unexpected %q at %d", p.token.value, p.token.pos)
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.peek(punct) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+func (p *parser) name() (string, error) {
+	if p.token.kind != tokenName {
+		return "", p.unexpected()
+	}
+	value := p.token.value
+	return value, p.advance()
+}
+
+func (p *parser) operation() (*Operation, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	operation := &Operation{}
+	if p.token.kind == tokenName {
+		operation.Name = p.token.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peek("(") {
+		variables, err := p.variableDefinitions()
+		if err != nil {
+			return nil, err
+		}
+		operation.Variables = variables
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	selections, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	operation.SelectionSet = selections
+	return operation, nil
+}
+
+func (p *parser) variableDefinitions() ([]*VariableDefinition, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var definitions []*VariableDefinition
+	for !p.peek(")") {
+		if err := p.expect("$"); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		typeRef, err := p.typeRef()
+		if err != nil {
+			return nil, err
+		}
+		definition := &VariableDefinition{Name: name, Type: typeRef}
+		if p.peek("=") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if definition.Default, err = p.value(true); err != nil {
+				return nil, err
+			}
+		}
+		definitions = append(definitions, definition)
+	}
+	return definitions, p.advance()
+}
+
+func (p *parser) typeRef() (TypeRef, error) {
+	var typeRef TypeRef
+	if p.peek("[") {
+		if err := p.advance(); err != nil {
+			return typeRef, err
+		}
+		elem, err := p.typeRef()
+		if err != nil {
+			return typeRef, err
+		}
+		if err := p.expect("]"); err != nil {
+			return typeRef, err
+		}
+		typeRef.Elem = &elem
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return typeRef, err
+		}
+		typeRef.Name = name
+	}
+	if p.peek("!") {
+		typeRef.NonNull = true
+		return typeRef, p.advance()
+	}
+	return typeRef, nil
+}
+
+func (p *parser) fragment() (*Fragment, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if name == "on" {
+		return nil, fmt.Errorf("syntax error: a fragment can not be named \"on\"")
+	}
+	if on, err := p.name(); err != nil || on != "on" {
+		return nil, fmt.Errorf("syntax error: fragment %q has no type condition", name)
+	}
+	typeCondition, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	selections, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	return &Fragment{Name: name, TypeCondition: typeCondition, SelectionSet: selections}, nil
+}
+
+func (p *parser) selectionSet() ([]Selection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var selections []Selection
+	for !p.peek("}") {
+		selection, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+	}
+	if len(selections) == 0 {
+		return nil, fmt.Errorf("syntax error: empty selection set at %d", p.token.pos)
+	}
+	return selections, p.advance()
+}
+
+func (p *parser) selection() (Selection, error) {
+	if p.peek("...") {
+		return p.spread()
+	}
+	field := &FieldSelection{}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	field.Name = name
+	if p.peek(":") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		field.Alias = name
+		if field.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peek("(") {
+		if field.Arguments, err = p.arguments(); err != nil {
+			return nil, err
+		}
+	}
+	if field.Directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	if p.peek("{") {
+		if field.SelectionSet, err = p.selectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return field, nil
+}
+
+func (p *parser) spread() (Selection, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.token.kind == tokenName && p.token.value != "on" {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		directives, err := p.directives()
+		if err != nil {
+			return nil, err
+		}
+		return &FragmentSpread{Name: name, Directives: directives}, nil
+	}
+	inline := &InlineFragment{}
+	if p.token.kind == tokenName {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		typeCondition, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		inline.TypeCondition = typeCondition
+	}
+	var err error
+	if inline.Directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	if inline.SelectionSet, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+	return inline, nil
+}
+
+func (p *parser) arguments() (map[string]Value, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	arguments := make(map[string]Value)
+	for !p.peek(")") {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if _, ok := arguments[name]; ok {
+			return nil, fmt.Errorf("there can be only one argument named %q", name)
+		}
+		if arguments[name], err = p.value(false); err != nil {
+			return nil, err
+		}
+	}
+	return arguments, p.advance()
+}
+
+func (p *parser) directives() ([]*Directive, error) {
+	var directives []*Directive
+	for p.peek("@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		directive := &Directive{Name: name}
+		if p.peek("(") {
+			if directive.Arguments, err = p.arguments(); err != nil {
+				return nil, err
+			}
+		}
+		directives = append(directives, directive)
+	}
+	return directives, nil
+}
+
+// value reads a literal, the default value of a variable can not refer to another variable
+func (p *parser) value(constant bool) (Value, error) {
+	t := p.token
+	switch {
+	case t.kind == tokenPunct && t.value == "$" && !constant:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		return Variable(name), err
+	case t.kind == tokenInt:
+		value, err := strconv.Atoi(t.value)
+		if err != nil {
+			return nil, fmt.Errorf("syntax error: invalid int %q", t.value)
+		}
+		return value, p.advance()
+	case t.kind == tokenFloat:
+		value, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("syntax error: invalid float %q", t.value)
+		}
+		return value, p.advance()
+	case t.kind == tokenString:
+		return t.value, p.advance()
+	case t.kind == tokenName:
+		var value Value
+		switch t.value {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null":
+			value = nil
+		default:
+			value = EnumValue(t.value)
+		}
+		return value, p.advance()
+	case t.kind == tokenPunct && t.value == "[":
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		list := []any{}
+		for !p.peek("]") {
+			item, err := p.value(constant)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		return list, p.advance()
+	case t.kind == tokenPunct && t.value == "{":
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		object := make(map[string]any)
+		for !p.peek("}") {
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			if object[name], err = p.value(constant); err != nil {
+				return nil, err
+			}
+		}
+		return object, p.advance()
+	}
+	return nil, p.unexpected()
+}
@@ -0,0 +1,135 @@
+package graphql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		operations int
+		fragments  int
+		check      func(t *testing.T, doc *Document)
+	}{
+		{
+			name:       "shorthand",
+			query:      `{ items { name } }`,
+			operations: 1,
+		},
+		{
+			name:       "named with variables",
+			query:      `query Lane($from: String!, $scac: [String!] = ["MAEU"], $range: Int = 4) { items(from: $from) { name } }`,
+			operations: 1,
+			check: func(t *testing.T, doc *Document) {
+				operation := doc.Operations[0]
+				if operation.Name != "Lane" || len(operation.Variables) != 3 {
+					t.Fatalf("got operation %q with %d variables", operation.Name, len(operation.Variables))
+				}
+				scac := operation.Variables[1]
+				if scac.Type.Elem == nil || scac.Type.Elem.Name != "String" || !scac.Type.Elem.NonNull {
+					t.Errorf("type of $scac = %+v, want [String!]", scac.Type)
+				}
+				if !reflect.DeepEqual(scac.Default, []any{"MAEU"}) {
+					t.Errorf("default of $scac = %v", scac.Default)
+				}
+			},
+		},
+		{
+			name:       "alias, arguments and directives",
+			query:      `{ first: items(count: 2, ratio: 1.5, on: true, mode: FAST, nested: {a: [1, null]}) @skip(if: false) { name } }`,
+			operations: 1,
+			check: func(t *testing.T, doc *Document) {
+				field := doc.Operations[0].SelectionSet[0].(*FieldSelection)
+				if field.ResponseKey() != "first" || field.Name != "items" {
+					t.Errorf("response key %q of field %q", field.ResponseKey(), field.Name)
+				}
+				want := map[string]Value{
+					"count": 2, "ratio": 1.5, "on": true, "mode": EnumValue("FAST"), "nested": map[string]any{"a": []any{1, nil}},
+				}
+				if !reflect.DeepEqual(field.Arguments, want) {
+					t.Errorf("arguments = %#v, want %#v", field.Arguments, want)
+				}
+				if len(field.Directives) != 1 || field.Directives[0].Name != "skip" {
+					t.Errorf("directives = %+v", field.Directives)
+				}
+			},
+		},
+		{
+			name:       "fragments",
+			query:      `query A { items { ...parts ... on Item { name } } } fragment parts on Item { name }`,
+			operations: 1,
+			fragments:  1,
+			check: func(t *testing.T, doc *Document) {
+				selections := doc.Operations[0].SelectionSet[0].(*FieldSelection).SelectionSet
+				if _, ok := selections[0].(*FragmentSpread); !ok {
+					t.Errorf("first selection is %T, want a fragment spread", selections[0])
+				}
+				if inline, ok := selections[1].(*InlineFragment); !ok || inline.TypeCondition != "Item" {
+					t.Errorf("second selection is %#v, want an inline fragment on Item", selections[1])
+				}
+			},
+		},
+		{
+			name:       "strings and comments",
+			query:      "{ items(from: \"a\\\"b\\u0041\", note: \"\"\"block \"quoted\" text\"\"\") { name } # comment\n}",
+			operations: 1,
+			check: func(t *testing.T, doc *Document) {
+				args := doc.Operations[0].SelectionSet[0].(*FieldSelection).Arguments
+				if args["from"] != `a"bA` || args["note"] != `block "quoted" text` {
+					t.Errorf("strings = %q, %q", args["from"], args["note"])
+				}
+			},
+		},
+		{
+			name:       "several operations",
+			query:      `query A { items { name } } query B { item { name } }`,
+			operations: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(doc.Operations) != tt.operations || len(doc.Fragments) != tt.fragments {
+				t.Fatalf("got %d operations and %d fragments, want %d and %d", len(doc.Operations), len(doc.Fragments), tt.operations, tt.fragments)
+			}
+			if tt.check != nil {
+				tt.check(t, doc)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "empty document", query: ``, want: "no operation"},
+		{name: "empty selection", query: `{ }`, want: "empty selection set"},
+		{name: "unclosed selection", query: `{ items { name }`, want: ""},
+		{name: "mutation", query: `mutation { items { name } }`, want: "not supported"},
+		{name: "subscription", query: `subscription { items { name } }`, want: "not supported"},
+		{name: "duplicate fragment", query: `{ items { ...a } } fragment a on Item { name } fragment a on Item { name }`, want: "only one fragment"},
+		{name: "unterminated string", query: `{ items(from: "abc) { name } }`, want: ""},
+		{name: "variable in default", query: `query ($a: Int = $b) { items { name } }`, want: ""},
+		{name: "missing colon", query: `{ items(from "a") { name } }`, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			if err == nil {
+				t.Fatalf("Parse(%q) succeeded, want an error", tt.query)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
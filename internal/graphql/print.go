@@ -0,0 +1,67 @@
+package graphql
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Print writes the schema in the schema definition language, the objects follow the query root in the order they are reached
+func Print(schema *Schema) string {
+	var sdl strings.Builder
+	sdl.WriteString("schema {\n  query: " + schema.Query.Name + "\n}\n")
+	scalars := false
+	seen := map[*Object]bool{schema.Query: true}
+	queue := []*Object{schema.Query}
+	for len(queue) > 0 {
+		o := queue[0]
+		queue = queue[1:]
+		sdl.WriteString("\n")
+		description(&sdl, "", o.Description)
+		sdl.WriteString("type " + o.Name + " {\n")
+		for _, name := range o.FieldNames() {
+			f := o.Fields[name]
+			description(&sdl, "  ", f.Description)
+			sdl.WriteString("  " + f.Name + arguments(f.Args) + ": " + f.Type.String() + "\n")
+			switch named := unwrap(f.Type).(type) {
+			case *Object:
+				if !seen[named] {
+					seen[named] = true
+					queue = append(queue, named)
+				}
+			case *Scalar:
+				scalars = scalars || named == JSON
+			}
+		}
+		sdl.WriteString("}\n")
+	}
+	if scalars {
+		sdl.WriteString("\n")
+		description(&sdl, "", JSON.Description)
+		sdl.WriteString("scalar JSON\n")
+	}
+	return sdl.String()
+}
+
+func description(sdl *strings.Builder, indent, text string) {
+	if text == "" {
+		return
+	}
+	quoted, _ := json.Marshal(text)
+	sdl.WriteString(indent + string(quoted) + "\n")
+}
+
+func arguments(args []*Argument) string {
+	if len(args) == 0 {
+		return ""
+	}
+	list := make([]string, 0, len(args))
+	for _, arg := range args {
+		item := arg.Name + ": " + arg.Type.String()
+		if arg.Default != nil {
+			value, _ := json.Marshal(arg.Default)
+			item += " = " + string(value)
+		}
+		list = append(list, item)
+	}
+	return "(" + strings.Join(list, ", ") + ")"
+}
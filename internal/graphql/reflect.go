@@ -0,0 +1,113 @@
+package graphql
+
+import (
+	"cmp"
+	"reflect"
+	"strings"
+)
+
+// Builder turns Go structs into object types through their json tags, one object per Go type named after it. The fields are
+// nullable and read the struct field unless a resolver is added on top
+type Builder struct {
+	objects map[reflect.Type]*Object
+}
+
+func NewBuilder() *Builder {
+	return &Builder{objects: make(map[reflect.Type]*Object)}
+}
+
+// Object returns the object type of the struct(or pointer to struct) v
+func (b *Builder) Object(v any) *Object {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return b.object(t)
+}
+
+func (b *Builder) object(t reflect.Type) *Object {
+	if o, ok := b.objects[t]; ok {
+		return o
+	}
+	o := NewObject(t.Name(), "")
+	b.objects[t] = o // registered before the fields so that recursive types terminate
+	b.addFields(o, t, nil)
+	return o
+}
+
+// addFields follows encoding/json, the fields of an embedded struct are promoted
+func (b *Builder) addFields(o *Object, t reflect.Type, index []int) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		path := append(append([]int(nil), index...), i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				b.addFields(o, embedded, path)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		o.AddField(&Field{
+			Name:        strings.TrimSpace(cmp.Or(name, f.Name)),
+			Description: f.Tag.Get("description"),
+			Type:        b.typeOf(f.Type),
+			Resolve:     structField(path, strings.Contains(options, "omitempty")),
+		})
+	}
+}
+
+func (b *Builder) typeOf(t reflect.Type) Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return String
+	case reflect.Bool:
+		return Boolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Int
+	case reflect.Float32, reflect.Float64:
+		return Float
+	case reflect.Slice, reflect.Array:
+		return &List{OfType: b.typeOf(t.Elem())}
+	case reflect.Struct:
+		return b.object(t)
+	}
+	return JSON // interface{} and maps keep whatever the carrier sent
+}
+
+// structField reads the field of the source, an omitted(omitempty) zero value resolves to null like it is left out of the JSON
+func structField(index []int, omitempty bool) ResolveFunc {
+	return func(p ResolveParams) (any, error) {
+		v := reflect.ValueOf(p.Source)
+		for _, i := range index {
+			for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+				if v.IsNil() {
+					return nil, nil
+				}
+				v = v.Elem()
+			}
+			if v.Kind() != reflect.Struct {
+				return nil, nil
+			}
+			v = v.Field(i)
+		}
+		if omitempty && v.IsZero() {
+			return nil, nil
+		}
+		return v.Interface(), nil
+	}
+}
@@ -0,0 +1,165 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// Type is one of *Scalar, *Object, *List or *NonNull
+type Type interface {
+	String() string
+}
+
+// Scalar coerces the input values of the arguments and serializes the resolved values
+type Scalar struct {
+	Name        string
+	Description string
+	Coerce      func(value any) (any, error)
+	Serialize   func(value any) any
+}
+
+type List struct {
+	OfType Type
+}
+
+type NonNull struct {
+	OfType Type
+}
+
+// Object keeps its fields in declaration order so that the printed schema follows the Go struct
+type Object struct {
+	Name        string
+	Description string
+	Fields      map[string]*Field
+	order       []string
+}
+
+// Field counts 1 towards the cost of the query plus Cost, which weighs the resolvers calling out(e.g. to the carriers)
+type Field struct {
+	Name        string
+	Description string
+	Type        Type
+	Args        []*Argument
+	Resolve     ResolveFunc
+	Cost        int
+}
+
+// Argument without a default is optional unless its type is NonNull
+type Argument struct {
+	Name        string
+	Description string
+	Type        Type
+	Default     any
+}
+
+// ResolveParams hands the resolver the parent value and the coerced arguments. Parents holds the values above the source, the
+// closest first, so a nested field can read the object it is reached from(e.g. the schedule of a leg)
+type ResolveParams struct {
+	Context context.Context
+	Source  any
+	Args    map[string]any
+	Parents []any
+}
+
+type ResolveFunc func(p ResolveParams) (any, error)
+
+// Schema only has a query root, the hub is read only. A query nested deeper than MaxDepth or costing more than MaxCost is rejected
+// before anything is resolved(0 leaves it unbounded), MaxConcurrency bounds the fields and list items resolved at the same time
+type Schema struct {
+	Query          *Object
+	MaxDepth       int
+	MaxCost        int
+	MaxConcurrency int
+}
+
+func (s *Scalar) String() string  { return s.Name }
+func (o *Object) String() string  { return o.Name }
+func (l *List) String() string    { return "[" + l.OfType.String() + "]" }
+func (n *NonNull) String() string { return n.OfType.String() + "!" }
+
+func NewObject(name, description string) *Object {
+	return &Object{Name: name, Description: description, Fields: make(map[string]*Field)}
+}
+
+// AddField adds the field or replaces the one with the same name(e.g. to give a reflected field a resolver)
+func (o *Object) AddField(f *Field) *Object {
+	if _, ok := o.Fields[f.Name]; !ok {
+		o.order = append(o.order, f.Name)
+	}
+	o.Fields[f.Name] = f
+	return o
+}
+
+// FieldNames lists the fields in declaration order
+func (o *Object) FieldNames() []string {
+	return o.order
+}
+
+// unwrap drops the NonNull and List wrappers down to the named type
+func unwrap(t Type) Type {
+	for {
+		switch w := t.(type) {
+		case *NonNull:
+			t = w.OfType
+		case *List:
+			t = w.OfType
+		default:
+			return t
+		}
+	}
+}
+
+func coerceInt(value any) (any, error) {
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case float64: // variables decoded from JSON are float64
+		if v == math.Trunc(v) && v >= math.MinInt32 && v <= math.MaxInt32 {
+			return int(v), nil
+		}
+	}
+	return nil, fmt.Errorf("Int cannot represent %v", value)
+}
+
+func coerceFloat(value any) (any, error) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	}
+	return nil, fmt.Errorf("Float cannot represent %v", value)
+}
+
+func coerceString(value any) (any, error) {
+	if v, ok := value.(string); ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("String cannot represent %v", value)
+}
+
+func coerceBoolean(value any) (any, error) {
+	if v, ok := value.(bool); ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("Boolean cannot represent %v", value)
+}
+
+func identity(value any) any { return value }
+
+var (
+	Int     = &Scalar{Name: "Int", Coerce: coerceInt, Serialize: identity}
+	Float   = &Scalar{Name: "Float", Coerce: coerceFloat, Serialize: identity}
+	String  = &Scalar{Name: "String", Coerce: coerceString, Serialize: identity}
+	Boolean = &Scalar{Name: "Boolean", Coerce: coerceBoolean, Serialize: identity}
+	// JSON carries the fields whose shape differs per carrier(e.g. the key and the bound of a port call)
+	JSON = &Scalar{
+		Name:        "JSON",
+		Description: "Any JSON value",
+		Coerce:      func(value any) (any, error) { return value, nil },
+		Serialize:   identity,
+	}
+)
+
+var builtinScalars = map[string]*Scalar{"Int": Int, "Float": Float, "String": String, "Boolean": Boolean}
@@ -0,0 +1,267 @@
+package graphql
+
+import (
+	"fmt"
+)
+
+// typeFromRef resolves the type of a variable definition, the input types are the built-in scalars and JSON
+func typeFromRef(ref TypeRef) (Type, error) {
+	var t Type
+	switch {
+	case ref.Elem != nil:
+		elem, err := typeFromRef(*ref.Elem)
+		if err != nil {
+			return nil, err
+		}
+		t = &List{OfType: elem}
+	case ref.Name == JSON.Name:
+		t = JSON
+	case builtinScalars[ref.Name] != nil:
+		t = builtinScalars[ref.Name]
+	default:
+		return nil, fmt.Errorf("unknown input type %q", ref.Name)
+	}
+	if ref.NonNull {
+		t = &NonNull{OfType: t}
+	}
+	return t, nil
+}
+
+// coerceInput checks the literal or variable value against the input type. The variables are coerced beforehand so they only
+// need checking against the type of the argument
+func coerceInput(t Type, value Value, variables map[string]any) (any, error) {
+	if name, ok := value.(Variable); ok {
+		value = variables[string(name)]
+	}
+	switch t := t.(type) {
+	case *NonNull:
+		if value == nil {
+			return nil, fmt.Errorf("expected a non-null %s", t.OfType)
+		}
+		return coerceInput(t.OfType, value, variables)
+	case *List:
+		if value == nil {
+			return nil, nil
+		}
+		items, ok := value.([]any)
+		if !ok {
+			items = []any{value} // a single value is accepted for a list
+		}
+		list := make([]any, 0, len(items))
+		for i, item := range items {
+			coerced, err := coerceInput(t.OfType, item, variables)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			list = append(list, coerced)
+		}
+		return list, nil
+	case *Scalar:
+		if value == nil {
+			return nil, nil
+		}
+		if t == JSON {
+			return plain(value, variables), nil
+		}
+		return t.Coerce(value)
+	}
+	return nil, fmt.Errorf("%s is not an input type", t)
+}
+
+// plain drops the literal types of the parser out of a JSON value
+func plain(value Value, variables map[string]any) any {
+	switch v := value.(type) {
+	case Variable:
+		return variables[string(v)]
+	case EnumValue:
+		return string(v)
+	case []any:
+		list := make([]any, len(v))
+		for i, item := range v {
+			list[i] = plain(item, variables)
+		}
+		return list
+	case map[string]any:
+		object := make(map[string]any, len(v))
+		for key, item := range v {
+			object[key] = plain(item, variables)
+		}
+		return object
+	}
+	return value
+}
+
+// coerceArguments fills in the defaults and checks the required arguments, the arguments left out without default are absent
+func coerceArguments(args []*Argument, values map[string]Value, variables map[string]any) (map[string]any, error) {
+	coerced := make(map[string]any, len(args))
+	for _, arg := range args {
+		value, ok := values[arg.Name]
+		if name, isVariable := value.(Variable); isVariable {
+			_, ok = variables[string(name)]
+		}
+		if !ok {
+			if arg.Default != nil {
+				coerced[arg.Name] = arg.Default
+				continue
+			}
+			if _, required := arg.Type.(*NonNull); required {
+				return nil, fmt.Errorf("argument %q of type %s is required", arg.Name, arg.Type)
+			}
+			continue
+		}
+		v, err := coerceInput(arg.Type, value, variables)
+		if err != nil {
+			return nil, fmt.Errorf("argument %q: %w", arg.Name, err)
+		}
+		coerced[arg.Name] = v
+	}
+	return coerced, nil
+}
+
+var directiveArgs = []*Argument{{Name: "if", Type: &NonNull{OfType: Boolean}}}
+
+// included applies @skip and @include
+func included(directives []*Directive, variables map[string]any) (bool, error) {
+	for _, d := range directives {
+		if d.Name != "skip" && d.Name != "include" {
+			return false, fmt.Errorf("unknown directive @%s", d.Name)
+		}
+		args, err := coerceArguments(directiveArgs, d.Arguments, variables)
+		if err != nil {
+			return false, fmt.Errorf("@%s: %w", d.Name, err)
+		}
+		if args["if"] == (d.Name == "skip") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// validator walks the operation against the schema before anything is resolved so that a bad query never reaches the carriers.
+// It adds up the cost of the fields on the way, the fragments count every time they are spread
+type validator struct {
+	schema    *Schema
+	doc       *Document
+	variables map[string]any
+	defined   map[string]bool
+	cost      int
+}
+
+func (v *validator) operation(operation *Operation) error {
+	if err := v.selectionSet(v.schema.Query, operation.SelectionSet, make(map[string]bool), 1); err != nil {
+		return err
+	}
+	if v.schema.MaxCost > 0 && v.cost > v.schema.MaxCost {
+		return fmt.Errorf("query costs %d, more than the limit of %d", v.cost, v.schema.MaxCost)
+	}
+	return nil
+}
+
+func (v *validator) selectionSet(o *Object, selections []Selection, visiting map[string]bool, depth int) error {
+	if v.schema.MaxDepth > 0 && depth > v.schema.MaxDepth {
+		return fmt.Errorf("query is nested deeper than %d levels", v.schema.MaxDepth)
+	}
+	for _, selection := range selections {
+		if _, err := included(selection.directives(), v.variables); err != nil {
+			return err
+		}
+		switch s := selection.(type) {
+		case *FieldSelection:
+			if err := v.field(o, s, visiting, depth); err != nil {
+				return err
+			}
+		case *InlineFragment:
+			if s.TypeCondition != "" && s.TypeCondition != o.Name {
+				return fmt.Errorf("fragment on %q can not be spread on type %q", s.TypeCondition, o.Name)
+			}
+			if err := v.selectionSet(o, s.SelectionSet, visiting, depth); err != nil {
+				return err
+			}
+		case *FragmentSpread:
+			fragment, ok := v.doc.Fragments[s.Name]
+			if !ok {
+				return fmt.Errorf("unknown fragment %q", s.Name)
+			}
+			if visiting[s.Name] {
+				return fmt.Errorf("fragment %q spreads itself", s.Name)
+			}
+			if fragment.TypeCondition != o.Name {
+				return fmt.Errorf("fragment %q on %q can not be spread on type %q", s.Name, fragment.TypeCondition, o.Name)
+			}
+			visiting[s.Name] = true
+			err := v.selectionSet(o, fragment.SelectionSet, visiting, depth)
+			delete(visiting, s.Name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *validator) field(o *Object, s *FieldSelection, visiting map[string]bool, depth int) error {
+	if s.Name == "__typename" {
+		if len(s.Arguments) > 0 || s.SelectionSet != nil {
+			return fmt.Errorf("field \"__typename\" takes no argument and no selection")
+		}
+		return nil
+	}
+	f, ok := o.Fields[s.Name]
+	if !ok {
+		return fmt.Errorf("cannot query field %q on type %q", s.Name, o.Name)
+	}
+	v.cost += 1 + f.Cost
+	for name, value := range s.Arguments {
+		if !hasArgument(f.Args, name) {
+			return fmt.Errorf("unknown argument %q on field %q of type %q", name, s.Name, o.Name)
+		}
+		if err := v.variableUse(value); err != nil {
+			return err
+		}
+	}
+	if _, err := coerceArguments(f.Args, s.Arguments, v.variables); err != nil {
+		return fmt.Errorf("field %q of type %q: %w", s.Name, o.Name, err)
+	}
+	object, isObject := unwrap(f.Type).(*Object)
+	switch {
+	case isObject && s.SelectionSet == nil:
+		return fmt.Errorf("field %q of type %s must have a selection of subfields", s.Name, f.Type)
+	case !isObject && s.SelectionSet != nil:
+		return fmt.Errorf("field %q of type %s can not have a selection of subfields", s.Name, f.Type)
+	case isObject:
+		return v.selectionSet(object, s.SelectionSet, visiting, depth+1)
+	}
+	return nil
+}
+
+// variableUse rejects the variables the operation does not define
+func (v *validator) variableUse(value Value) error {
+	switch value := value.(type) {
+	case Variable:
+		if !v.defined[string(value)] {
+			return fmt.Errorf("variable \"$%s\" is not defined", value)
+		}
+	case []any:
+		for _, item := range value {
+			if err := v.variableUse(item); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		for _, item := range value {
+			if err := v.variableUse(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func hasArgument(args []*Argument, name string) bool {
+	for _, arg := range args {
+		if arg.Name == name {
+			return true
+		}
+	}
+	return false
+}
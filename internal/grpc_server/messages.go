@@ -0,0 +1,197 @@
+package grpc_server
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative schedulehub.proto
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/neckchi/schedulehub/internal/schema"
+	"google.golang.org/protobuf/proto"
+)
+
+func pointBaseMessage(p *schema.PointBase) *PointBase {
+	if p == nil {
+		return nil
+	}
+	return &PointBase{
+		LocationName: p.LocationName,
+		LocationCode: p.LocationCode,
+		TerminalName: p.TerminalName,
+		TerminalCode: p.TerminalCode,
+		TimeZone:     p.TimeZone,
+	}
+}
+
+func legMessage(leg *schema.Leg) *Leg {
+	message := &Leg{
+		PointFrom:   pointBaseMessage(leg.PointFrom),
+		PointTo:     pointBaseMessage(leg.PointTo),
+		Etd:         leg.Etd,
+		Eta:         leg.Eta,
+		EtdUtc:      leg.EtdUTC,
+		EtaUtc:      leg.EtaUTC,
+		TransitTime: int32(leg.TransitTime),
+		Transportations: &Transportation{
+			TransportType: string(leg.Transportations.TransportType),
+			TransportName: leg.Transportations.TransportName,
+			ReferenceType: leg.Transportations.ReferenceType,
+			Reference:     leg.Transportations.Reference,
+		},
+		Scac: leg.Scac,
+	}
+	if c := leg.Cutoffs; c != nil {
+		message.Cutoffs = &Cutoff{CyCutoffDate: c.CyCutoffDate, DocCutoffDate: c.DocCutoffDate, VgmCutoffDate: c.VgmCutoffDate}
+	}
+	if v := leg.Voyages; v != nil {
+		message.Voyages = &Voyage{InternalVoyage: v.InternalVoyage, ExternalVoyage: v.ExternalVoyage}
+	}
+	if s := leg.Services; s != nil {
+		message.Services = &Service{ServiceCode: s.ServiceCode, ServiceName: s.ServiceName}
+	}
+	return message
+}
+
+func p2pScheduleMessage(s *schema.P2PSchedule) *P2PSchedule {
+	message := &P2PSchedule{
+		ScheduleId:    s.ScheduleID,
+		Scac:          s.Scac,
+		PointFrom:     s.PointFrom,
+		PointTo:       s.PointTo,
+		Etd:           s.Etd,
+		Eta:           s.Eta,
+		EtdUtc:        s.EtdUTC,
+		EtaUtc:        s.EtaUTC,
+		TransitTime:   int32(s.TransitTime),
+		Transshipment: s.Transshipment,
+		Legs:          make([]*Leg, 0, len(s.Legs)),
+		OfferedBy:     s.OfferedBy,
+		Lane:          s.Lane,
+		Interline:     s.Interline,
+	}
+	for _, leg := range s.Legs {
+		if leg != nil {
+			message.Legs = append(message.Legs, legMessage(leg))
+		}
+	}
+	return message
+}
+
+// anyString keeps a string as it is, the other shapes the carriers send(numbers, lists) go out as json
+func anyString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+func servicesMessage(s *schema.Services) *Services {
+	if s == nil {
+		return nil
+	}
+	return &Services{ServiceCode: s.ServiceCode, ServiceName: s.ServiceName}
+}
+
+func portCallsMessage(call *schema.PortCalls) *PortCalls {
+	message := &PortCalls{
+		Seq:                   int32(call.Seq),
+		Key:                   anyString(call.Key),
+		Bound:                 anyString(call.Bound),
+		Voyage:                anyString(call.Voyage),
+		Service:               servicesMessage(call.Service),
+		PortEvent:             call.PortEvent,
+		PlannedEventDate:      call.PlannedEventDate,
+		EstimatedEventDate:    call.EstimatedEventDate,
+		ActualEventDate:       call.ActualEventDate,
+		EstimatedEventDateUtc: call.EstimatedEventDateUTC,
+		ActualEventDateUtc:    call.ActualEventDateUTC,
+	}
+	if p := call.Port; p != nil {
+		message.Port = &Port{PortName: p.PortName, PortCode: p.PortCode, TerminalName: p.TerminalName, TerminalCode: p.TerminalCode, TimeZone: p.TimeZone}
+	}
+	return message
+}
+
+func masterVesselScheduleMessage(s *schema.MasterVesselSchedule) *MasterVesselSchedule {
+	message := &MasterVesselSchedule{
+		Scac:       s.Scac,
+		Voyage:     s.Voyage,
+		NextVoyage: s.NextVoyage,
+		Services:   servicesMessage(s.Services),
+		Calls:      make([]*PortCalls, 0, len(s.Calls)),
+	}
+	if v := s.Vessel; v != nil {
+		message.Vessel = &VesselDetails{VesselName: v.VesselName, Imo: v.Imo}
+	}
+	for i := range s.Calls {
+		message.Calls = append(message.Calls, portCallsMessage(&s.Calls[i]))
+	}
+	return message
+}
+
+// decodeQueryParams reads the QueryParams message, the defaults of the REST api apply to the fields left out
+func decodeQueryParams(data []byte) (schema.QueryParams, error) {
+	q := schema.QueryParams{StartDateType: schema.Departure, SearchRange: 4}
+	var message QueryParams
+	if err := proto.Unmarshal(data, &message); err != nil {
+		return q, fmt.Errorf("QueryParams: %w", err)
+	}
+	q.PointFrom = message.PointFrom
+	q.PointTo = message.PointTo
+	if message.StartDateType != "" {
+		q.StartDateType = schema.StartDateType(message.StartDateType)
+	}
+	q.StartDate = message.StartDate
+	if message.SearchRange != 0 {
+		q.SearchRange = int(message.SearchRange)
+	}
+	for _, scac := range message.Scac {
+		q.SCAC = append(q.SCAC, schema.CarrierCode(scac))
+	}
+	q.DirectOnly = message.DirectOnly
+	q.TSP = message.TranshipmentPort
+	q.VesselIMO = message.VesselImo
+	q.Service = message.Service
+	q.ServiceName = message.ServiceName
+	q.VesselName = message.VesselName
+	if message.MaxTransitTime != nil {
+		maxTransit := int(*message.MaxTransitTime)
+		q.MaxTransit = &maxTransit
+	}
+	if message.MaxTransshipments != nil {
+		maxTSP := int(*message.MaxTransshipments)
+		q.MaxTSP = &maxTSP
+	}
+	q.ExcludeTSP = message.ExcludeTransshipment
+	for _, mode := range message.TransportModes {
+		q.Modes = append(q.Modes, schema.TransportType(mode))
+	}
+	q.CutoffAfter = message.CutoffAfter
+	q.SortBy = schema.SortBy(message.SortBy)
+	q.Dedupe = message.Dedupe
+	q.Connections = message.Connections
+	return q, nil
+}
+
+func decodeVesselVoyageQuery(data []byte) (schema.QueryParamsForVesselVoyage, error) {
+	var q schema.QueryParamsForVesselVoyage
+	var message VesselVoyageQuery
+	if err := proto.Unmarshal(data, &message); err != nil {
+		return q, fmt.Errorf("VesselVoyageQuery: %w", err)
+	}
+	for _, scac := range message.Scac {
+		q.SCAC = append(q.SCAC, schema.CarrierCode(scac))
+	}
+	q.VesselIMO = message.VesselImo
+	q.StartDate = message.StartDate
+	q.DateRange = int(message.DateRange)
+	q.Voyage = message.VoyageNum
+	return q, nil
+}
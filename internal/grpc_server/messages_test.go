@@ -0,0 +1,237 @@
+package grpc_server
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/neckchi/schedulehub/internal/schema"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// assertJSON compares the message with the expected protojson, which randomizes its whitespace
+func assertJSON(t *testing.T, message proto.Message, want string) {
+	t.Helper()
+	encoded, err := protojson.Marshal(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, expected any
+	if err := json.Unmarshal(encoded, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(want), &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("decoded %s\nwant %s", encoded, want)
+	}
+}
+
+func TestP2PScheduleMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule *schema.P2PSchedule
+		want     string
+	}{
+		{
+			name:     "empty",
+			schedule: &schema.P2PSchedule{},
+			want:     `{}`,
+		},
+		{
+			name: "every field",
+			schedule: &schema.P2PSchedule{
+				ScheduleID: "0123456789abcdef0123456789abcdef", Scac: "MAEU", PointFrom: "CNSHA", PointTo: "DEHAM",
+				Etd: "2024-03-10T08:00:00", Eta: "2024-04-12T06:00:00", EtdUTC: "2024-03-10T00:00:00Z", EtaUTC: "2024-04-12T04:00:00Z",
+				TransitTime: 33, Transshipment: true, OfferedBy: []string{"MAEU", "MSCU"}, Lane: "CNSHA-DEHAM", Interline: true,
+				Legs: []*schema.Leg{{
+					PointFrom:       &schema.PointBase{LocationName: "Shanghai", LocationCode: "CNSHA", TerminalName: "Yangshan", TerminalCode: "YS", TimeZone: "Asia/Shanghai"},
+					PointTo:         &schema.PointBase{LocationCode: "SGSIN"},
+					Etd:             "2024-03-10T08:00:00",
+					Eta:             "2024-03-16T06:00:00",
+					EtdUTC:          "2024-03-10T00:00:00Z",
+					EtaUTC:          "2024-03-15T22:00:00Z",
+					TransitTime:     6,
+					Cutoffs:         &schema.Cutoff{CyCutoffDate: "2024-03-08T12:00:00", DocCutoffDate: "2024-03-07T12:00:00", VgmCutoffDate: "2024-03-08T08:00:00"},
+					Transportations: schema.Transportation{TransportType: schema.Vessel, TransportName: "EVER ACE", ReferenceType: "IMO", Reference: "9893890"},
+					Voyages:         &schema.Voyage{InternalVoyage: "001W", ExternalVoyage: "E001"},
+					Services:        &schema.Service{ServiceCode: "AE7", ServiceName: "Asia Europe 7"},
+					Scac:            "MAEU",
+				}},
+			},
+			want: `{
+				"scheduleId": "0123456789abcdef0123456789abcdef", "scac": "MAEU", "pointFrom": "CNSHA", "pointTo": "DEHAM",
+				"etd": "2024-03-10T08:00:00", "eta": "2024-04-12T06:00:00", "etdUtc": "2024-03-10T00:00:00Z", "etaUtc": "2024-04-12T04:00:00Z",
+				"transitTime": 33, "transshipment": true, "offeredBy": ["MAEU", "MSCU"], "lane": "CNSHA-DEHAM", "interline": true,
+				"legs": [{
+					"pointFrom": {"locationName": "Shanghai", "locationCode": "CNSHA", "terminalName": "Yangshan", "terminalCode": "YS", "timeZone": "Asia/Shanghai"},
+					"pointTo": {"locationCode": "SGSIN"},
+					"etd": "2024-03-10T08:00:00", "eta": "2024-03-16T06:00:00", "etdUtc": "2024-03-10T00:00:00Z", "etaUtc": "2024-03-15T22:00:00Z",
+					"transitTime": 6,
+					"cutoffs": {"cyCutoffDate": "2024-03-08T12:00:00", "docCutoffDate": "2024-03-07T12:00:00", "vgmCutoffDate": "2024-03-08T08:00:00"},
+					"transportations": {"transportType": "Vessel", "transportName": "EVER ACE", "referenceType": "IMO", "reference": "9893890"},
+					"voyages": {"internalVoyage": "001W", "externalVoyage": "E001"},
+					"services": {"serviceCode": "AE7", "serviceName": "Asia Europe 7"},
+					"scac": "MAEU"
+				}]
+			}`,
+		},
+		{
+			name:     "negative transit time and an empty leg",
+			schedule: &schema.P2PSchedule{TransitTime: -1, Legs: []*schema.Leg{{}}},
+			want:     `{"transitTime": -1, "legs": [{"transportations": {}}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertJSON(t, p2pScheduleMessage(tt.schedule), tt.want)
+		})
+	}
+}
+
+func TestMasterVesselScheduleMessage(t *testing.T) {
+	schedule := &schema.MasterVesselSchedule{
+		Scac: "MAEU", Voyage: "412W", NextVoyage: "413W",
+		Vessel:   &schema.VesselDetails{VesselName: "MAERSK ESSEN", Imo: "9456771"},
+		Services: &schema.Services{ServiceCode: "AE1", ServiceName: "Asia Europe 1"},
+		Calls: []schema.PortCalls{
+			{
+				Seq: 1, Key: "SGSIN-1", Bound: "W", Voyage: "412W", PortEvent: "Loading",
+				Service:          &schema.Services{ServiceCode: "AE1"},
+				Port:             &schema.Port{PortName: "Singapore", PortCode: "SGSIN", TerminalName: "PSA", TerminalCode: "PPT", TimeZone: "Asia/Singapore"},
+				PlannedEventDate: "2024-03-09T08:00:00", EstimatedEventDate: "2024-03-10T08:00:00", ActualEventDate: "2024-03-10T09:00:00",
+				EstimatedEventDateUTC: "2024-03-10T00:00:00Z", ActualEventDateUTC: "2024-03-10T01:00:00Z",
+			},
+			{Seq: 2, Key: 7, Bound: nil, Voyage: []string{"412W", "413E"}, PortEvent: "Unloading"},
+		},
+	}
+	want := `{
+		"scac": "MAEU", "voyage": "412W", "nextVoyage": "413W",
+		"vessel": {"vesselName": "MAERSK ESSEN", "imo": "9456771"},
+		"services": {"serviceCode": "AE1", "serviceName": "Asia Europe 1"},
+		"calls": [
+			{
+				"seq": 1, "key": "SGSIN-1", "bound": "W", "voyage": "412W", "portEvent": "Loading",
+				"service": {"serviceCode": "AE1"},
+				"port": {"portName": "Singapore", "portCode": "SGSIN", "terminalName": "PSA", "terminalCode": "PPT", "timeZone": "Asia/Singapore"},
+				"plannedEventDate": "2024-03-09T08:00:00", "estimatedEventDate": "2024-03-10T08:00:00", "actualEventDate": "2024-03-10T09:00:00",
+				"estimatedEventDateUtc": "2024-03-10T00:00:00Z", "actualEventDateUtc": "2024-03-10T01:00:00Z"
+			},
+			{"seq": 2, "key": "7", "voyage": "[\"412W\",\"413E\"]", "portEvent": "Unloading"}
+		]
+	}`
+	assertJSON(t, masterVesselScheduleMessage(schedule), want)
+}
+
+// marshal builds the request the way a client would, out of its protojson form
+func marshal(t *testing.T, message proto.Message, request string) []byte {
+	t.Helper()
+	if err := protojson.Unmarshal([]byte(request), message); err != nil {
+		t.Fatalf("protojson.Unmarshal(%s) error = %v", request, err)
+	}
+	data, err := proto.Marshal(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func intPointer(value int) *int {
+	return &value
+}
+
+func TestDecodeQueryParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		request string
+		want    schema.QueryParams
+	}{
+		{
+			name:    "defaults of the rest api",
+			request: `{"pointFrom": "CNSHA", "pointTo": "DEHAM", "startDate": "2024-03-10"}`,
+			want:    schema.QueryParams{PointFrom: "CNSHA", PointTo: "DEHAM", StartDate: "2024-03-10", StartDateType: schema.Departure, SearchRange: 4},
+		},
+		{
+			name: "every field",
+			request: `{
+				"pointFrom": "CNSHA,CNNGB", "pointTo": "DEHAM", "startDateType": "Arrival", "startDate": "2024-03-10", "searchRange": 8,
+				"scac": ["MAEU", "MSCU"], "directOnly": true, "transhipmentPort": "SGSIN", "vesselImo": "9893890", "service": "AE7",
+				"serviceName": "Asia Europe 7", "vesselName": "EVER ACE", "maxTransitTime": 40, "maxTransshipments": 0,
+				"excludeTransshipment": ["LKCMB"], "transportModes": ["Vessel", "Barge"], "cutoffAfter": "2024-03-08",
+				"sortBy": "transitTime", "dedupe": true, "connections": true
+			}`,
+			want: schema.QueryParams{
+				PointFrom: "CNSHA,CNNGB", PointTo: "DEHAM", StartDateType: schema.Arrival, StartDate: "2024-03-10", SearchRange: 8,
+				SCAC: []schema.CarrierCode{"MAEU", "MSCU"}, DirectOnly: true, TSP: "SGSIN", VesselIMO: "9893890", Service: "AE7",
+				ServiceName: "Asia Europe 7", VesselName: "EVER ACE", MaxTransit: intPointer(40), MaxTSP: intPointer(0),
+				ExcludeTSP: []string{"LKCMB"}, Modes: []schema.TransportType{schema.Vessel, schema.Barge}, CutoffAfter: "2024-03-08",
+				SortBy: schema.SortBy("transitTime"), Dedupe: true, Connections: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeQueryParams(marshal(t, &QueryParams{}, tt.request))
+			if err != nil {
+				t.Fatalf("decodeQueryParams() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeQueryParams() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeVesselVoyageQuery(t *testing.T) {
+	request := `{"scac": ["MAEU", "CMDU"], "vesselImo": "9456771", "startDate": "2024-03-10", "dateRange": 7, "voyageNum": "412W"}`
+	want := schema.QueryParamsForVesselVoyage{
+		SCAC: []schema.CarrierCode{"MAEU", "CMDU"}, VesselIMO: "9456771", StartDate: "2024-03-10", DateRange: 7, Voyage: "412W",
+	}
+	got, err := decodeVesselVoyageQuery(marshal(t, &VesselVoyageQuery{}, request))
+	if err != nil {
+		t.Fatalf("decodeVesselVoyageQuery() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeVesselVoyageQuery() = %+v\nwant %+v", got, want)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "truncated varint", data: []byte{0x28, 0x80}},
+		{name: "truncated string", data: []byte{0x0a, 0x05, 'C', 'N'}},
+		{name: "invalid utf-8 string", data: []byte{0x0a, 0x02, 0xff, 0xfe}},
+		{name: "field number zero", data: []byte{0x00, 0x01}},
+		{name: "group without its end", data: []byte{0xfb, 0x01}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeQueryParams(tt.data); err == nil {
+				t.Errorf("decodeQueryParams(%x) succeeded, want an error", tt.data)
+			}
+		})
+	}
+}
+
+func TestDecodeSkipsUnknownFields(t *testing.T) {
+	// field 99 of every wire type followed by point_from
+	data := []byte{
+		0x98, 0x06, 0x01, // varint
+		0x99, 0x06, 1, 2, 3, 4, 5, 6, 7, 8, // 64-bit
+		0x9a, 0x06, 0x02, 'x', 'y', // bytes
+		0x9d, 0x06, 1, 2, 3, 4, // 32-bit
+		0x0a, 0x05, 'C', 'N', 'S', 'H', 'A',
+	}
+	got, err := decodeQueryParams(data)
+	if err != nil {
+		t.Fatalf("decodeQueryParams() error = %v", err)
+	}
+	if got.PointFrom != "CNSHA" {
+		t.Errorf("PointFrom = %q, want CNSHA", got.PointFrom)
+	}
+}
@@ -0,0 +1,1603 @@
+// Contract of the gRPC server(port 8009). The messages mirror the json of the REST apis field by field. schedulehub.pb.go is
+// generated from it(go generate ./internal/grpc_server), messages.go maps the schema types onto the generated ones.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.5
+// 	protoc        (unknown)
+// source: schedulehub.proto
+
+package grpc_server
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type QueryParams struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	PointFrom            string                 `protobuf:"bytes,1,opt,name=point_from,json=pointFrom,proto3" json:"point_from,omitempty"` // port, port group or comma separated list
+	PointTo              string                 `protobuf:"bytes,2,opt,name=point_to,json=pointTo,proto3" json:"point_to,omitempty"`
+	StartDateType        string                 `protobuf:"bytes,3,opt,name=start_date_type,json=startDateType,proto3" json:"start_date_type,omitempty"` // Departure(default) or Arrival
+	StartDate            string                 `protobuf:"bytes,4,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`               // YYYY-MM-DD
+	SearchRange          int32                  `protobuf:"varint,5,opt,name=search_range,json=searchRange,proto3" json:"search_range,omitempty"`        // weeks, 4 by default
+	Scac                 []string               `protobuf:"bytes,6,rep,name=scac,proto3" json:"scac,omitempty"`
+	DirectOnly           bool                   `protobuf:"varint,7,opt,name=direct_only,json=directOnly,proto3" json:"direct_only,omitempty"`
+	TranshipmentPort     string                 `protobuf:"bytes,8,opt,name=transhipment_port,json=transhipmentPort,proto3" json:"transhipment_port,omitempty"`
+	VesselImo            string                 `protobuf:"bytes,9,opt,name=vessel_imo,json=vesselImo,proto3" json:"vessel_imo,omitempty"`
+	Service              string                 `protobuf:"bytes,10,opt,name=service,proto3" json:"service,omitempty"`
+	ServiceName          string                 `protobuf:"bytes,11,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	VesselName           string                 `protobuf:"bytes,12,opt,name=vessel_name,json=vesselName,proto3" json:"vessel_name,omitempty"`
+	MaxTransitTime       *int32                 `protobuf:"varint,13,opt,name=max_transit_time,json=maxTransitTime,proto3,oneof" json:"max_transit_time,omitempty"`
+	MaxTransshipments    *int32                 `protobuf:"varint,14,opt,name=max_transshipments,json=maxTransshipments,proto3,oneof" json:"max_transshipments,omitempty"`
+	ExcludeTransshipment []string               `protobuf:"bytes,15,rep,name=exclude_transshipment,json=excludeTransshipment,proto3" json:"exclude_transshipment,omitempty"`
+	TransportModes       []string               `protobuf:"bytes,16,rep,name=transport_modes,json=transportModes,proto3" json:"transport_modes,omitempty"`
+	CutoffAfter          string                 `protobuf:"bytes,17,opt,name=cutoff_after,json=cutoffAfter,proto3" json:"cutoff_after,omitempty"`
+	SortBy               string                 `protobuf:"bytes,18,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	Dedupe               bool                   `protobuf:"varint,19,opt,name=dedupe,proto3" json:"dedupe,omitempty"`
+	Connections          bool                   `protobuf:"varint,20,opt,name=connections,proto3" json:"connections,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *QueryParams) Reset() {
+	*x = QueryParams{}
+	mi := &file_schedulehub_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryParams) ProtoMessage() {}
+
+func (x *QueryParams) ProtoReflect() protoreflect.Message {
+	mi := &file_schedulehub_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryParams.ProtoReflect.Descriptor instead.
+func (*QueryParams) Descriptor() ([]byte, []int) {
+	return file_schedulehub_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *QueryParams) GetPointFrom() string {
+	if x != nil {
+		return x.PointFrom
+	}
+	return ""
+}
+
+func (x *QueryParams) GetPointTo() string {
+	if x != nil {
+		return x.PointTo
+	}
+	return ""
+}
+
+func (x *QueryParams) GetStartDateType() string {
+	if x != nil {
+		return x.StartDateType
+	}
+	return ""
+}
+
+func (x *QueryParams) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *QueryParams) GetSearchRange() int32 {
+	if x != nil {
+		return x.SearchRange
+	}
+	return 0
+}
+
+func (x *QueryParams) GetScac() []string {
+	if x != nil {
+		return x.Scac
+	}
+	return nil
+}
+
+func (x *QueryParams) GetDirectOnly() bool {
+	if x != nil {
+		return x.DirectOnly
+	}
+	return false
+}
+
+func (x *QueryParams) GetTranshipmentPort() string {
+	if x != nil {
+		return x.TranshipmentPort
+	}
+	return ""
+}
+
+func (x *QueryParams) GetVesselImo() string {
+	if x != nil {
+		return x.VesselImo
+	}
+	return ""
+}
+
+func (x *QueryParams) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *QueryParams) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *QueryParams) GetVesselName() string {
+	if x != nil {
+		return x.VesselName
+	}
+	return ""
+}
+
+func (x *QueryParams) GetMaxTransitTime() int32 {
+	if x != nil && x.MaxTransitTime != nil {
+		return *x.MaxTransitTime
+	}
+	return 0
+}
+
+func (x *QueryParams) GetMaxTransshipments() int32 {
+	if x != nil && x.MaxTransshipments != nil {
+		return *x.MaxTransshipments
+	}
+	return 0
+}
+
+func (x *QueryParams) GetExcludeTransshipment() []string {
+	if x != nil {
+		return x.ExcludeTransshipment
+	}
+	return nil
+}
+
+func (x *QueryParams) GetTransportModes() []string {
+	if x != nil {
+		return x.TransportModes
+	}
+	return nil
+}
+
+func (x *QueryParams) GetCutoffAfter() string {
+	if x != nil {
+		return x.CutoffAfter
+	}
+	return ""
+}
+
+func (x *QueryParams) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *QueryParams) GetDedupe() bool {
+	if x != nil {
+		return x.Dedupe
+	}
+	return false
+}
+
+func (x *QueryParams) GetConnections() bool {
+	if x != nil {
+		return x.Connections
+	}
+	return false
+}
+
+type PointBase struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LocationName  string                 `protobuf:"bytes,1,opt,name=location_name,json=locationName,proto3" json:"location_name,omitempty"`
+	LocationCode  string                 `protobuf:"bytes,2,opt,name=location_code,json=locationCode,proto3" json:"location_code,omitempty"`
+	TerminalName  string                 `protobuf:"bytes,3,opt,name=terminal_name,json=terminalName,proto3" json:"terminal_name,omitempty"`
+	TerminalCode  string                 `protobuf:"bytes,4,opt,name=terminal_code,json=terminalCode,proto3" json:"terminal_code,omitempty"`
+	TimeZone      string                 `protobuf:"bytes,5,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PointBase) Reset() {
+	*x = PointBase{}
+	mi := &file_schedulehub_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PointBase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PointBase) ProtoMessage() {}
+
+func (x *PointBase) ProtoReflect() protoreflect.Message {
+	mi := &file_schedulehub_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PointBase.ProtoReflect.Descriptor instead.
+func (*PointBase) Descriptor() ([]byte, []int) {
+	return file_schedulehub_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PointBase) GetLocationName() string {
+	if x != nil {
+		return x.LocationName
+	}
+	return ""
+}
+
+func (x *PointBase) GetLocationCode() string {
+	if x != nil {
+		return x.LocationCode
+	}
+	return ""
+}
+
+func (x *PointBase) GetTerminalName() string {
+	if x != nil {
+		return x.TerminalName
+	}
+	return ""
+}
+
+func (x *PointBase) GetTerminalCode() string {
+	if x != nil {
+		return x.TerminalCode
+	}
+	return ""
+}
+
+func (x *PointBase) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+type Cutoff struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CyCutoffDate  string                 `protobuf:"bytes,1,opt,name=cy_cutoff_date,json=cyCutoffDate,proto3" json:"cy_cutoff_date,omitempty"`
+	DocCutoffDate string                 `protobuf:"bytes,2,opt,name=doc_cutoff_date,json=docCutoffDate,proto3" json:"doc_cutoff_date,omitempty"`
+	VgmCutoffDate string                 `protobuf:"bytes,3,opt,name=vgm_cutoff_date,json=vgmCutoffDate,proto3" json:"vgm_cutoff_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cutoff) Reset() {
+	*x = Cutoff{}
+	mi := &file_schedulehub_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cutoff) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cutoff) ProtoMessage() {}
+
+func (x *Cutoff) ProtoReflect() protoreflect.Message {
+	mi := &file_schedulehub_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cutoff.ProtoReflect.Descriptor instead.
+func (*Cutoff) Descriptor() ([]byte, []int) {
+	return file_schedulehub_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Cutoff) GetCyCutoffDate() string {
+	if x != nil {
+		return x.CyCutoffDate
+	}
+	return ""
+}
+
+func (x *Cutoff) GetDocCutoffDate() string {
+	if x != nil {
+		return x.DocCutoffDate
+	}
+	return ""
+}
+
+func (x *Cutoff) GetVgmCutoffDate() string {
+	if x != nil {
+		return x.VgmCutoffDate
+	}
+	return ""
+}
+
+type Transportation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransportType string                 `protobuf:"bytes,1,opt,name=transport_type,json=transportType,proto3" json:"transport_type,omitempty"`
+	TransportName string                 `protobuf:"bytes,2,opt,name=transport_name,json=transportName,proto3" json:"transport_name,omitempty"`
+	ReferenceType string                 `protobuf:"bytes,3,opt,name=reference_type,json=referenceType,proto3" json:"reference_type,omitempty"`
+	Reference     string                 `protobuf:"bytes,4,opt,name=reference,proto3" json:"reference,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Transportation) Reset() {
+	*x = Transportation{}
+	mi := &file_schedulehub_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transportation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transportation) ProtoMessage() {}
+
+func (x *Transportation) ProtoReflect() protoreflect.Message {
+	mi := &file_schedulehub_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transportation.ProtoReflect.Descriptor instead.
+func (*Transportation) Descriptor() ([]byte, []int) {
+	return file_schedulehub_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Transportation) GetTransportType() string {
+	if x != nil {
+		return x.TransportType
+	}
+	return ""
+}
+
+func (x *Transportation) GetTransportName() string {
+	if x != nil {
+		return x.TransportName
+	}
+	return ""
+}
+
+func (x *Transportation) GetReferenceType() string {
+	if x != nil {
+		return x.ReferenceType
+	}
+	return ""
+}
+
+func (x *Transportation) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+type Voyage struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	InternalVoyage string                 `protobuf:"bytes,1,opt,name=internal_voyage,json=internalVoyage,proto3" json:"internal_voyage,omitempty"`
+	ExternalVoyage string                 `protobuf:"bytes,2,opt,name=external_voyage,json=externalVoyage,proto3" json:"external_voyage,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Voyage) Reset() {
+	*x = Voyage{}
+	mi := &file_schedulehub_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Voyage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Voyage) ProtoMessage() {}
+
+func (x *Voyage) ProtoReflect() protoreflect.Message {
+	mi := &file_schedulehub_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Voyage.ProtoReflect.Descriptor instead.
+func (*Voyage) Descriptor() ([]byte, []int) {
+	return file_schedulehub_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Voyage) GetInternalVoyage() string {
+	if x != nil {
+		return x.InternalVoyage
+	}
+	return ""
+}
+
+func (x *Voyage) GetExternalVoyage() string {
+	if x != nil {
+		return x.ExternalVoyage
+	}
+	return ""
+}
+
+type Service struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceCode   string                 `protobuf:"bytes,1,opt,name=service_code,json=serviceCode,proto3" json:"service_code,omitempty"`
+	ServiceName   string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Service) Reset() {
+	*x = Service{}
+	mi := &file_schedulehub_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Service) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Service) ProtoMessage() {}
+
+func (x *Service) ProtoReflect() protoreflect.Message {
+	mi := &file_schedulehub_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Service.ProtoReflect.Descriptor instead.
+func (*Service) Descriptor() ([]byte, []int) {
+	return file_schedulehub_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Service) GetServiceCode() string {
+	if x != nil {
+		return x.ServiceCode
+	}
+	return ""
+}
+
+func (x *Service) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+type Leg struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PointFrom       *PointBase             `protobuf:"bytes,1,opt,name=point_from,json=pointFrom,proto3" json:"point_from,omitempty"`
+	PointTo         *PointBase             `protobuf:"bytes,2,opt,name=point_to,json=pointTo,proto3" json:"point_to,omitempty"`
+	Etd             string                 `protobuf:"bytes,3,opt,name=etd,proto3" json:"etd,omitempty"`
+	Eta             string                 `protobuf:"bytes,4,opt,name=eta,proto3" json:"eta,omitempty"`
+	EtdUtc          string                 `protobuf:"bytes,5,opt,name=etd_utc,json=etdUtc,proto3" json:"etd_utc,omitempty"`
+	EtaUtc          string                 `protobuf:"bytes,6,opt,name=eta_utc,json=etaUtc,proto3" json:"eta_utc,omitempty"`
+	TransitTime     int32                  `protobuf:"varint,7,opt,name=transit_time,json=transitTime,proto3" json:"transit_time,omitempty"`
+	Cutoffs         *Cutoff                `protobuf:"bytes,8,opt,name=cutoffs,proto3" json:"cutoffs,omitempty"`
+	Transportations *Transportation        `protobuf:"bytes,9,opt,name=transportations,proto3" json:"transportations,omitempty"`
+	Voyages         *Voyage                `protobuf:"bytes,10,opt,name=voyages,proto3" json:"voyages,omitempty"`
+	Services        *Service               `protobuf:"bytes,11,opt,name=services,proto3" json:"services,omitempty"`
+	Scac            string                 `protobuf:"bytes,12,opt,name=scac,proto3" json:"scac,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Leg) Reset() {
+	*x = Leg{}
+	mi := &file_schedulehub_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Leg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Leg) ProtoMessage() {}
+
+func (x *Leg) ProtoReflect() protoreflect.Message {
+	mi := &file_schedulehub_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Leg.ProtoReflect.Descriptor instead.
+func (*Leg) Descriptor() ([]byte, []int) {
+	return file_schedulehub_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Leg) GetPointFrom() *PointBase {
+	if x != nil {
+		return x.PointFrom
+	}
+	return nil
+}
+
+func (x *Leg) GetPointTo() *PointBase {
+	if x != nil {
+		return x.PointTo
+	}
+	return nil
+}
+
+func (x *Leg) GetEtd() string {
+	if x != nil {
+		return x.Etd
+	}
+	return ""
+}
+
+func (x *Leg) GetEta() string {
+	if x != nil {
+		return x.Eta
+	}
+	return ""
+}
+
+func (x *Leg) GetEtdUtc() string {
+	if x != nil {
+		return x.EtdUtc
+	}
+	return ""
+}
+
+func (x *Leg) GetEtaUtc() string {
+	if x != nil {
+		return x.EtaUtc
+	}
+	return ""
+}
+
+func (x *Leg) GetTransitTime() int32 {
+	if x != nil {
+		return x.TransitTime
+	}
+	return 0
+}
+
+func (x *Leg) GetCutoffs() *Cutoff {
+	if x != nil {
+		return x.Cutoffs
+	}
+	return nil
+}
+
+func (x *Leg) GetTransportations() *Transportation {
+	if x != nil {
+		return x.Transportations
+	}
+	return nil
+}
+
+func (x *Leg) GetVoyages() *Voyage {
+	if x != nil {
+		return x.Voyages
+	}
+	return nil
+}
+
+func (x *Leg) GetServices() *Service {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+func (x *Leg) GetScac() string {
+	if x != nil {
+		return x.Scac
+	}
+	return ""
+}
+
+type P2PSchedule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId    string                 `protobuf:"bytes,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	Scac          string                 `protobuf:"bytes,2,opt,name=scac,proto3" json:"scac,omitempty"`
+	PointFrom     string                 `protobuf:"bytes,3,opt,name=point_from,json=pointFrom,proto3" json:"point_from,omitempty"`
+	PointTo       string                 `protobuf:"bytes,4,opt,name=point_to,json=pointTo,proto3" json:"point_to,omitempty"`
+	Etd           string                 `protobuf:"bytes,5,opt,name=etd,proto3" json:"etd,omitempty"`
+	Eta           string                 `protobuf:"bytes,6,opt,name=eta,proto3" json:"eta,omitempty"`
+	EtdUtc        string                 `protobuf:"bytes,7,opt,name=etd_utc,json=etdUtc,proto3" json:"etd_utc,omitempty"`
+	EtaUtc        string                 `protobuf:"bytes,8,opt,name=eta_utc,json=etaUtc,proto3" json:"eta_utc,omitempty"`
+	TransitTime   int32                  `protobuf:"varint,9,opt,name=transit_time,json=transitTime,proto3" json:"transit_time,omitempty"`
+	Transshipment bool                   `protobuf:"varint,10,opt,name=transshipment,proto3" json:"transshipment,omitempty"`
+	Legs          []*Leg                 `protobuf:"bytes,11,rep,name=legs,proto3" json:"legs,omitempty"`
+	OfferedBy     []string               `protobuf:"bytes,12,rep,name=offered_by,json=offeredBy,proto3" json:"offered_by,omitempty"`
+	Lane          string                 `protobuf:"bytes,13,opt,name=lane,proto3" json:"lane,omitempty"`
+	Interline     bool                   `protobuf:"varint,14,opt,name=interline,proto3" json:"interline,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *P2PSchedule) Reset() {
+	*x = P2PSchedule{}
+	mi := &file_schedulehub_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *P2PSchedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*P2PSchedule) ProtoMessage() {}
+
+func (x *P2PSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_schedulehub_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use P2PSchedule.ProtoReflect.Descriptor instead.
+func (*P2PSchedule) Descriptor() ([]byte, []int) {
+	return file_schedulehub_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *P2PSchedule) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+func (x *P2PSchedule) GetScac() string {
+	if x != nil {
+		return x.Scac
+	}
+	return ""
+}
+
+func (x *P2PSchedule) GetPointFrom() string {
+	if x != nil {
+		return x.PointFrom
+	}
+	return ""
+}
+
+func (x *P2PSchedule) GetPointTo() string {
+	if x != nil {
+		return x.PointTo
+	}
+	return ""
+}
+
+func (x *P2PSchedule) GetEtd() string {
+	if x != nil {
+		return x.Etd
+	}
+	return ""
+}
+
+func (x *P2PSchedule) GetEta() string {
+	if x != nil {
+		return x.Eta
+	}
+	return ""
+}
+
+func (x *P2PSchedule) GetEtdUtc() string {
+	if x != nil {
+		return x.EtdUtc
+	}
+	return ""
+}
+
+func (x *P2PSchedule) GetEtaUtc() string {
+	if x != nil {
+		return x.EtaUtc
+	}
+	return ""
+}
+
+func (x *P2PSchedule) GetTransitTime() int32 {
+	if x != nil {
+		return x.TransitTime
+	}
+	return 0
+}
+
+func (x *P2PSchedule) GetTransshipment() bool {
+	if x != nil {
+		return x.Transshipment
+	}
+	return false
+}
+
+func (x *P2PSchedule) GetLegs() []*Leg {
+	if x != nil {
+		return x.Legs
+	}
+	return nil
+}
+
+func (x *P2PSchedule) GetOfferedBy() []string {
+	if x != nil {
+		return x.OfferedBy
+	}
+	return nil
+}
+
+func (x *P2PSchedule) GetLane() string {
+	if x != nil {
+		return x.Lane
+	}
+	return ""
+}
+
+func (x *P2PSchedule) GetInterline() bool {
+	if x != nil {
+		return x.Interline
+	}
+	return false
+}
+
+type VesselVoyageQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Scac          []string               `protobuf:"bytes,1,rep,name=scac,proto3" json:"scac,omitempty"`
+	VesselImo     string                 `protobuf:"bytes,2,opt,name=vessel_imo,json=vesselImo,proto3" json:"vessel_imo,omitempty"`
+	StartDate     string                 `protobuf:"bytes,3,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`  // YYYY-MM-DD
+	DateRange     int32                  `protobuf:"varint,4,opt,name=date_range,json=dateRange,proto3" json:"date_range,omitempty"` // required with start_date
+	VoyageNum     string                 `protobuf:"bytes,5,opt,name=voyage_num,json=voyageNum,proto3" json:"voyage_num,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VesselVoyageQuery) Reset() {
+	*x = VesselVoyageQuery{}
+	mi := &file_schedulehub_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VesselVoyageQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VesselVoyageQuery) ProtoMessage() {}
+
+func (x *VesselVoyageQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_schedulehub_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VesselVoyageQuery.ProtoReflect.Descriptor instead.
+func (*VesselVoyageQuery) Descriptor() ([]byte, []int) {
+	return file_schedulehub_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *VesselVoyageQuery) GetScac() []string {
+	if x != nil {
+		return x.Scac
+	}
+	return nil
+}
+
+func (x *VesselVoyageQuery) GetVesselImo() string {
+	if x != nil {
+		return x.VesselImo
+	}
+	return ""
+}
+
+func (x *VesselVoyageQuery) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *VesselVoyageQuery) GetDateRange() int32 {
+	if x != nil {
+		return x.DateRange
+	}
+	return 0
+}
+
+func (x *VesselVoyageQuery) GetVoyageNum() string {
+	if x != nil {
+		return x.VoyageNum
+	}
+	return ""
+}
+
+type Port struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PortName      string                 `protobuf:"bytes,1,opt,name=port_name,json=portName,proto3" json:"port_name,omitempty"`
+	PortCode      string                 `protobuf:"bytes,2,opt,name=port_code,json=portCode,proto3" json:"port_code,omitempty"`
+	TerminalName  string                 `protobuf:"bytes,3,opt,name=terminal_name,json=terminalName,proto3" json:"terminal_name,omitempty"`
+	TerminalCode  string                 `protobuf:"bytes,4,opt,name=terminal_code,json=terminalCode,proto3" json:"terminal_code,omitempty"`
+	TimeZone      string                 `protobuf:"bytes,5,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Port) Reset() {
+	*x = Port{}
+	mi := &file_schedulehub_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Port) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Port) ProtoMessage() {}
+
+func (x *Port) ProtoReflect() protoreflect.Message {
+	mi := &file_schedulehub_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Port.ProtoReflect.Descriptor instead.
+func (*Port) Descriptor() ([]byte, []int) {
+	return file_schedulehub_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Port) GetPortName() string {
+	if x != nil {
+		return x.PortName
+	}
+	return ""
+}
+
+func (x *Port) GetPortCode() string {
+	if x != nil {
+		return x.PortCode
+	}
+	return ""
+}
+
+func (x *Port) GetTerminalName() string {
+	if x != nil {
+		return x.TerminalName
+	}
+	return ""
+}
+
+func (x *Port) GetTerminalCode() string {
+	if x != nil {
+		return x.TerminalCode
+	}
+	return ""
+}
+
+func (x *Port) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+type VesselDetails struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VesselName    string                 `protobuf:"bytes,1,opt,name=vessel_name,json=vesselName,proto3" json:"vessel_name,omitempty"`
+	Imo           string                 `protobuf:"bytes,2,opt,name=imo,proto3" json:"imo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VesselDetails) Reset() {
+	*x = VesselDetails{}
+	mi := &file_schedulehub_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VesselDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VesselDetails) ProtoMessage() {}
+
+func (x *VesselDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_schedulehub_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VesselDetails.ProtoReflect.Descriptor instead.
+func (*VesselDetails) Descriptor() ([]byte, []int) {
+	return file_schedulehub_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *VesselDetails) GetVesselName() string {
+	if x != nil {
+		return x.VesselName
+	}
+	return ""
+}
+
+func (x *VesselDetails) GetImo() string {
+	if x != nil {
+		return x.Imo
+	}
+	return ""
+}
+
+type Services struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceCode   string                 `protobuf:"bytes,1,opt,name=service_code,json=serviceCode,proto3" json:"service_code,omitempty"`
+	ServiceName   string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Services) Reset() {
+	*x = Services{}
+	mi := &file_schedulehub_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Services) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Services) ProtoMessage() {}
+
+func (x *Services) ProtoReflect() protoreflect.Message {
+	mi := &file_schedulehub_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Services.ProtoReflect.Descriptor instead.
+func (*Services) Descriptor() ([]byte, []int) {
+	return file_schedulehub_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Services) GetServiceCode() string {
+	if x != nil {
+		return x.ServiceCode
+	}
+	return ""
+}
+
+func (x *Services) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+type PortCalls struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Seq                   int32                  `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Key                   string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"` // the shape differs per carrier, anything else than a string is sent as json
+	Bound                 string                 `protobuf:"bytes,3,opt,name=bound,proto3" json:"bound,omitempty"`
+	Voyage                string                 `protobuf:"bytes,4,opt,name=voyage,proto3" json:"voyage,omitempty"`
+	Service               *Services              `protobuf:"bytes,5,opt,name=service,proto3" json:"service,omitempty"`
+	PortEvent             string                 `protobuf:"bytes,6,opt,name=port_event,json=portEvent,proto3" json:"port_event,omitempty"`
+	Port                  *Port                  `protobuf:"bytes,7,opt,name=port,proto3" json:"port,omitempty"`
+	PlannedEventDate      string                 `protobuf:"bytes,8,opt,name=planned_event_date,json=plannedEventDate,proto3" json:"planned_event_date,omitempty"`
+	EstimatedEventDate    string                 `protobuf:"bytes,9,opt,name=estimated_event_date,json=estimatedEventDate,proto3" json:"estimated_event_date,omitempty"`
+	ActualEventDate       string                 `protobuf:"bytes,10,opt,name=actual_event_date,json=actualEventDate,proto3" json:"actual_event_date,omitempty"`
+	EstimatedEventDateUtc string                 `protobuf:"bytes,11,opt,name=estimated_event_date_utc,json=estimatedEventDateUtc,proto3" json:"estimated_event_date_utc,omitempty"`
+	ActualEventDateUtc    string                 `protobuf:"bytes,12,opt,name=actual_event_date_utc,json=actualEventDateUtc,proto3" json:"actual_event_date_utc,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *PortCalls) Reset() {
+	*x = PortCalls{}
+	mi := &file_schedulehub_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PortCalls) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PortCalls) ProtoMessage() {}
+
+func (x *PortCalls) ProtoReflect() protoreflect.Message {
+	mi := &file_schedulehub_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PortCalls.ProtoReflect.Descriptor instead.
+func (*PortCalls) Descriptor() ([]byte, []int) {
+	return file_schedulehub_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PortCalls) GetSeq() int32 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *PortCalls) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *PortCalls) GetBound() string {
+	if x != nil {
+		return x.Bound
+	}
+	return ""
+}
+
+func (x *PortCalls) GetVoyage() string {
+	if x != nil {
+		return x.Voyage
+	}
+	return ""
+}
+
+func (x *PortCalls) GetService() *Services {
+	if x != nil {
+		return x.Service
+	}
+	return nil
+}
+
+func (x *PortCalls) GetPortEvent() string {
+	if x != nil {
+		return x.PortEvent
+	}
+	return ""
+}
+
+func (x *PortCalls) GetPort() *Port {
+	if x != nil {
+		return x.Port
+	}
+	return nil
+}
+
+func (x *PortCalls) GetPlannedEventDate() string {
+	if x != nil {
+		return x.PlannedEventDate
+	}
+	return ""
+}
+
+func (x *PortCalls) GetEstimatedEventDate() string {
+	if x != nil {
+		return x.EstimatedEventDate
+	}
+	return ""
+}
+
+func (x *PortCalls) GetActualEventDate() string {
+	if x != nil {
+		return x.ActualEventDate
+	}
+	return ""
+}
+
+func (x *PortCalls) GetEstimatedEventDateUtc() string {
+	if x != nil {
+		return x.EstimatedEventDateUtc
+	}
+	return ""
+}
+
+func (x *PortCalls) GetActualEventDateUtc() string {
+	if x != nil {
+		return x.ActualEventDateUtc
+	}
+	return ""
+}
+
+type MasterVesselSchedule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Scac          string                 `protobuf:"bytes,1,opt,name=scac,proto3" json:"scac,omitempty"`
+	Voyage        string                 `protobuf:"bytes,2,opt,name=voyage,proto3" json:"voyage,omitempty"`
+	NextVoyage    string                 `protobuf:"bytes,3,opt,name=next_voyage,json=nextVoyage,proto3" json:"next_voyage,omitempty"`
+	Vessel        *VesselDetails         `protobuf:"bytes,4,opt,name=vessel,proto3" json:"vessel,omitempty"`
+	Services      *Services              `protobuf:"bytes,5,opt,name=services,proto3" json:"services,omitempty"`
+	Calls         []*PortCalls           `protobuf:"bytes,6,rep,name=calls,proto3" json:"calls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MasterVesselSchedule) Reset() {
+	*x = MasterVesselSchedule{}
+	mi := &file_schedulehub_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MasterVesselSchedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MasterVesselSchedule) ProtoMessage() {}
+
+func (x *MasterVesselSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_schedulehub_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MasterVesselSchedule.ProtoReflect.Descriptor instead.
+func (*MasterVesselSchedule) Descriptor() ([]byte, []int) {
+	return file_schedulehub_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *MasterVesselSchedule) GetScac() string {
+	if x != nil {
+		return x.Scac
+	}
+	return ""
+}
+
+func (x *MasterVesselSchedule) GetVoyage() string {
+	if x != nil {
+		return x.Voyage
+	}
+	return ""
+}
+
+func (x *MasterVesselSchedule) GetNextVoyage() string {
+	if x != nil {
+		return x.NextVoyage
+	}
+	return ""
+}
+
+func (x *MasterVesselSchedule) GetVessel() *VesselDetails {
+	if x != nil {
+		return x.Vessel
+	}
+	return nil
+}
+
+func (x *MasterVesselSchedule) GetServices() *Services {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+func (x *MasterVesselSchedule) GetCalls() []*PortCalls {
+	if x != nil {
+		return x.Calls
+	}
+	return nil
+}
+
+var File_schedulehub_proto protoreflect.FileDescriptor
+
+var file_schedulehub_proto_rawDesc = string([]byte{
+	0x0a, 0x11, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x68, 0x75, 0x62, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x0e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x68, 0x75, 0x62,
+	0x2e, 0x76, 0x31, 0x22, 0xf3, 0x05, 0x0a, 0x0b, 0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x5f, 0x66, 0x72, 0x6f,
+	0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x46, 0x72,
+	0x6f, 0x6d, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x5f, 0x74, 0x6f, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x54, 0x6f, 0x12, 0x26, 0x0a,
+	0x0f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x74, 0x61, 0x72, 0x74, 0x44, 0x61, 0x74,
+	0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x64,
+	0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x44, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x5f, 0x72,
+	0x61, 0x6e, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x73, 0x65, 0x61, 0x72,
+	0x63, 0x68, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x63, 0x61, 0x63, 0x18,
+	0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x73, 0x63, 0x61, 0x63, 0x12, 0x1f, 0x0a, 0x0b, 0x64,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0a, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x4f, 0x6e, 0x6c, 0x79, 0x12, 0x2b, 0x0a, 0x11,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x68, 0x69, 0x70, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x70, 0x6f, 0x72,
+	0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x68, 0x69,
+	0x70, 0x6d, 0x65, 0x6e, 0x74, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x76, 0x65, 0x73,
+	0x73, 0x65, 0x6c, 0x5f, 0x69, 0x6d, 0x6f, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x76,
+	0x65, 0x73, 0x73, 0x65, 0x6c, 0x49, 0x6d, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x76, 0x65, 0x73, 0x73, 0x65, 0x6c, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x76, 0x65, 0x73, 0x73,
+	0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2d, 0x0a, 0x10, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x69, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05,
+	0x48, 0x00, 0x52, 0x0e, 0x6d, 0x61, 0x78, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x54, 0x69,
+	0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x32, 0x0a, 0x12, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x72, 0x61,
+	0x6e, 0x73, 0x73, 0x68, 0x69, 0x70, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x0e, 0x20, 0x01, 0x28,
+	0x05, 0x48, 0x01, 0x52, 0x11, 0x6d, 0x61, 0x78, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x73, 0x68, 0x69,
+	0x70, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x88, 0x01, 0x01, 0x12, 0x33, 0x0a, 0x15, 0x65, 0x78, 0x63,
+	0x6c, 0x75, 0x64, 0x65, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x73, 0x68, 0x69, 0x70, 0x6d, 0x65,
+	0x6e, 0x74, 0x18, 0x0f, 0x20, 0x03, 0x28, 0x09, 0x52, 0x14, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64,
+	0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x73, 0x68, 0x69, 0x70, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x27,
+	0x0a, 0x0f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x6d, 0x6f, 0x64, 0x65,
+	0x73, 0x18, 0x10, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f,
+	0x72, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x75, 0x74, 0x6f, 0x66,
+	0x66, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63,
+	0x75, 0x74, 0x6f, 0x66, 0x66, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x6f,
+	0x72, 0x74, 0x5f, 0x62, 0x79, 0x18, 0x12, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x72,
+	0x74, 0x42, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65, 0x64, 0x75, 0x70, 0x65, 0x18, 0x13, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x06, 0x64, 0x65, 0x64, 0x75, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x63,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x14, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x42, 0x13, 0x0a,
+	0x11, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x5f, 0x74, 0x69,
+	0x6d, 0x65, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x73, 0x68, 0x69, 0x70, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x22, 0xbc, 0x01, 0x0a, 0x09, 0x50, 0x6f,
+	0x69, 0x6e, 0x74, 0x42, 0x61, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x6c, 0x6f, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d,
+	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64,
+	0x65, 0x12, 0x23, 0x0a, 0x0d, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e,
+	0x61, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e,
+	0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x74,
+	0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x5f, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x74, 0x69, 0x6d, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x22, 0x7e, 0x0a, 0x06, 0x43, 0x75, 0x74, 0x6f,
+	0x66, 0x66, 0x12, 0x24, 0x0a, 0x0e, 0x63, 0x79, 0x5f, 0x63, 0x75, 0x74, 0x6f, 0x66, 0x66, 0x5f,
+	0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x79, 0x43, 0x75,
+	0x74, 0x6f, 0x66, 0x66, 0x44, 0x61, 0x74, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x64, 0x6f, 0x63, 0x5f,
+	0x63, 0x75, 0x74, 0x6f, 0x66, 0x66, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x64, 0x6f, 0x63, 0x43, 0x75, 0x74, 0x6f, 0x66, 0x66, 0x44, 0x61, 0x74, 0x65,
+	0x12, 0x26, 0x0a, 0x0f, 0x76, 0x67, 0x6d, 0x5f, 0x63, 0x75, 0x74, 0x6f, 0x66, 0x66, 0x5f, 0x64,
+	0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x76, 0x67, 0x6d, 0x43, 0x75,
+	0x74, 0x6f, 0x66, 0x66, 0x44, 0x61, 0x74, 0x65, 0x22, 0xa3, 0x01, 0x0a, 0x0e, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x6e,
+	0x73, 0x70, 0x6f, 0x72, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x66,
+	0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x22, 0x5a,
+	0x0a, 0x06, 0x56, 0x6f, 0x79, 0x61, 0x67, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x76, 0x6f, 0x79, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x56, 0x6f, 0x79, 0x61, 0x67,
+	0x65, 0x12, 0x27, 0x0a, 0x0f, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x76, 0x6f,
+	0x79, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x65, 0x78, 0x74, 0x65,
+	0x72, 0x6e, 0x61, 0x6c, 0x56, 0x6f, 0x79, 0x61, 0x67, 0x65, 0x22, 0x4f, 0x0a, 0x07, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0xe5, 0x03, 0x0a, 0x03,
+	0x4c, 0x65, 0x67, 0x12, 0x38, 0x0a, 0x0a, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x5f, 0x66, 0x72, 0x6f,
+	0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x68, 0x75, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x42, 0x61,
+	0x73, 0x65, 0x52, 0x09, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x34, 0x0a,
+	0x08, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x5f, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x68, 0x75, 0x62, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x42, 0x61, 0x73, 0x65, 0x52, 0x07, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x54, 0x6f, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x74, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x65, 0x74, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x65, 0x74, 0x61, 0x12, 0x17, 0x0a, 0x07, 0x65, 0x74, 0x64, 0x5f, 0x75,
+	0x74, 0x63, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x65, 0x74, 0x64, 0x55, 0x74, 0x63,
+	0x12, 0x17, 0x0a, 0x07, 0x65, 0x74, 0x61, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x65, 0x74, 0x61, 0x55, 0x74, 0x63, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x72, 0x61,
+	0x6e, 0x73, 0x69, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x30, 0x0a, 0x07,
+	0x63, 0x75, 0x74, 0x6f, 0x66, 0x66, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e,
+	0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x68, 0x75, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x43,
+	0x75, 0x74, 0x6f, 0x66, 0x66, 0x52, 0x07, 0x63, 0x75, 0x74, 0x6f, 0x66, 0x66, 0x73, 0x12, 0x48,
+	0x0a, 0x0f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x68, 0x75, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f,
+	0x72, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f,
+	0x72, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x30, 0x0a, 0x07, 0x76, 0x6f, 0x79, 0x61,
+	0x67, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x73, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x65, 0x68, 0x75, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x6f, 0x79, 0x61, 0x67,
+	0x65, 0x52, 0x07, 0x76, 0x6f, 0x79, 0x61, 0x67, 0x65, 0x73, 0x12, 0x33, 0x0a, 0x08, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73,
+	0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x68, 0x75, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12,
+	0x12, 0x0a, 0x04, 0x73, 0x63, 0x61, 0x63, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73,
+	0x63, 0x61, 0x63, 0x22, 0x95, 0x03, 0x0a, 0x0b, 0x50, 0x32, 0x50, 0x53, 0x63, 0x68, 0x65, 0x64,
+	0x75, 0x6c, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x63, 0x61, 0x63, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x73, 0x63, 0x61, 0x63, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x5f, 0x74, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x54, 0x6f, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x74, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x65, 0x74, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x74, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x65, 0x74, 0x61, 0x12, 0x17, 0x0a, 0x07, 0x65, 0x74, 0x64, 0x5f, 0x75, 0x74,
+	0x63, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x65, 0x74, 0x64, 0x55, 0x74, 0x63, 0x12,
+	0x17, 0x0a, 0x07, 0x65, 0x74, 0x61, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x65, 0x74, 0x61, 0x55, 0x74, 0x63, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x72, 0x61, 0x6e,
+	0x73, 0x69, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x73, 0x68, 0x69, 0x70, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x73, 0x68, 0x69, 0x70, 0x6d, 0x65, 0x6e,
+	0x74, 0x12, 0x27, 0x0a, 0x04, 0x6c, 0x65, 0x67, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x68, 0x75, 0x62, 0x2e, 0x76, 0x31,
+	0x2e, 0x4c, 0x65, 0x67, 0x52, 0x04, 0x6c, 0x65, 0x67, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x66,
+	0x66, 0x65, 0x72, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09,
+	0x6f, 0x66, 0x66, 0x65, 0x72, 0x65, 0x64, 0x42, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x61, 0x6e,
+	0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x61, 0x6e, 0x65, 0x12, 0x1c, 0x0a,
+	0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6c, 0x69, 0x6e, 0x65, 0x22, 0xa3, 0x01, 0x0a, 0x11,
+	0x56, 0x65, 0x73, 0x73, 0x65, 0x6c, 0x56, 0x6f, 0x79, 0x61, 0x67, 0x65, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x63, 0x61, 0x63, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x04, 0x73, 0x63, 0x61, 0x63, 0x12, 0x1d, 0x0a, 0x0a, 0x76, 0x65, 0x73, 0x73, 0x65, 0x6c, 0x5f,
+	0x69, 0x6d, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x76, 0x65, 0x73, 0x73, 0x65,
+	0x6c, 0x49, 0x6d, 0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x64, 0x61,
+	0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x44,
+	0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x72, 0x61, 0x6e, 0x67,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x64, 0x61, 0x74, 0x65, 0x52, 0x61, 0x6e,
+	0x67, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x76, 0x6f, 0x79, 0x61, 0x67, 0x65, 0x5f, 0x6e, 0x75, 0x6d,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x76, 0x6f, 0x79, 0x61, 0x67, 0x65, 0x4e, 0x75,
+	0x6d, 0x22, 0xa7, 0x01, 0x0a, 0x04, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x6f,
+	0x72, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70,
+	0x6f, 0x72, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x6f, 0x72, 0x74, 0x5f,
+	0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6f, 0x72, 0x74,
+	0x43, 0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x74, 0x65, 0x72,
+	0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x74, 0x65, 0x72,
+	0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1b,
+	0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x22, 0x42, 0x0a, 0x0d, 0x56,
+	0x65, 0x73, 0x73, 0x65, 0x6c, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
+	0x76, 0x65, 0x73, 0x73, 0x65, 0x6c, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x76, 0x65, 0x73, 0x73, 0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x69, 0x6d, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x69, 0x6d, 0x6f, 0x22,
+	0x50, 0x0a, 0x08, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x21,
+	0x0a, 0x0c, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4e, 0x61, 0x6d,
+	0x65, 0x22, 0xd2, 0x03, 0x0a, 0x09, 0x50, 0x6f, 0x72, 0x74, 0x43, 0x61, 0x6c, 0x6c, 0x73, 0x12,
+	0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x73, 0x65,
+	0x71, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x6f, 0x79,
+	0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x76, 0x6f, 0x79, 0x61, 0x67,
+	0x65, 0x12, 0x32, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x68, 0x75, 0x62,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x07, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x6f, 0x72, 0x74, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x12, 0x28, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x68, 0x75, 0x62,
+	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x2c,
+	0x0a, 0x12, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f,
+	0x64, 0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x70, 0x6c, 0x61, 0x6e,
+	0x6e, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x65, 0x12, 0x30, 0x0a, 0x14,
+	0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f,
+	0x64, 0x61, 0x74, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x65, 0x73, 0x74, 0x69,
+	0x6d, 0x61, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x65, 0x12, 0x2a,
+	0x0a, 0x11, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x64,
+	0x61, 0x74, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x61, 0x63, 0x74, 0x75, 0x61,
+	0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x65, 0x12, 0x37, 0x0a, 0x18, 0x65, 0x73,
+	0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x64, 0x61,
+	0x74, 0x65, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x15, 0x65, 0x73,
+	0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x65,
+	0x55, 0x74, 0x63, 0x12, 0x31, 0x0a, 0x15, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x12, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x44,
+	0x61, 0x74, 0x65, 0x55, 0x74, 0x63, 0x22, 0x81, 0x02, 0x0a, 0x14, 0x4d, 0x61, 0x73, 0x74, 0x65,
+	0x72, 0x56, 0x65, 0x73, 0x73, 0x65, 0x6c, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x73, 0x63, 0x61, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73,
+	0x63, 0x61, 0x63, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x6f, 0x79, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x76, 0x6f, 0x79, 0x61, 0x67, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6e,
+	0x65, 0x78, 0x74, 0x5f, 0x76, 0x6f, 0x79, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x56, 0x6f, 0x79, 0x61, 0x67, 0x65, 0x12, 0x35, 0x0a, 0x06,
+	0x76, 0x65, 0x73, 0x73, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x73,
+	0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x68, 0x75, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65,
+	0x73, 0x73, 0x65, 0x6c, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x52, 0x06, 0x76, 0x65, 0x73,
+	0x73, 0x65, 0x6c, 0x12, 0x34, 0x0a, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
+	0x68, 0x75, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52,
+	0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x2f, 0x0a, 0x05, 0x63, 0x61, 0x6c,
+	0x6c, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64,
+	0x75, 0x6c, 0x65, 0x68, 0x75, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x43, 0x61,
+	0x6c, 0x6c, 0x73, 0x52, 0x05, 0x63, 0x61, 0x6c, 0x6c, 0x73, 0x32, 0xb4, 0x01, 0x0a, 0x0b, 0x53,
+	0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x48, 0x75, 0x62, 0x12, 0x47, 0x0a, 0x09, 0x53, 0x65,
+	0x61, 0x72, 0x63, 0x68, 0x50, 0x32, 0x50, 0x12, 0x1b, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x68, 0x75, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x61,
+	0x72, 0x61, 0x6d, 0x73, 0x1a, 0x1b, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x68,
+	0x75, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x32, 0x50, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c,
+	0x65, 0x30, 0x01, 0x12, 0x5c, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72,
+	0x56, 0x6f, 0x79, 0x61, 0x67, 0x65, 0x12, 0x21, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c,
+	0x65, 0x68, 0x75, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x73, 0x73, 0x65, 0x6c, 0x56, 0x6f,
+	0x79, 0x61, 0x67, 0x65, 0x51, 0x75, 0x65, 0x72, 0x79, 0x1a, 0x24, 0x2e, 0x73, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x65, 0x68, 0x75, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x61, 0x73, 0x74, 0x65,
+	0x72, 0x56, 0x65, 0x73, 0x73, 0x65, 0x6c, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x30,
+	0x01, 0x42, 0x41, 0x5a, 0x3f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x6e, 0x65, 0x63, 0x6b, 0x63, 0x68, 0x69, 0x2f, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
+	0x68, 0x75, 0x62, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x67, 0x72, 0x70,
+	0x63, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x3b, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var (
+	file_schedulehub_proto_rawDescOnce sync.Once
+	file_schedulehub_proto_rawDescData []byte
+)
+
+func file_schedulehub_proto_rawDescGZIP() []byte {
+	file_schedulehub_proto_rawDescOnce.Do(func() {
+		file_schedulehub_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_schedulehub_proto_rawDesc), len(file_schedulehub_proto_rawDesc)))
+	})
+	return file_schedulehub_proto_rawDescData
+}
+
+var file_schedulehub_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_schedulehub_proto_goTypes = []any{
+	(*QueryParams)(nil),          // 0: schedulehub.v1.QueryParams
+	(*PointBase)(nil),            // 1: schedulehub.v1.PointBase
+	(*Cutoff)(nil),               // 2: schedulehub.v1.Cutoff
+	(*Transportation)(nil),       // 3: schedulehub.v1.Transportation
+	(*Voyage)(nil),               // 4: schedulehub.v1.Voyage
+	(*Service)(nil),              // 5: schedulehub.v1.Service
+	(*Leg)(nil),                  // 6: schedulehub.v1.Leg
+	(*P2PSchedule)(nil),          // 7: schedulehub.v1.P2PSchedule
+	(*VesselVoyageQuery)(nil),    // 8: schedulehub.v1.VesselVoyageQuery
+	(*Port)(nil),                 // 9: schedulehub.v1.Port
+	(*VesselDetails)(nil),        // 10: schedulehub.v1.VesselDetails
+	(*Services)(nil),             // 11: schedulehub.v1.Services
+	(*PortCalls)(nil),            // 12: schedulehub.v1.PortCalls
+	(*MasterVesselSchedule)(nil), // 13: schedulehub.v1.MasterVesselSchedule
+}
+var file_schedulehub_proto_depIdxs = []int32{
+	1,  // 0: schedulehub.v1.Leg.point_from:type_name -> schedulehub.v1.PointBase
+	1,  // 1: schedulehub.v1.Leg.point_to:type_name -> schedulehub.v1.PointBase
+	2,  // 2: schedulehub.v1.Leg.cutoffs:type_name -> schedulehub.v1.Cutoff
+	3,  // 3: schedulehub.v1.Leg.transportations:type_name -> schedulehub.v1.Transportation
+	4,  // 4: schedulehub.v1.Leg.voyages:type_name -> schedulehub.v1.Voyage
+	5,  // 5: schedulehub.v1.Leg.services:type_name -> schedulehub.v1.Service
+	6,  // 6: schedulehub.v1.P2PSchedule.legs:type_name -> schedulehub.v1.Leg
+	11, // 7: schedulehub.v1.PortCalls.service:type_name -> schedulehub.v1.Services
+	9,  // 8: schedulehub.v1.PortCalls.port:type_name -> schedulehub.v1.Port
+	10, // 9: schedulehub.v1.MasterVesselSchedule.vessel:type_name -> schedulehub.v1.VesselDetails
+	11, // 10: schedulehub.v1.MasterVesselSchedule.services:type_name -> schedulehub.v1.Services
+	12, // 11: schedulehub.v1.MasterVesselSchedule.calls:type_name -> schedulehub.v1.PortCalls
+	0,  // 12: schedulehub.v1.ScheduleHub.SearchP2P:input_type -> schedulehub.v1.QueryParams
+	8,  // 13: schedulehub.v1.ScheduleHub.GetMasterVoyage:input_type -> schedulehub.v1.VesselVoyageQuery
+	7,  // 14: schedulehub.v1.ScheduleHub.SearchP2P:output_type -> schedulehub.v1.P2PSchedule
+	13, // 15: schedulehub.v1.ScheduleHub.GetMasterVoyage:output_type -> schedulehub.v1.MasterVesselSchedule
+	14, // [14:16] is the sub-list for method output_type
+	12, // [12:14] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_schedulehub_proto_init() }
+func file_schedulehub_proto_init() {
+	if File_schedulehub_proto != nil {
+		return
+	}
+	file_schedulehub_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_schedulehub_proto_rawDesc), len(file_schedulehub_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_schedulehub_proto_goTypes,
+		DependencyIndexes: file_schedulehub_proto_depIdxs,
+		MessageInfos:      file_schedulehub_proto_msgTypes,
+	}.Build()
+	File_schedulehub_proto = out.File
+	file_schedulehub_proto_goTypes = nil
+	file_schedulehub_proto_depIdxs = nil
+}
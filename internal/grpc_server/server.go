@@ -0,0 +1,245 @@
+package grpc_server
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neckchi/schedulehub/internal/handlers/mvs_handler"
+	"github.com/neckchi/schedulehub/internal/handlers/p2p_schedule_handler"
+	"github.com/neckchi/schedulehub/internal/middleware"
+	"github.com/neckchi/schedulehub/internal/schema"
+	"github.com/neckchi/schedulehub/internal/utils"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+)
+
+// status codes of the gRPC protocol the server answers with
+const (
+	codeOK               = 0
+	codeCanceled         = 1
+	codeInvalidArgument  = 3
+	codeDeadlineExceeded = 4
+	codeUnimplemented    = 12
+	codeInternal         = 13
+)
+
+const (
+	serviceName       = "/schedulehub.v1.ScheduleHub/"
+	maxRequestMessage = 1 << 20
+)
+
+// statusError carries the gRPC status of a failed call
+type statusError struct {
+	code    int
+	message string
+}
+
+func (e *statusError) Error() string { return e.message }
+
+func statusf(code int, format string, args ...any) error {
+	return &statusError{code: code, message: fmt.Sprintf(format, args...)}
+}
+
+// Server speaks the gRPC protocol on top of net/http, it has to be served over HTTP/2(h2c on the internal network). The messages
+// are the generated ones of schedulehub.proto. Only the identity encoding is accepted, they are small enough to go uncompressed
+type Server struct {
+	p2p *p2p_schedule_handler.P2PScheduleService
+	mvs *mvs_handler.VoyageService
+}
+
+func NewServer(p2p *p2p_schedule_handler.P2PScheduleService, mvs *mvs_handler.VoyageService) *Server {
+	return &Server{p2p: p2p, mvs: mvs}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.ProtoMajor != 2 {
+		http.Error(w, "gRPC requires HTTP/2", http.StatusHTTPVersionNotSupported)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "gRPC requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+	if contentType := r.Header.Get("Content-Type"); contentType != "application/grpc" && contentType != "application/grpc+proto" {
+		http.Error(w, "unsupported content type "+contentType, http.StatusUnsupportedMediaType)
+		return
+	}
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Grpc-Accept-Encoding", "identity")
+	w.WriteHeader(http.StatusOK) // the status goes in the trailers, even when the call fails before the first message
+	utils.NewFlushWriter(w).Flush()
+	started := time.Now()
+	err := s.call(w, r)
+	code, message := status(r.Context(), err)
+	w.Header().Set(http.TrailerPrefix+"Grpc-Status", strconv.Itoa(code))
+	if message != "" {
+		w.Header().Set(http.TrailerPrefix+"Grpc-Message", encodeMessage(message))
+	}
+	if code != codeOK {
+		log.Errorf("gRPC %s failed with code %d: %s", r.URL.Path, code, message)
+		return
+	}
+	log.Infof("gRPC %s completed in %v", r.URL.Path, time.Since(started))
+}
+
+func (s *Server) call(w http.ResponseWriter, r *http.Request) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = statusf(codeInternal, "internal error: %v", recovered)
+		}
+	}()
+	ctx := r.Context()
+	if timeout, ok := parseTimeout(r.Header.Get("Grpc-Timeout")); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if encoding := r.Header.Get("Grpc-Encoding"); encoding != "" && encoding != "identity" {
+		return statusf(codeUnimplemented, "grpc-encoding %s is not supported", encoding)
+	}
+	request, err := readMessage(r.Body)
+	if err != nil {
+		return err
+	}
+	send := func(message proto.Message) error {
+		data, err := proto.Marshal(message)
+		if err != nil {
+			return statusf(codeInternal, "%v", err)
+		}
+		return writeMessage(w, data)
+	}
+	switch method, _ := strings.CutPrefix(r.URL.Path, serviceName); method {
+	case "SearchP2P":
+		return s.searchP2P(ctx, request, send)
+	case "GetMasterVoyage":
+		return s.getMasterVoyage(ctx, request, send)
+	}
+	return statusf(codeUnimplemented, "unknown method %s", r.URL.Path)
+}
+
+// searchP2P takes the same checks and pipeline as /schedules/p2p, every schedule is sent as soon as its carrier answers
+func (s *Server) searchP2P(ctx context.Context, request []byte, send func(proto.Message) error) error {
+	queryParams, err := decodeQueryParams(request)
+	if err != nil {
+		return statusf(codeInvalidArgument, "%v", err)
+	}
+	settings, err := middleware.AppConfig("service.registry.p2p")
+	if err != nil {
+		return statusf(codeInternal, "%v", err)
+	}
+	if err := middleware.PrepareP2PQuery(&queryParams, settings); err != nil {
+		return statusf(codeInvalidArgument, "%v", err)
+	}
+	watchKey := fmt.Sprintf("grpc#p2p#%s#%s#%s#%s", queryParams.PointFrom, queryParams.PointTo, queryParams.StartDateType, queryParams.StartDate)
+	return s.p2p.Stream(ctx, &queryParams, settings, watchKey, func(schedule *schema.P2PSchedule) error {
+		return send(p2pScheduleMessage(schedule))
+	})
+}
+
+func (s *Server) getMasterVoyage(ctx context.Context, request []byte, send func(proto.Message) error) error {
+	queryParams, err := decodeVesselVoyageQuery(request)
+	if err != nil {
+		return statusf(codeInvalidArgument, "%v", err)
+	}
+	if err := middleware.PrepareVesselVoyageQuery(&queryParams); err != nil {
+		return statusf(codeInvalidArgument, "%v", err)
+	}
+	settings, err := middleware.AppConfig("service.registry.mvs")
+	if err != nil {
+		return statusf(codeInternal, "%v", err)
+	}
+	scacConfig, ok := settings["externalAPICarriers"].(map[string]interface{})
+	if !ok {
+		return statusf(codeInternal, "invalid schedule configuration")
+	}
+	watchKey := fmt.Sprintf("grpc#mvs#%s#%v#%s#%d", queryParams.VesselIMO, queryParams.SCAC, queryParams.StartDate, queryParams.DateRange)
+	return s.mvs.Stream(ctx, &queryParams, scacConfig, watchKey, func(schedule *schema.MasterVesselSchedule) error {
+		return send(masterVesselScheduleMessage(schedule))
+	})
+}
+
+// readMessage reads the single length prefixed request message of a server streaming call
+func readMessage(body io.Reader) ([]byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(body, prefix[:]); err != nil {
+		return nil, statusf(codeInvalidArgument, "missing request message: %v", err)
+	}
+	if prefix[0] != 0 {
+		return nil, statusf(codeUnimplemented, "compressed messages are not supported")
+	}
+	size := binary.BigEndian.Uint32(prefix[1:])
+	if size > maxRequestMessage {
+		return nil, statusf(codeInvalidArgument, "request message of %d bytes exceeds %d", size, maxRequestMessage)
+	}
+	message := make([]byte, size)
+	if _, err := io.ReadFull(body, message); err != nil {
+		return nil, statusf(codeInvalidArgument, "truncated request message: %v", err)
+	}
+	return message, nil
+}
+
+// writeMessage frames the message and flushes it right away so the client gets the schedules as they come
+func writeMessage(w http.ResponseWriter, message []byte) error {
+	frame := make([]byte, 5, 5+len(message))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(message)))
+	if _, err := w.Write(append(frame, message...)); err != nil {
+		return err
+	}
+	utils.NewFlushWriter(w).Flush()
+	return nil
+}
+
+// status maps the error of the call on a gRPC status, a send error after the client has gone away is reported as canceled
+func status(ctx context.Context, err error) (int, string) {
+	var se *statusError
+	switch {
+	case err == nil:
+		return codeOK, ""
+	case errors.As(err, &se):
+		return se.code, se.message
+	case errors.Is(err, context.DeadlineExceeded):
+		return codeDeadlineExceeded, "deadline exceeded"
+	case errors.Is(err, context.Canceled) || ctx.Err() != nil:
+		return codeCanceled, "call canceled"
+	}
+	return codeInternal, err.Error()
+}
+
+// parseTimeout reads the grpc-timeout header(e.g. 30S, 500m)
+func parseTimeout(value string) (time.Duration, bool) {
+	if len(value) < 2 || len(value) > 9 {
+		return 0, false
+	}
+	amount, err := strconv.ParseInt(value[:len(value)-1], 10, 64)
+	if err != nil || amount < 0 {
+		return 0, false
+	}
+	units := map[byte]time.Duration{
+		'H': time.Hour, 'M': time.Minute, 'S': time.Second, 'm': time.Millisecond, 'u': time.Microsecond, 'n': time.Nanosecond,
+	}
+	unit, ok := units[value[len(value)-1]]
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(amount) * unit, true
+}
+
+// encodeMessage percent encodes grpc-message as the protocol asks for everything outside printable ASCII
+func encodeMessage(message string) string {
+	var encoded strings.Builder
+	for i := 0; i < len(message); i++ {
+		if c := message[i]; c >= 0x20 && c <= 0x7e && c != '%' {
+			encoded.WriteByte(c)
+		} else {
+			fmt.Fprintf(&encoded, "%%%02X", c)
+		}
+	}
+	return encoded.String()
+}
@@ -0,0 +1,56 @@
+package graphql_handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/neckchi/schedulehub/internal/exceptions"
+	"github.com/neckchi/schedulehub/internal/graphql"
+	"github.com/neckchi/schedulehub/internal/handlers/p2p_schedule_handler"
+	"github.com/neckchi/schedulehub/internal/middleware"
+	log "github.com/sirupsen/logrus"
+)
+
+const maxQueryBodySize = 1 << 20
+
+// GraphQLHandler runs the query posted as {"query", "operationName", "variables"}. A GET runs the query parameter and returns
+// the schema in SDL without one. The query errors are answered with a 400, the field errors(e.g. an inactive scac) come back
+// next to the data of the other fields
+func GraphQLHandler(s *graphql.Schema) http.Handler {
+	sdl := graphql.Print(s)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req graphql.Request
+		switch {
+		case r.Method == http.MethodPost:
+			decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxQueryBodySize))
+			if err := decoder.Decode(&req); err != nil {
+				exceptions.RequestErrorHandler(w, fmt.Errorf("invalid graphql request: %v", err))
+				return
+			}
+		case r.URL.Query().Has("query"):
+			query := r.URL.Query()
+			req.Query, req.OperationName = query.Get("query"), query.Get("operationName")
+			if variables := query.Get("variables"); variables != "" {
+				if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+					exceptions.RequestErrorHandler(w, fmt.Errorf("invalid graphql variables: %v", err))
+					return
+				}
+			}
+		default:
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = w.Write([]byte(sdl))
+			return
+		}
+		settings, _ := r.Context().Value(middleware.ScheduleConfig).(map[string]interface{})
+		response, err := graphql.Do(p2p_schedule_handler.WithBudget(WithVoyageMemo(r.Context()), settings), s, req)
+		if err != nil {
+			exceptions.RequestErrorHandler(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Errorf("Failed to write the graphql response: %v", err)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package graphql_handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGraphQLHandlerContentType(t *testing.T) {
+	handler := GraphQLHandler(NewSchema(nil, nil))
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		body        string
+		contentType string
+	}{
+		{name: "post", method: http.MethodPost, target: "/graphql", body: `{"query": "{ __typename }"}`, contentType: "application/json"},
+		{name: "get with a query", method: http.MethodGet, target: "/graphql?query=%7B__typename%7D", contentType: "application/json"},
+		{name: "schema", method: http.MethodGet, target: "/graphql", contentType: "text/plain; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rsp := httptest.NewRecorder()
+			handler.ServeHTTP(rsp, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			if rsp.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200\n%s", rsp.Code, rsp.Body)
+			}
+			if got := rsp.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package graphql_handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neckchi/schedulehub/external"
+	"github.com/neckchi/schedulehub/internal/graphql"
+	"github.com/neckchi/schedulehub/internal/handlers/mvs_handler"
+	"github.com/neckchi/schedulehub/internal/handlers/p2p_schedule_handler"
+	"github.com/neckchi/schedulehub/internal/middleware"
+	"github.com/neckchi/schedulehub/internal/schema"
+)
+
+// searchCost weighs the fields running a carrier search, the limit lets a query run a few searches next to a full selection
+const (
+	searchCost     = 50
+	maxQueryCost   = 250
+	maxQueryDepth  = 8
+	maxConcurrency = 16
+)
+
+// resolver answers the root fields with the same pipelines as the REST endpoints
+type resolver struct {
+	p2p *p2p_schedule_handler.P2PScheduleService
+	mvs *mvs_handler.VoyageService
+}
+
+var (
+	str        = graphql.String
+	integer    = graphql.Int
+	boolean    = graphql.Boolean
+	stringList = &graphql.List{OfType: &graphql.NonNull{OfType: graphql.String}}
+)
+
+func required(t graphql.Type) graphql.Type {
+	return &graphql.NonNull{OfType: t}
+}
+
+// NewSchema mirrors P2PSchedule/Leg and MasterVesselSchedule/PortCalls through their json tags. A leg sailed by a vessel can
+// resolve the vessel voyages of its IMO through the master vessel schedule pipeline
+func NewSchema(p2p *p2p_schedule_handler.P2PScheduleService, mvs *mvs_handler.VoyageService) *graphql.Schema {
+	r := &resolver{p2p: p2p, mvs: mvs}
+	b := graphql.NewBuilder()
+	p2pSchedule := b.Object(schema.P2PSchedule{})
+	p2pSchedule.Description = "Point to point schedule of a carrier"
+	masterVesselSchedule := b.Object(schema.MasterVesselSchedule{})
+	masterVesselSchedule.Description = "Port calls of a vessel voyage as published by the carrier"
+	leg := b.Object(schema.Leg{})
+	leg.AddField(&graphql.Field{
+		Name:        "vesselVoyages",
+		Description: "Master vessel voyages of the vessel sailing the leg, empty when the leg has no IMO",
+		Type:        &graphql.List{OfType: masterVesselSchedule},
+		Args: []*graphql.Argument{
+			{Name: "scac", Type: stringList, Description: "Carriers to ask, the carrier of the leg by default"},
+			{Name: "startDate", Type: str, Description: "YYYY-MM-DD, the departure date of the leg by default"},
+			{Name: "dateRange", Type: integer, Default: 7, Description: "Date Tolerance"},
+		},
+		Resolve: r.vesselVoyages,
+		Cost:    searchCost,
+	})
+
+	query := graphql.NewObject("Query", "")
+	query.AddField(&graphql.Field{
+		Name:        "p2pSchedules",
+		Description: "Point to point schedules of the requested carriers",
+		Type:        &graphql.List{OfType: required(p2pSchedule)},
+		Args: []*graphql.Argument{
+			{Name: "pointFrom", Type: required(str), Description: "Port Of Loading, a port group or a comma separated list"},
+			{Name: "pointTo", Type: required(str), Description: "Port Of Discharge, a port group or a comma separated list"},
+			{Name: "startDateType", Type: str, Default: string(schema.Departure), Description: "Search by either Departure or Arrival"},
+			{Name: "startDate", Type: required(str), Description: "YYYY-MM-DD"},
+			{Name: "searchRange", Type: integer, Default: 4, Description: "Search range based on start date and type, max 16 weeks"},
+			{Name: "scac", Type: stringList, Description: "Carriers to ask, the active carriers by default"},
+			{Name: "directOnly", Type: boolean},
+			{Name: "transhipmentPort", Type: str},
+			{Name: "vesselIMO", Type: str},
+			{Name: "service", Type: str},
+			{Name: "serviceName", Type: str},
+			{Name: "vesselName", Type: str},
+			{Name: "maxTransitTime", Type: integer},
+			{Name: "maxTransshipments", Type: integer},
+			{Name: "excludeTransshipment", Type: stringList},
+			{Name: "transportModes", Type: stringList},
+			{Name: "cutoffAfter", Type: str},
+			{Name: "sortBy", Type: str},
+			{Name: "dedupe", Type: boolean},
+			{Name: "connections", Type: boolean},
+		},
+		Resolve: r.p2pSchedules,
+		Cost:    searchCost,
+	})
+	query.AddField(&graphql.Field{
+		Name:        "masterVesselSchedules",
+		Description: "Master vessel voyages of the vessel per carrier",
+		Type:        &graphql.List{OfType: required(masterVesselSchedule)},
+		Args: []*graphql.Argument{
+			{Name: "vesselIMO", Type: required(str), Description: "vessel IMO lloyds code"},
+			{Name: "scac", Type: required(stringList)},
+			{Name: "startDate", Type: str, Description: "YYYY-MM-DD"},
+			{Name: "dateRange", Type: integer, Description: "Date Tolerance, required with startDate"},
+			{Name: "voyageNum", Type: str},
+		},
+		Resolve: r.masterVesselSchedules,
+		Cost:    searchCost,
+	})
+	return &graphql.Schema{Query: query, MaxDepth: maxQueryDepth, MaxCost: maxQueryCost, MaxConcurrency: maxConcurrency}
+}
+
+func stringArg(args map[string]any, name string) string {
+	value, _ := args[name].(string)
+	return value
+}
+
+func intArg(args map[string]any, name string) int {
+	value, _ := args[name].(int)
+	return value
+}
+
+// optionalIntArg keeps the difference between a filter left out and a filter set to 0
+func optionalIntArg(args map[string]any, name string) *int {
+	if value, ok := args[name].(int); ok {
+		return &value
+	}
+	return nil
+}
+
+func boolArg(args map[string]any, name string) bool {
+	value, _ := args[name].(bool)
+	return value
+}
+
+func stringListArg(args map[string]any, name string) []string {
+	items, _ := args[name].([]any)
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if value, ok := item.(string); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func carrierCodes(scacList []string) []schema.CarrierCode {
+	codes := make([]schema.CarrierCode, 0, len(scacList))
+	for _, scac := range scacList {
+		codes = append(codes, schema.CarrierCode(scac))
+	}
+	return codes
+}
+
+func (r *resolver) p2pSchedules(p graphql.ResolveParams) (any, error) {
+	settings, _ := p.Context.Value(middleware.ScheduleConfig).(map[string]interface{})
+	modes := make([]schema.TransportType, 0)
+	for _, mode := range stringListArg(p.Args, "transportModes") {
+		modes = append(modes, schema.TransportType(mode))
+	}
+	queryParams := schema.QueryParams{
+		PointFrom:     stringArg(p.Args, "pointFrom"),
+		PointTo:       stringArg(p.Args, "pointTo"),
+		StartDateType: schema.StartDateType(stringArg(p.Args, "startDateType")),
+		StartDate:     stringArg(p.Args, "startDate"),
+		SearchRange:   intArg(p.Args, "searchRange"),
+		SCAC:          carrierCodes(stringListArg(p.Args, "scac")),
+		DirectOnly:    boolArg(p.Args, "directOnly"),
+		TSP:           stringArg(p.Args, "transhipmentPort"),
+		VesselIMO:     stringArg(p.Args, "vesselIMO"),
+		Service:       stringArg(p.Args, "service"),
+		ServiceName:   stringArg(p.Args, "serviceName"),
+		VesselName:    stringArg(p.Args, "vesselName"),
+		MaxTransit:    optionalIntArg(p.Args, "maxTransitTime"),
+		MaxTSP:        optionalIntArg(p.Args, "maxTransshipments"),
+		ExcludeTSP:    stringListArg(p.Args, "excludeTransshipment"),
+		Modes:         modes,
+		CutoffAfter:   stringArg(p.Args, "cutoffAfter"),
+		SortBy:        schema.SortBy(stringArg(p.Args, "sortBy")),
+		Dedupe:        boolArg(p.Args, "dedupe"),
+		Connections:   boolArg(p.Args, "connections"),
+	}
+	if err := middleware.PrepareP2PQuery(&queryParams, settings); err != nil {
+		return nil, err
+	}
+	watchKey := fmt.Sprintf("graphql#p2p#%s#%s#%s#%s", queryParams.PointFrom, queryParams.PointTo, queryParams.StartDateType, queryParams.StartDate)
+	return r.p2p.Search(p.Context, &queryParams, settings, watchKey)
+}
+
+func (r *resolver) masterVesselSchedules(p graphql.ResolveParams) (any, error) {
+	queryParams := schema.QueryParamsForVesselVoyage{
+		SCAC:      carrierCodes(stringListArg(p.Args, "scac")),
+		VesselIMO: stringArg(p.Args, "vesselIMO"),
+		Voyage:    stringArg(p.Args, "voyageNum"),
+		StartDate: stringArg(p.Args, "startDate"),
+		DateRange: intArg(p.Args, "dateRange"),
+	}
+	return r.searchVoyages(p.Context, &queryParams)
+}
+
+func (r *resolver) searchVoyages(ctx context.Context, queryParams *schema.QueryParamsForVesselVoyage) ([]*schema.MasterVesselSchedule, error) {
+	if err := middleware.PrepareVesselVoyageQuery(queryParams); err != nil {
+		return nil, err
+	}
+	// the p2p router carries the p2p config, the carriers of the vessel schedules come from the mvs one
+	settings, err := middleware.AppConfig("service.registry.mvs")
+	if err != nil {
+		return nil, err
+	}
+	scacConfig, ok := settings["externalAPICarriers"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid schedule configuration")
+	}
+	watchKey := fmt.Sprintf("graphql#mvs#%s#%v#%s#%d", queryParams.VesselIMO, queryParams.SCAC, queryParams.StartDate, queryParams.DateRange)
+	return r.mvs.Search(ctx, queryParams, scacConfig, watchKey)
+}
+
+type voyagesMemoKey struct{}
+
+// voyageWeek widens the search of the leg to the ISO week of its start date, the legs of one vessel departing on different days
+// of the week then share a single lookup. The search is centred on the Thursday and reaches the Monday and the Sunday
+func voyageWeek(queryParams *schema.QueryParamsForVesselVoyage) string {
+	date, err := time.Parse("2006-01-02", queryParams.StartDate)
+	if err != nil {
+		return queryParams.StartDate
+	}
+	year, week := date.ISOWeek()
+	weekday := (int(date.Weekday()) + 6) % 7 // Monday is 0
+	queryParams.StartDate = date.AddDate(0, 0, 3-weekday).Format("2006-01-02")
+	queryParams.DateRange += 3
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// voyageCall is shared by the legs asking for the same vessel voyages, the transshipment legs of many schedules often sail on
+// the same vessel
+type voyageCall struct {
+	once      sync.Once
+	schedules []*schema.MasterVesselSchedule
+	err       error
+}
+
+// WithVoyageMemo scopes the vessel voyages looked up by the legs to one request
+func WithVoyageMemo(ctx context.Context) context.Context {
+	return context.WithValue(ctx, voyagesMemoKey{}, &sync.Map{})
+}
+
+func (r *resolver) vesselVoyages(p graphql.ResolveParams) (any, error) {
+	leg, ok := p.Source.(*schema.Leg)
+	if !ok || leg.Transportations.ReferenceType != "IMO" || !external.ValidateIMO(leg.Transportations.Reference) {
+		return nil, nil
+	}
+	scacList := stringListArg(p.Args, "scac")
+	if len(scacList) == 0 && leg.Scac != "" {
+		scacList = []string{leg.Scac}
+	}
+	if len(scacList) == 0 && len(p.Parents) > 0 {
+		if schedule, ok := p.Parents[0].(*schema.P2PSchedule); ok {
+			scacList = []string{schedule.Scac}
+		}
+	}
+	startDate := stringArg(p.Args, "startDate")
+	if startDate == "" {
+		startDate, _, _ = strings.Cut(leg.Etd, "T")
+	}
+	queryParams := schema.QueryParamsForVesselVoyage{
+		SCAC:      carrierCodes(scacList),
+		VesselIMO: leg.Transportations.Reference,
+		StartDate: startDate,
+		DateRange: intArg(p.Args, "dateRange"),
+	}
+	memo, ok := p.Context.Value(voyagesMemoKey{}).(*sync.Map)
+	if !ok {
+		return r.searchVoyages(p.Context, &queryParams)
+	}
+	week := voyageWeek(&queryParams)
+	key := fmt.Sprintf("%s|%v|%s|%d", queryParams.VesselIMO, queryParams.SCAC, week, queryParams.DateRange)
+	value, _ := memo.LoadOrStore(key, &voyageCall{})
+	call := value.(*voyageCall)
+	call.once.Do(func() {
+		call.schedules, call.err = r.searchVoyages(p.Context, &queryParams)
+	})
+	return call.schedules, call.err
+}
@@ -0,0 +1,34 @@
+package graphql_handler
+
+import (
+	"testing"
+
+	"github.com/neckchi/schedulehub/internal/schema"
+)
+
+func TestVoyageWeek(t *testing.T) {
+	tests := []struct {
+		startDate string
+		dateRange int
+		week      string
+		centre    string
+		wantRange int
+	}{
+		{startDate: "2024-03-11", dateRange: 7, week: "2024-W11", centre: "2024-03-14", wantRange: 10}, // Monday
+		{startDate: "2024-03-14", dateRange: 7, week: "2024-W11", centre: "2024-03-14", wantRange: 10}, // Thursday
+		{startDate: "2024-03-17", dateRange: 0, week: "2024-W11", centre: "2024-03-14", wantRange: 3},  // Sunday
+		{startDate: "2024-12-30", dateRange: 7, week: "2025-W01", centre: "2025-01-02", wantRange: 10}, // ISO week of the next year
+		{startDate: "not-a-date", dateRange: 7, week: "not-a-date", centre: "not-a-date", wantRange: 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.startDate, func(t *testing.T) {
+			queryParams := schema.QueryParamsForVesselVoyage{StartDate: tt.startDate, DateRange: tt.dateRange}
+			if week := voyageWeek(&queryParams); week != tt.week {
+				t.Errorf("voyageWeek() = %q, want %q", week, tt.week)
+			}
+			if queryParams.StartDate != tt.centre || queryParams.DateRange != tt.wantRange {
+				t.Errorf("search of %s±%d, want %s±%d", queryParams.StartDate, queryParams.DateRange, tt.centre, tt.wantRange)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package mvs_handler
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/neckchi/schedulehub/internal/schema"
+	"github.com/neckchi/schedulehub/internal/utils"
+	log "github.com/sirupsen/logrus"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VesselScheduleEncoder writes the vessel schedules in one of the output formats negotiated with the client
+type VesselScheduleEncoder interface {
+	ContentType() string
+	Open(w utils.FlushWriter, vesselIMO string) error
+	Schedule(w utils.FlushWriter, schedule *schema.MasterVesselSchedule) error
+	Close(w utils.FlushWriter, total int) error
+}
+
+// NegotiateEncoder picks the encoder of the format parameter or else of the Accept header, the JSON envelope is the default. The
+// fieldset only prunes the JSON envelope
+func NegotiateEncoder(accept string, format string, fields schema.FieldSet) VesselScheduleEncoder {
+	switch format {
+	case "ics":
+		return &icsEncoder{}
+	case "dcsa":
+		return &dcsaEncoder{}
+	}
+	if format == "" {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(mediaRange)); err == nil && mediaType == "text/calendar" {
+				return &icsEncoder{}
+			}
+		}
+	}
+	return &jsonEncoder{fields: fields}
+}
+
+// jsonEncoder keeps the original envelope {"vesselIMO","vesselSchedules":[...]}
+type jsonEncoder struct {
+	written int
+	fields  schema.FieldSet
+}
+
+func (e *jsonEncoder) ContentType() string { return "application/json" }
+
+func (e *jsonEncoder) Open(w utils.FlushWriter, vesselIMO string) error {
+	_, err := fmt.Fprintf(w, `{"vesselIMO":"%s","vesselSchedules":[`, vesselIMO)
+	return err
+}
+
+func (e *jsonEncoder) Schedule(w utils.FlushWriter, schedule *schema.MasterVesselSchedule) error {
+	scheduleJSON, err := json.Marshal(e.fields.Apply(schedule))
+	if err != nil {
+		return err
+	}
+	if e.written > 0 {
+		scheduleJSON = append([]byte(","), scheduleJSON...)
+	}
+	e.written++
+	_, err = w.Write(scheduleJSON)
+	return err
+}
+
+func (e *jsonEncoder) Close(w utils.FlushWriter, total int) error {
+	var err error
+	if total == 0 {
+		_, err = w.Write([]byte(`],"message":"No available schedules for the requested route."}`))
+	} else {
+		_, err = w.Write([]byte(`]}`))
+	}
+	return err
+}
+
+// dcsaEncoder writes the array of DCSA Operational Vessel Schedules services. A service not matching the spec is left out and
+// counted in the Dcsa-Dropped trailer
+type dcsaEncoder struct {
+	written int
+	dropped int
+}
+
+func (e *dcsaEncoder) ContentType() string { return "application/json" }
+
+func (e *dcsaEncoder) Open(w utils.FlushWriter, _ string) error {
+	w.Header().Set("Trailer", schema.DCSADroppedTrailer)
+	_, err := w.Write([]byte("["))
+	return err
+}
+
+func (e *dcsaEncoder) Schedule(w utils.FlushWriter, schedule *schema.MasterVesselSchedule) error {
+	service := schema.NewDCSAServiceSchedule(schedule)
+	if err := schema.DCSAValidate.Struct(&service); err != nil {
+		log.Errorf("Vessel schedule of %s does not match the DCSA service schedule shape: %v", schedule.Scac, err)
+		e.dropped++
+		return nil
+	}
+	serviceJSON, err := json.Marshal(&service)
+	if err != nil {
+		return err
+	}
+	if e.written > 0 {
+		serviceJSON = append([]byte(","), serviceJSON...)
+	}
+	e.written++
+	_, err = w.Write(serviceJSON)
+	return err
+}
+
+func (e *dcsaEncoder) Close(w utils.FlushWriter, _ int) error {
+	_, err := w.Write([]byte("]"))
+	w.Header().Set(schema.DCSADroppedTrailer, strconv.Itoa(e.dropped))
+	return err
+}
+
+const icsLayout = "20060102T150405"
+
+// icsEncoder turns every port call into a VEVENT. The UID only depends on the carrier, vessel, voyage, sequence and event so that a
+// calendar subscribed to the rotation updates the events instead of duplicating them when it is fetched again
+type icsEncoder struct {
+	stamp string
+}
+
+func (e *icsEncoder) ContentType() string { return "text/calendar; charset=utf-8" }
+
+func (e *icsEncoder) Open(w utils.FlushWriter, vesselIMO string) error {
+	e.stamp = time.Now().UTC().Format(icsLayout) + "Z"
+	return writeICSLines(w,
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//schedulehub//mastervoyage//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:"+escapeICSText("Vessel "+vesselIMO+" rotation"),
+	)
+}
+
+// callVoyage flattens the voyage of the call, the calls shared by two voyages carry both of them
+func callVoyage(call schema.PortCalls, schedule *schema.MasterVesselSchedule) string {
+	switch voyage := call.Voyage.(type) {
+	case string:
+		return voyage
+	case []string:
+		return strings.Join(voyage, "/")
+	case []interface{}:
+		voyages := make([]string, 0, len(voyage))
+		for _, v := range voyage {
+			voyages = append(voyages, fmt.Sprint(v))
+		}
+		return strings.Join(voyages, "/")
+	}
+	return schedule.Voyage
+}
+
+func eventUID(scac, imo, voyage string, seq int, event string) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%d|%s", scac, imo, voyage, seq, event)))
+	return hex.EncodeToString(hash[:16]) + "@schedulehub"
+}
+
+func (e *icsEncoder) Schedule(w utils.FlushWriter, schedule *schema.MasterVesselSchedule) error {
+	var vesselName, imo string
+	if schedule.Vessel != nil {
+		vesselName, imo = schedule.Vessel.VesselName, schedule.Vessel.Imo
+	}
+	for _, call := range schedule.Calls {
+		eventDate, eventDateUTC := call.ActualEventDate, call.ActualEventDateUTC
+		status := "CONFIRMED"
+		if eventDate == "" {
+			eventDate, eventDateUTC, status = call.EstimatedEventDate, call.EstimatedEventDateUTC, "TENTATIVE"
+		}
+		start, err := time.Parse("2006-01-02T15:04:05", eventDate)
+		if err != nil {
+			continue
+		}
+		// the event is pinned to UTC when the zone of the port is known, otherwise the local port time is written as a floating time
+		dtStart := start.Format(icsLayout)
+		if startUTC, err := time.Parse("2006-01-02T15:04:05Z", eventDateUTC); err == nil {
+			dtStart = startUTC.Format(icsLayout) + "Z"
+		}
+		voyage := callVoyage(call, schedule)
+		var portName, portCode, terminal, service string
+		if call.Port != nil {
+			portName, portCode, terminal = call.Port.PortName, call.Port.PortCode, call.Port.TerminalName
+		}
+		if call.Service != nil {
+			service = cmp.Or(call.Service.ServiceName, call.Service.ServiceCode)
+		} else if schedule.Services != nil {
+			service = cmp.Or(schedule.Services.ServiceName, schedule.Services.ServiceCode)
+		}
+		location := strings.TrimSpace(strings.Join([]string{cmp.Or(portName, portCode), terminal}, " "))
+		description := []string{
+			"Carrier: " + schedule.Scac,
+			"Voyage: " + voyage,
+			"Service: " + service,
+			"Port: " + portCode,
+			"Terminal: " + terminal,
+			"Estimated: " + call.EstimatedEventDate,
+			"Actual: " + call.ActualEventDate,
+		}
+		if err := writeICSLines(w,
+			"BEGIN:VEVENT",
+			"UID:"+eventUID(schedule.Scac, imo, voyage, call.Seq, call.PortEvent),
+			"DTSTAMP:"+e.stamp,
+			"DTSTART:"+dtStart,
+			"SUMMARY:"+escapeICSText(fmt.Sprintf("%s %s %s at %s", vesselName, voyage, call.PortEvent, cmp.Or(portName, portCode))),
+			"LOCATION:"+escapeICSText(location),
+			"DESCRIPTION:"+escapeICSText(strings.Join(description, "\n")),
+			"STATUS:"+status,
+			"END:VEVENT",
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *icsEncoder) Close(w utils.FlushWriter, _ int) error {
+	return writeICSLines(w, "END:VCALENDAR")
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+func escapeICSText(text string) string {
+	return icsEscaper.Replace(text)
+}
+
+// writeICSLines ends every line with CRLF and folds the lines longer than 75 octets as RFC 5545 requires
+func writeICSLines(w utils.FlushWriter, lines ...string) error {
+	var calendar strings.Builder
+	for _, line := range lines {
+		// the folded lines start with a space which counts as well
+		for limit := 75; len(line) > limit; limit = 74 {
+			cut := limit
+			for cut > 0 && line[cut]&0xC0 == 0x80 { // do not split a multi byte character
+				cut--
+			}
+			calendar.WriteString(line[:cut] + "\r\n ")
+			line = line[cut:]
+		}
+		calendar.WriteString(line + "\r\n")
+	}
+	_, err := w.Write([]byte(calendar.String()))
+	return err
+}
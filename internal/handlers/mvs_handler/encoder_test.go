@@ -0,0 +1,121 @@
+package mvs_handler
+
+import (
+	"github.com/neckchi/schedulehub/internal/schema"
+	"github.com/neckchi/schedulehub/internal/utils"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWriteICSLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		lines int
+	}{
+		{name: "short", line: "SUMMARY:MAERSK ESSEN 412W Loading at Singapore", lines: 1},
+		{name: "75 octets", line: "DESCRIPTION:" + strings.Repeat("a", 63), lines: 1},
+		{name: "76 octets", line: "DESCRIPTION:" + strings.Repeat("a", 64), lines: 2},
+		{name: "long", line: "DESCRIPTION:" + strings.Repeat("abcdefghij", 20), lines: 3},
+		{name: "multi byte characters", line: "LOCATION:" + strings.Repeat("ü", 40) + strings.Repeat("港", 30), lines: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rsp := httptest.NewRecorder()
+			if err := writeICSLines(utils.NewFlushWriter(rsp), tt.line); err != nil {
+				t.Fatal(err)
+			}
+			written := rsp.Body.String()
+			if !strings.HasSuffix(written, "\r\n") {
+				t.Fatalf("%q does not end with CRLF", written)
+			}
+			physical := strings.Split(strings.TrimSuffix(written, "\r\n"), "\r\n")
+			if len(physical) != tt.lines {
+				t.Errorf("folded into %d lines, want %d", len(physical), tt.lines)
+			}
+			for i, line := range physical {
+				if len(line) > 75 {
+					t.Errorf("line %d has %d octets", i, len(line))
+				}
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("continuation line %d does not start with a space", i)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a character: %q", i, line)
+				}
+			}
+			if unfolded := strings.ReplaceAll(strings.TrimSuffix(written, "\r\n"), "\r\n ", ""); unfolded != tt.line {
+				t.Errorf("unfolded %q, want %q", unfolded, tt.line)
+			}
+		})
+	}
+}
+
+func TestEscapeICSText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "Singapore", want: "Singapore"},
+		{text: `Terminal; Pier 1, Berth\2`, want: `Terminal\; Pier 1\, Berth\\2`},
+		{text: "Carrier: MAEU\nVoyage: 412W", want: `Carrier: MAEU\nVoyage: 412W`},
+	}
+	for _, tt := range tests {
+		if got := escapeICSText(tt.text); got != tt.want {
+			t.Errorf("escapeICSText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestICSEncoder(t *testing.T) {
+	schedule := &schema.MasterVesselSchedule{
+		Scac: "MAEU", Voyage: "412W",
+		Vessel:   &schema.VesselDetails{VesselName: "MAERSK ESSEN", Imo: "9456771"},
+		Services: &schema.Services{ServiceCode: "AE1", ServiceName: "Asia Europe 1"},
+		Calls: []schema.PortCalls{
+			{
+				Seq: 1, Voyage: "412W", PortEvent: "Loading", Port: &schema.Port{PortName: "Singapore", PortCode: "SGSIN", TerminalName: "PSA"},
+				EstimatedEventDate: "2024-03-10T08:00:00", ActualEventDate: "2024-03-10T09:00:00", ActualEventDateUTC: "2024-03-10T01:00:00Z",
+			},
+			{Seq: 2, Voyage: []string{"412W", "413E"}, PortEvent: "Unloading", Port: &schema.Port{PortCode: "LKCMB"}, EstimatedEventDate: "2024-03-14T06:00:00"},
+			{Seq: 3, PortEvent: "Loading", Port: &schema.Port{PortCode: "DEHAM"}}, // no date, left out
+		},
+	}
+	rsp := httptest.NewRecorder()
+	w := utils.NewFlushWriter(rsp)
+	encoder := &icsEncoder{}
+	if err := encoder.Open(w, "9456771"); err != nil {
+		t.Fatal(err)
+	}
+	if err := encoder.Schedule(w, schedule); err != nil {
+		t.Fatal(err)
+	}
+	if err := encoder.Close(w, 1); err != nil {
+		t.Fatal(err)
+	}
+	calendar := strings.ReplaceAll(rsp.Body.String(), "\r\n ", "")
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Vessel 9456771 rotation\r\n",
+		"UID:" + eventUID("MAEU", "9456771", "412W", 1, "Loading") + "\r\n",
+		"DTSTART:20240310T010000Z\r\nSUMMARY:MAERSK ESSEN 412W Loading at Singapore\r\nLOCATION:Singapore PSA\r\n",
+		"STATUS:CONFIRMED\r\n",
+		"UID:" + eventUID("MAEU", "9456771", "412W/413E", 2, "Unloading") + "\r\n",
+		"DTSTART:20240314T060000\r\nSUMMARY:MAERSK ESSEN 412W/413E Unloading at LKCMB\r\n",
+		"STATUS:TENTATIVE\r\n",
+		`DESCRIPTION:Carrier: MAEU\nVoyage: 412W/413E\nService: Asia Europe 1\nPort: LKCMB\n`,
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(calendar, want) {
+			t.Errorf("calendar does not contain %q\n%s", want, calendar)
+		}
+	}
+	if events := strings.Count(calendar, "BEGIN:VEVENT"); events != 2 {
+		t.Errorf("got %d events, want 2", events)
+	}
+	if strings.Contains(strings.ReplaceAll(rsp.Body.String(), "\r\n", ""), "\n") {
+		t.Error("a line ends without CR")
+	}
+}
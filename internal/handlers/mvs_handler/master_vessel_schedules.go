@@ -46,10 +46,13 @@ func VoyageHandler(s *VoyageService) http.Handler {
 		}
 		ctx, cancel := context.WithCancel(r.Context())
 		defer cancel() // Ensure cancellation when function exits
-		mvsService := NewMastervVesselVoyageService(ctx, s.oracle, s.client, s.env, s.vs, &queryParams, scacConfig)
+		mvsService := NewMastervVesselVoyageService(ctx, s.oracle, s.client, s.env, s.vs, s.redis, &queryParams, scacConfig)
 		fanoutMVSChannels := mvsService.FanOutMVSChannels()
 		fannedInStream := mvsService.FanInMasterVesselSchedule(fanoutMVSChannels...)
-		mvsService.StreamMasterVesselSchedule(fw, fannedInStream)
+		encoder := NegotiateEncoder(r.Header.Get("Accept"), queryParams.Format, queryParams.FieldSet)
+		w.Header().Set("Content-Type", encoder.ContentType())
+		w.Header().Add("Vary", "Accept")
+		mvsService.StreamMasterVesselSchedule(fw, fannedInStream, encoder)
 		go func() {
 			err := s.redis.Set(r.URL.String())
 			if err != nil {
@@ -58,3 +61,38 @@ func VoyageHandler(s *VoyageService) http.Handler {
 		}()
 	})
 }
+
+// Stream hands the vessel schedule of every requested carrier to send as soon as it is consolidated, the cache is flushed under
+// watchKey once the carriers have answered
+func (s *VoyageService) Stream(ctx context.Context, queryParams *schema.QueryParamsForVesselVoyage, scacConfig map[string]interface{}, watchKey string, send func(*schema.MasterVesselSchedule) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	mvsService := NewMastervVesselVoyageService(ctx, s.oracle, s.client, s.env, s.vs, s.redis, queryParams, scacConfig)
+	var sendErr error
+	for schedule := range mvsService.FanInMasterVesselSchedule(mvsService.FanOutMVSChannels()...) {
+		if sendErr == nil {
+			if sendErr = send(schedule); sendErr != nil {
+				cancel()
+			}
+		}
+	}
+	go func() {
+		if err := s.redis.Set(watchKey); err != nil {
+			log.Error(err)
+		}
+	}()
+	if sendErr != nil {
+		return sendErr
+	}
+	return ctx.Err()
+}
+
+// Search collects the vessel schedules of every requested carrier
+func (s *VoyageService) Search(ctx context.Context, queryParams *schema.QueryParamsForVesselVoyage, scacConfig map[string]interface{}, watchKey string) ([]*schema.MasterVesselSchedule, error) {
+	results := make([]*schema.MasterVesselSchedule, 0)
+	err := s.Stream(ctx, queryParams, scacConfig, watchKey, func(schedule *schema.MasterVesselSchedule) error {
+		results = append(results, schedule)
+		return nil
+	})
+	return results, err
+}
@@ -3,8 +3,8 @@ package mvs_handler
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"github.com/go-playground/validator/v10"
+	"github.com/neckchi/schedulehub/external"
 	"github.com/neckchi/schedulehub/external/carrier_vessel_schedule"
 	"github.com/neckchi/schedulehub/internal/database"
 	httpclient "github.com/neckchi/schedulehub/internal/http"
@@ -13,6 +13,7 @@ import (
 	"github.com/neckchi/schedulehub/internal/utils"
 	log "github.com/sirupsen/logrus"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -22,6 +23,7 @@ type MasterVesselSchedule struct {
 	client         *httpclient.HttpClient
 	env            *env.Manager
 	vv             *carrier_vessel_schedule.VesselScheduleServiceFactory
+	redis          database.RedisRepository
 	queryParams    *schema.QueryParamsForVesselVoyage
 	scheduleConfig map[string]interface{}
 }
@@ -33,6 +35,7 @@ func NewMastervVesselVoyageService(
 	client *httpclient.HttpClient,
 	env *env.Manager,
 	vv *carrier_vessel_schedule.VesselScheduleServiceFactory,
+	redis database.RedisRepository,
 	queryParams *schema.QueryParamsForVesselVoyage,
 	scheduleConfig map[string]any) *MasterVesselSchedule {
 	return &MasterVesselSchedule{
@@ -41,6 +44,7 @@ func NewMastervVesselVoyageService(
 		client:         client,
 		env:            env,
 		vv:             vv,
+		redis:          redis,
 		queryParams:    queryParams,
 		scheduleConfig: scheduleConfig,
 	}
@@ -60,10 +64,12 @@ func (mvs *MasterVesselSchedule) ConsolidateMasterVesselSchedule(scac schema.Car
 	stream := make(chan *schema.MasterVesselSchedule)
 	go func() {
 		defer close(stream)
+		masterVesselSchedule := mvs.FetchMasterVesselSchedule(scac)
+		external.ApplyPortCallTimeZones(masterVesselSchedule)
 		select {
 		case <-mvs.ctx.Done():
 			return
-		case stream <- mvs.FetchMasterVesselSchedule(scac):
+		case stream <- masterVesselSchedule:
 		}
 	}()
 	return stream
@@ -77,7 +83,18 @@ func (mvs *MasterVesselSchedule) FetchMasterVesselSchedule(scac schema.CarrierCo
 			log.Errorf("Failed to create schedule service: %s", err)
 			return nil
 		}
-		masterVesselSchedule, _ := service.FetchSchedule(mvs.ctx, mvs.client, mvs.env, mvs.queryParams, scac)
+		if cached, exist := mvs.cachedVesselSchedule(scac); exist {
+			return cached
+		}
+		config, _ := mvs.vv.CarrierConfig(scac)
+		var fresh atomic.Bool
+		ctx := httpclient.WithRefreshHook(mvs.ctx, func(namespace string) {
+			if namespace == config.CacheKey {
+				fresh.Store(true)
+			}
+		})
+		masterVesselSchedule, _ := service.FetchSchedule(ctx, mvs.client, mvs.env, mvs.queryParams, scac)
+		mvs.cacheVesselSchedule(scac, masterVesselSchedule, fresh.Load(), config.CacheDuration)
 		return masterVesselSchedule
 	}
 	//Query database
@@ -156,40 +173,28 @@ func (mvs *MasterVesselSchedule) FanInMasterVesselSchedule(channels ...<-chan *s
 	return fannedInStream
 }
 
-// StreamResponse handles the streaming of response data
-func (mvs *MasterVesselSchedule) StreamMasterVesselSchedule(w utils.FlushWriter, fannedIn <-chan *schema.MasterVesselSchedule) {
-	_, _ = w.Write([]byte(fmt.Sprintf(
-		`{"vesselIMO":"%s","vesselSchedules":[`, mvs.queryParams.VesselIMO,
-	)))
+// StreamMasterVesselSchedule writes every vessel schedule with the negotiated encoder as soon as the carrier answers
+func (mvs *MasterVesselSchedule) StreamMasterVesselSchedule(w utils.FlushWriter, fannedIn <-chan *schema.MasterVesselSchedule, encoder VesselScheduleEncoder) {
+	if err := encoder.Open(w, mvs.queryParams.VesselIMO); err != nil {
+		log.Errorf("Failed to write the response: %v", err)
+		return
+	}
 	w.Flush() // Flush data right away
 
 	scheduleCount := 0
-	first := true
-	doneProcessing := make(chan int) // Signal when processing is done
-	go func() {
-		defer close(doneProcessing)
-		for schedules := range fannedIn {
-			select {
-			case <-mvs.ctx.Done():
-				return
-			default:
-				if !first {
-					_, _ = w.Write([]byte(","))
-				}
-				first = false
-				scheduleJSON, _ := json.Marshal(schedules) // this need to be changed
-				_, _ = w.Write(scheduleJSON)
-				w.Flush()
-				scheduleCount++
-			}
+	for schedule := range fannedIn {
+		if mvs.ctx.Err() != nil {
+			return
 		}
-	}()
-
-	<-doneProcessing // Block until goroutine finishes (ensures JSON is properly closed)
-	if scheduleCount == 0 {
-		_, _ = w.Write([]byte(`],"message":"No available schedules for the requested route."}`))
-	} else {
-		_, _ = w.Write([]byte(`]}`))
+		if err := encoder.Schedule(w, schedule); err != nil {
+			log.Errorf("Failed to write the vessel schedule of %s: %v", schedule.Scac, err)
+			return
+		}
+		w.Flush()
+		scheduleCount++
+	}
+	if err := encoder.Close(w, scheduleCount); err != nil {
+		log.Errorf("Failed to write the response: %v", err)
 	}
 	w.Flush()
 }
@@ -0,0 +1,133 @@
+package mvs_handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/neckchi/schedulehub/external"
+	"github.com/neckchi/schedulehub/internal/database"
+	"github.com/neckchi/schedulehub/internal/schema"
+	log "github.com/sirupsen/logrus"
+	"slices"
+	"time"
+)
+
+const (
+	vesselVoyageNamespace = "vessel voyage"
+	vesselIndexNamespace  = "vessel voyage index"
+)
+
+// The normalized voyages are cached per scac, imo and voyage no matter which date window or voyage number was asked, so every
+// variant of the query is answered by the same copy. An index per scac and imo(a redis set) lists the cached voyages of the vessel.
+
+type cachedVoyage struct {
+	FetchedAt time.Time                    `json:"fetchedAt"`
+	Schedule  *schema.MasterVesselSchedule `json:"schedule"`
+}
+
+func voyageCacheKey(scac schema.CarrierCode, imo, voyage string) string {
+	return fmt.Sprintf("%s:%s:%s", scac, imo, voyage)
+}
+
+func vesselIndexKey(scac schema.CarrierCode, imo string) string {
+	return fmt.Sprintf("%s:%s", scac, imo)
+}
+
+// dateWindow is the same window the carriers are asked for
+func dateWindow(q *schema.QueryParamsForVesselVoyage) (string, string) {
+	startDate := q.StartDate
+	if startDate == "" {
+		startDate = time.Now().Format("2006-01-02")
+	}
+	return external.CalculateDateRangeForMVS(startDate, q.DateRange)
+}
+
+func callDate(call schema.PortCalls) string {
+	date := call.EstimatedEventDate
+	if call.ActualEventDate != "" {
+		date = call.ActualEventDate
+	}
+	return date[:min(len(date), len("2006-01-02"))]
+}
+
+// overlaps tells whether any call of the voyage falls into the window
+func overlaps(schedule *schema.MasterVesselSchedule, from, to string) bool {
+	return slices.ContainsFunc(schedule.Calls, func(call schema.PortCalls) bool {
+		date := callDate(call)
+		return date >= from && date <= to
+	})
+}
+
+func loadVoyage(redis database.RedisRepository, key string) (*cachedVoyage, bool) {
+	cached, exist := redis.Get(vesselVoyageNamespace, key)
+	if !exist {
+		return nil, false
+	}
+	var voyage cachedVoyage
+	if err := json.Unmarshal(cached, &voyage); err != nil || voyage.Schedule == nil {
+		log.Errorf("Failed to decode cached voyage %s: %v", key, err)
+		return nil, false
+	}
+	return &voyage, true
+}
+
+// cachedVesselSchedule answers the query from the cached voyages of the vessel. The voyage number wins over the date window
+func (mvs *MasterVesselSchedule) cachedVesselSchedule(scac schema.CarrierCode) (*schema.MasterVesselSchedule, bool) {
+	imo := mvs.queryParams.VesselIMO
+	if mvs.queryParams.Voyage != "" {
+		voyage, exist := loadVoyage(mvs.redis, voyageCacheKey(scac, imo, mvs.queryParams.Voyage))
+		if !exist {
+			return nil, false
+		}
+		return voyage.Schedule, true
+	}
+	from, to := dateWindow(mvs.queryParams)
+	var found *schema.MasterVesselSchedule
+	var expired []string
+	voyages, _ := mvs.redis.Members(vesselIndexNamespace, vesselIndexKey(scac, imo))
+	for _, voyageNum := range voyages {
+		voyage, exist := loadVoyage(mvs.redis, voyageCacheKey(scac, imo, voyageNum))
+		if !exist {
+			expired = append(expired, voyageNum)
+			continue
+		}
+		if !overlaps(voyage.Schedule, from, to) {
+			continue
+		}
+		// the earliest voyage in the window is what the carrier would have answered
+		if found == nil || callDate(voyage.Schedule.Calls[0]) < callDate(found.Calls[0]) {
+			found = voyage.Schedule
+		}
+	}
+	// the voyages expire on their own, the index only learns it here
+	_ = mvs.redis.RemoveMembers(vesselIndexNamespace, vesselIndexKey(scac, imo), expired...)
+	return found, found != nil
+}
+
+// cacheVesselSchedule stores the voyage. A fresh carrier response overwrites the older copy, a response served from the
+// carrier cache only fills the gap as it might be older than the copy we already have.
+func (mvs *MasterVesselSchedule) cacheVesselSchedule(scac schema.CarrierCode, schedule *schema.MasterVesselSchedule, fresh bool, expiry time.Duration) {
+	// invalid voyages are dropped later on by the validation stage so they must not be cached either
+	if schedule == nil || len(schedule.Calls) == 0 || schema.MVSResponseValidate.Struct(schedule) != nil {
+		return
+	}
+	imo := mvs.queryParams.VesselIMO
+	if schedule.Vessel != nil && schedule.Vessel.Imo != "" {
+		imo = schedule.Vessel.Imo
+	}
+	key := voyageCacheKey(scac, imo, schedule.Voyage)
+	if !fresh {
+		if _, exist := loadVoyage(mvs.redis, key); exist {
+			return
+		}
+	}
+	voyageJSON, err := json.Marshal(&cachedVoyage{FetchedAt: time.Now(), Schedule: schedule})
+	if err != nil {
+		log.Errorf("Failed to encode voyage %s: %v", key, err)
+		return
+	}
+	if err := mvs.redis.Put(vesselVoyageNamespace, map[string][]byte{key: voyageJSON}, expiry); err != nil {
+		return
+	}
+
+	_ = mvs.redis.AddMembers(vesselIndexNamespace, vesselIndexKey(scac, imo), []string{schedule.Voyage}, expiry)
+}
@@ -0,0 +1,164 @@
+package p2p_schedule_handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/neckchi/schedulehub/internal/middleware"
+	"github.com/neckchi/schedulehub/internal/schema"
+	"github.com/neckchi/schedulehub/internal/utils"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// defaultConcurrency is the number of lanes one request searches at the same time when batch.concurrency is not configured, so a
+// big batch or a GraphQL query with many fields can not exhaust the carrier quota or the redis pool on its own
+const defaultConcurrency = 16
+
+type budgetKey struct{}
+
+// WithBudget scopes the lanes searched at the same time to one request, each batch or GraphQL request gets its own budget
+func WithBudget(ctx context.Context, settings map[string]interface{}) context.Context {
+	concurrency := max(utils.IntSetting(utils.MapSetting(settings, "batch"), "concurrency", defaultConcurrency), 1)
+	return context.WithValue(ctx, budgetKey{}, make(chan struct{}, concurrency))
+}
+
+// acquireBudget waits for a slot of the request budget. A request without one(e.g. a gRPC stream searching a single lane) goes
+// ahead right away
+func acquireBudget(ctx context.Context) (release func(), err error) {
+	budget, ok := ctx.Value(budgetKey{}).(chan struct{})
+	if !ok {
+		return func() {}, nil
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case budget <- struct{}{}:
+	}
+	return func() { <-budget }, nil
+}
+
+// searchLane runs the lane through the usual pipeline once it gets a slot of the budget and tags every schedule with the lane index
+func (s *P2PScheduleService) searchLane(ctx context.Context, index int, queryParams *schema.QueryParams, settings map[string]interface{}, lines chan<- schema.BatchScheduleLine, watchKey string) {
+	release, err := acquireBudget(ctx)
+	if err != nil {
+		return
+	}
+	defer release()
+
+	s.lanes.Record(queryParams)
+	service := NewScheduleStreamingService(ctx, s.client, s.env, s.ps, s.redis, queryParams)
+	stream, finish := service.Pipeline(settings)
+	origin, destination := strings.Join(queryParams.OriginPorts(), ","), strings.Join(queryParams.DestinationPorts(), ",")
+	total := 0
+	for schedules := range stream {
+		for _, schedule := range schedules {
+			select {
+			case <-ctx.Done():
+				return
+			case lines <- schema.BatchScheduleLine{Lane: index, Origin: origin, Destination: destination, Schedule: schedule}:
+				total++
+			}
+		}
+	}
+	finish()
+	closing := schema.BatchScheduleLine{Lane: index, Origin: origin, Destination: destination, Complete: true, Total: total}
+	if total == 0 {
+		closing.Message = "No available schedules for the requested route."
+	}
+	select {
+	case <-ctx.Done():
+	case lines <- closing:
+	}
+	// flush the cache of each lane on its own as the cache channel is too small for the whole batch
+	go func() {
+		if err := s.redis.Set(watchKey); err != nil {
+			log.Error(err)
+		}
+	}()
+}
+
+func P2PBatchHandler(s *P2PScheduleService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fw := utils.NewFlushWriter(w)
+		batch, _ := r.Context().Value(middleware.P2PBatchQueryParamsKey).(schema.BatchQuery)
+		settings, _ := r.Context().Value(middleware.ScheduleConfig).(map[string]interface{})
+		ctx, cancel := context.WithCancel(WithBudget(r.Context(), settings))
+		defer cancel() // Ensure cancellation when function exits
+
+		lines := make(chan schema.BatchScheduleLine)
+		var wg sync.WaitGroup
+		for i := range batch.Lanes {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				s.searchLane(ctx, i, &batch.Lanes[i], settings, lines, fmt.Sprintf("%s#%d", r.URL, i))
+			}(i)
+		}
+		go func() {
+			wg.Wait()
+			close(lines)
+		}()
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		encoder := json.NewEncoder(fw)
+		for line := range lines {
+			if err := encoder.Encode(&line); err != nil {
+				log.Errorf("Failed to write batch line of lane %d: %v", line.Lane, err)
+				cancel()
+				continue
+			}
+			fw.Flush()
+		}
+	})
+}
+
+// Stream hands the schedules of the query to send as the carriers answer, for the callers living outside the HTTP handlers(e.g.
+// the GraphQL fields and the gRPC stream). It waits for the budget of the request given by WithBudget and flushes the cache under
+// watchKey at the end
+func (s *P2PScheduleService) Stream(ctx context.Context, queryParams *schema.QueryParams, settings map[string]interface{}, watchKey string, send func(*schema.P2PSchedule) error) error {
+	release, err := acquireBudget(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // stops the carriers when send fails
+	s.lanes.Record(queryParams)
+	service := NewScheduleStreamingService(ctx, s.client, s.env, s.ps, s.redis, queryParams)
+	stream, finish := service.Pipeline(settings)
+	var sendErr error
+	for schedules := range stream {
+		for _, schedule := range schedules {
+			if sendErr == nil {
+				sendErr = send(schedule)
+			}
+		}
+		if sendErr != nil {
+			cancel()
+		}
+	}
+	finish()
+	go func() {
+		if err := s.redis.Set(watchKey); err != nil {
+			log.Error(err)
+		}
+	}()
+	if sendErr != nil {
+		return sendErr
+	}
+	return ctx.Err()
+}
+
+// Search collects the schedules of the query for the callers that need them all before answering
+func (s *P2PScheduleService) Search(ctx context.Context, queryParams *schema.QueryParams, settings map[string]interface{}, watchKey string) ([]*schema.P2PSchedule, error) {
+	results := make([]*schema.P2PSchedule, 0)
+	err := s.Stream(ctx, queryParams, settings, watchKey, func(schedule *schema.P2PSchedule) error {
+		results = append(results, schedule)
+		return nil
+	})
+	return results, err
+}
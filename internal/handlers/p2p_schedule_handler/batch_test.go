@@ -0,0 +1,30 @@
+package p2p_schedule_handler
+
+import (
+	"context"
+	"testing"
+)
+
+// TestWithBudget checks that every request gets its own budget of the configured size
+func TestWithBudget(t *testing.T) {
+	settings := map[string]interface{}{"batch": map[string]interface{}{"concurrency": 2}}
+	first, second := WithBudget(context.Background(), settings), WithBudget(context.Background(), settings)
+	for range 2 {
+		if _, err := acquireBudget(first); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx, cancel := context.WithCancel(first)
+	cancel()
+	if _, err := acquireBudget(ctx); err == nil {
+		t.Error("acquired a third slot of a budget of 2")
+	}
+	release, err := acquireBudget(second)
+	if err != nil {
+		t.Fatalf("the budget of another request is taken: %v", err)
+	}
+	release()
+	if _, err := acquireBudget(context.Background()); err != nil {
+		t.Errorf("a request without budget waits: %v", err)
+	}
+}
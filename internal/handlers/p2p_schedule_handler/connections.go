@@ -0,0 +1,173 @@
+package p2p_schedule_handler
+
+import (
+	"github.com/neckchi/schedulehub/external"
+	"github.com/neckchi/schedulehub/internal/schema"
+	"github.com/neckchi/schedulehub/internal/utils"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// connectionSettings are the hubs where the schedules of two carriers may be joined and how long the cargo may wait there
+type connectionSettings struct {
+	hubs          []string
+	minConnection time.Duration
+	maxConnection time.Duration
+}
+
+func newConnectionSettings(settings map[string]interface{}) connectionSettings {
+	return connectionSettings{
+		hubs:          utils.StringListSetting(settings, "hubs"),
+		minConnection: time.Duration(utils.IntSetting(settings, "minConnectionHours", 24)) * time.Hour,
+		maxConnection: time.Duration(utils.IntSetting(settings, "maxConnectionDays", 7)) * 24 * time.Hour,
+	}
+}
+
+// hubQueries splits the pair at the hub. The leg that is not anchored by the requested window is searched over a few more weeks so
+// that it still covers the sailings connecting at the edge of the window
+func (c connectionSettings) hubQueries(pair *schema.QueryParams, hub string) (*schema.QueryParams, *schema.QueryParams) {
+	extraWeeks := int((c.maxConnection + 7*24*time.Hour - 1) / (7 * 24 * time.Hour))
+	feeder, mainline := *pair, *pair
+	feeder.PointTo, mainline.PointFrom = hub, hub
+	if pair.StartDateType == schema.Arrival {
+		if startDate, err := time.Parse("2006-01-02", pair.StartDate); err == nil {
+			feeder.StartDate = startDate.AddDate(0, 0, -7*extraWeeks).Format("2006-01-02")
+			feeder.SearchRange = min(pair.SearchRange+extraWeeks, 16)
+		}
+	} else {
+		mainline.SearchRange = min(pair.SearchRange+extraWeeks, 16)
+	}
+	return &feeder, &mainline
+}
+
+// fetchAllCarriers asks every requested carrier for the leg of the lane at the same time
+func (sss *ScheduleStreamingService) fetchAllCarriers(lane *schema.QueryParams, leg *schema.QueryParams) []*schema.P2PSchedule {
+	results := make([][]*schema.P2PSchedule, len(sss.queryParams.SCAC))
+	var wg sync.WaitGroup
+	for i, scac := range sss.queryParams.SCAC {
+		wg.Add(1)
+		go func(i int, scac schema.CarrierCode) {
+			defer wg.Done()
+			results[i] = sss.fetchWindow(scac, lane, leg)
+		}(i, scac)
+	}
+	wg.Wait()
+	return slices.Concat(results...)
+}
+
+// stitchSchedules joins the feeder to the earliest connecting sailing of every other carrier. The carrier's own transshipments are
+// already part of its answer, so only the sailings of different carriers are joined
+func stitchSchedules(feeders, mainlines []*schema.P2PSchedule, minConnection, maxConnection time.Duration) []*schema.P2PSchedule {
+	slices.SortFunc(mainlines, tieBreak)
+	stitched := make([]*schema.P2PSchedule, 0)
+	for _, feeder := range feeders {
+		arrival, err := time.Parse(eventLayout, feeder.Eta)
+		if err != nil {
+			continue
+		}
+		joined := make(map[string]bool)
+		for _, mainline := range mainlines {
+			if mainline.Scac == feeder.Scac || joined[mainline.Scac] {
+				continue
+			}
+			departure, err := time.Parse(eventLayout, mainline.Etd)
+			if err != nil {
+				continue
+			}
+			if connection := departure.Sub(arrival); connection < minConnection || connection > maxConnection {
+				continue
+			}
+			joined[mainline.Scac] = true
+			stitched = append(stitched, interlineSchedule(feeder, mainline))
+		}
+	}
+	return stitched
+}
+
+// interlineSchedule builds the multi carrier schedule, the legs are copied so that the carrier schedules are left untouched
+func interlineSchedule(feeder, mainline *schema.P2PSchedule) *schema.P2PSchedule {
+	legs := make([]*schema.Leg, 0, len(feeder.Legs)+len(mainline.Legs))
+	for _, schedule := range []*schema.P2PSchedule{feeder, mainline} {
+		for _, leg := range schedule.Legs {
+			legCopy := *leg
+			legCopy.Scac = schedule.Scac
+			legs = append(legs, &legCopy)
+		}
+	}
+	etd, eta := feeder.Etd, mainline.Eta
+	schedule := &schema.P2PSchedule{
+		Scac:          strings.Join([]string{feeder.Scac, mainline.Scac}, "+"),
+		PointFrom:     feeder.PointFrom,
+		PointTo:       mainline.PointTo,
+		Etd:           etd,
+		Eta:           eta,
+		EtdUTC:        feeder.EtdUTC,
+		EtaUTC:        mainline.EtaUTC,
+		TransitTime:   external.CalculateTransitTime(&etd, &eta),
+		Transshipment: true,
+		Legs:          legs,
+		Interline:     true,
+	}
+	if transitTime, ok := external.CalculateUTCTransitTime(schedule.EtdUTC, schedule.EtaUTC); ok {
+		schedule.TransitTime = transitTime
+	}
+	schedule.ScheduleID = schedule.GenerateScheduleID()
+	return schedule
+}
+
+// InterlineSchedules searches origin->hub and hub->destination across the carriers for every configured hub and streams the
+// stitched schedules of each hub as soon as both sides have answered
+func (sss *ScheduleStreamingService) InterlineSchedules(pair *schema.QueryParams) <-chan []*schema.P2PSchedule {
+	stream := make(chan []*schema.P2PSchedule)
+	go func() {
+		defer close(stream)
+		var wg sync.WaitGroup
+		for _, hub := range sss.connections.hubs {
+			if hub == pair.PointFrom || hub == pair.PointTo {
+				continue
+			}
+			wg.Add(1)
+			go func(hub string) {
+				defer wg.Done()
+				feederQuery, mainlineQuery := sss.connections.hubQueries(pair, hub)
+				var feeders, mainlines []*schema.P2PSchedule
+				var legs sync.WaitGroup
+				legs.Add(2)
+				go func() { defer legs.Done(); feeders = sss.fetchAllCarriers(pair, feederQuery) }()
+				go func() { defer legs.Done(); mainlines = sss.fetchAllCarriers(pair, mainlineQuery) }()
+				legs.Wait()
+				if len(feeders) == 0 || len(mainlines) == 0 {
+					return
+				}
+				stitched := stitchSchedules(feeders, mainlines, sss.connections.minConnection, sss.connections.maxConnection)
+				for _, schedule := range stitched {
+					if sss.queryParams.MultiPort() {
+						schedule.Lane = pair.PointFrom + "-" + pair.PointTo
+					}
+				}
+				if len(stitched) == 0 {
+					return
+				}
+				go sss.indexInterline(pair, stitched)
+				select {
+				case <-sss.ctx.Done():
+				case stream <- stitched:
+				}
+			}(hub)
+		}
+		wg.Wait()
+	}()
+	return stream
+}
+
+// interlineHub is the port where the legs of the interline schedule change carrier
+func interlineHub(schedule *schema.P2PSchedule) string {
+	for i := 1; i < len(schedule.Legs); i++ {
+		if previous := schedule.Legs[i-1]; previous.Scac != schedule.Legs[i].Scac && previous.PointTo != nil {
+			return previous.PointTo.LocationCode
+		}
+	}
+	return ""
+}
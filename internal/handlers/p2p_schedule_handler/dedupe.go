@@ -0,0 +1,71 @@
+package p2p_schedule_handler
+
+import (
+	"fmt"
+	"github.com/neckchi/schedulehub/internal/schema"
+	"slices"
+	"strings"
+)
+
+// sailingKey identifies the physical sailing no matter which carrier sells it. Legs without a vessel(truck, rail) are identified
+// by their ports instead
+func sailingKey(schedule *schema.P2PSchedule) string {
+	var key strings.Builder
+	for _, leg := range schedule.Legs {
+		reference := leg.Transportations.Reference
+		if leg.Transportations.ReferenceType != "IMO" || len(reference) != 7 {
+			reference = string(leg.Transportations.TransportType)
+			if leg.PointFrom != nil && leg.PointTo != nil {
+				reference += leg.PointFrom.LocationCode + leg.PointTo.LocationCode
+			}
+		}
+		var voyage string
+		if leg.Voyages != nil {
+			voyage = leg.Voyages.InternalVoyage
+		}
+		_, _ = fmt.Fprintf(&key, "%s|%s|%s|%s;", reference, voyage, leg.Etd, leg.Eta)
+	}
+	return key.String()
+}
+
+// DedupeScheduleList keeps the first schedule of every sailing and lists all the carriers selling it in offeredBy
+func DedupeScheduleList(schedules []*schema.P2PSchedule) []*schema.P2PSchedule {
+	deduped := make([]*schema.P2PSchedule, 0, len(schedules))
+	groups := make(map[string]*schema.P2PSchedule, len(schedules))
+	for _, schedule := range schedules {
+		key := sailingKey(schedule)
+		if kept, ok := groups[key]; ok {
+			if !slices.Contains(kept.OfferedBy, schedule.Scac) {
+				kept.OfferedBy = append(kept.OfferedBy, schedule.Scac)
+				slices.Sort(kept.OfferedBy)
+			}
+			continue
+		}
+		// copy it as the original might still be cached as part of the product
+		kept := *schedule
+		kept.OfferedBy = []string{schedule.Scac}
+		groups[key] = &kept
+		deduped = append(deduped, &kept)
+	}
+	return deduped
+}
+
+// DedupeSchedules buffers the fanned in stream as a sailing can show up from any carrier at any time
+func (sss *ScheduleStreamingService) DedupeSchedules(stream <-chan []*schema.P2PSchedule) <-chan []*schema.P2PSchedule {
+	out := make(chan []*schema.P2PSchedule)
+	go func() {
+		defer close(out)
+		buffered := make([]*schema.P2PSchedule, 0, 64)
+		for schedules := range stream {
+			buffered = append(buffered, schedules...)
+		}
+		if len(buffered) == 0 {
+			return
+		}
+		select {
+		case <-sss.ctx.Done():
+		case out <- DedupeScheduleList(buffered):
+		}
+	}()
+	return out
+}
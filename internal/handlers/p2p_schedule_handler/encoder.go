@@ -0,0 +1,201 @@
+package p2p_schedule_handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/neckchi/schedulehub/internal/schema"
+	"github.com/neckchi/schedulehub/internal/utils"
+	log "github.com/sirupsen/logrus"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+const noScheduleMessage = "No available schedules for the requested route."
+
+// ScheduleEncoder writes the schedule stream in one of the output formats negotiated with the client
+type ScheduleEncoder interface {
+	ContentType() string
+	Open(w utils.FlushWriter, origin, destination string) error
+	Schedule(w utils.FlushWriter, schedule *schema.P2PSchedule) error
+	CarrierStatus(w utils.FlushWriter, status schema.CarrierStatus) error
+	Close(w utils.FlushWriter, trailer schema.StreamTrailer) error
+}
+
+// attachment is implemented by the encoders producing a file, the response is then downloaded under that name
+type attachment interface {
+	Filename(origin, destination string) string
+}
+
+type EncoderFactory func(queryParams *schema.QueryParams) ScheduleEncoder
+
+var encoderRegistry = map[string]EncoderFactory{
+	"application/json":     func(q *schema.QueryParams) ScheduleEncoder { return &jsonEncoder{fields: q.FieldSet} },
+	"application/x-ndjson": func(q *schema.QueryParams) ScheduleEncoder { return &ndjsonEncoder{fields: q.FieldSet} },
+	"text/event-stream":    func(q *schema.QueryParams) ScheduleEncoder { return &eventStreamEncoder{fields: q.FieldSet} },
+	"text/csv":             func(q *schema.QueryParams) ScheduleEncoder { return newCSVEncoder(q.Layout) },
+	xlsxContentType:        func(q *schema.QueryParams) ScheduleEncoder { return newXLSXEncoder(q.Layout) },
+}
+
+// formatEncoders are picked by the format parameter, dcsa has no media type of its own
+var formatEncoders = map[string]EncoderFactory{
+	"json":   encoderRegistry["application/json"],
+	"ndjson": encoderRegistry["application/x-ndjson"],
+	"csv":    encoderRegistry["text/csv"],
+	"xlsx":   encoderRegistry[xlsxContentType],
+	"dcsa":   func(*schema.QueryParams) ScheduleEncoder { return &dcsaEncoder{} },
+}
+
+// NegotiateEncoder picks the encoder of the format parameter or else of the first media type of the Accept header we support, the
+// JSON envelope is the default
+func NegotiateEncoder(accept string, queryParams *schema.QueryParams) ScheduleEncoder {
+	if factory, ok := formatEncoders[queryParams.Format]; ok {
+		return factory(queryParams)
+	}
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+		if factory, ok := encoderRegistry[mediaType]; ok {
+			return factory(queryParams)
+		}
+	}
+	return &jsonEncoder{fields: queryParams.FieldSet}
+}
+
+// jsonEncoder keeps the original envelope {"origin","destination","schedules":[...]}, fields prunes the schedules
+type jsonEncoder struct {
+	written int
+	fields  schema.FieldSet
+}
+
+func (e *jsonEncoder) ContentType() string { return "application/json" }
+
+func (e *jsonEncoder) Open(w utils.FlushWriter, origin, destination string) error {
+	originJSON, _ := json.Marshal(origin)
+	destinationJSON, _ := json.Marshal(destination)
+	_, err := fmt.Fprintf(w, `{"origin":%s,"destination":%s,"schedules":[`, originJSON, destinationJSON)
+	return err
+}
+
+func (e *jsonEncoder) Schedule(w utils.FlushWriter, schedule *schema.P2PSchedule) error {
+	scheduleJSON, err := json.Marshal(e.fields.Apply(schedule))
+	if err != nil {
+		return err
+	}
+	if e.written > 0 {
+		scheduleJSON = append([]byte(","), scheduleJSON...)
+	}
+	e.written++
+	_, err = w.Write(scheduleJSON)
+	return err
+}
+
+// CarrierStatus has no place in the envelope
+func (e *jsonEncoder) CarrierStatus(utils.FlushWriter, schema.CarrierStatus) error { return nil }
+
+func (e *jsonEncoder) Close(w utils.FlushWriter, trailer schema.StreamTrailer) error {
+	var err error
+	switch {
+	case trailer.Message != "":
+		message, _ := json.Marshal(trailer.Message)
+		_, err = fmt.Fprintf(w, `],"message":%s}`, message)
+	case trailer.NextPageToken != "":
+		_, err = fmt.Fprintf(w, `],"nextPageToken":"%s"}`, trailer.NextPageToken)
+	default:
+		_, err = w.Write([]byte(`]}`))
+	}
+	return err
+}
+
+// ndjsonEncoder writes one schedule per line and a trailer line with done=true, every line can be parsed on its own
+type ndjsonEncoder struct {
+	fields schema.FieldSet
+}
+
+func (e *ndjsonEncoder) ContentType() string { return "application/x-ndjson" }
+
+func (e *ndjsonEncoder) Open(utils.FlushWriter, string, string) error { return nil }
+
+func (e *ndjsonEncoder) Schedule(w utils.FlushWriter, schedule *schema.P2PSchedule) error {
+	return json.NewEncoder(w).Encode(e.fields.Apply(schedule))
+}
+
+func (e *ndjsonEncoder) CarrierStatus(utils.FlushWriter, schema.CarrierStatus) error { return nil }
+
+func (e *ndjsonEncoder) Close(w utils.FlushWriter, trailer schema.StreamTrailer) error {
+	return json.NewEncoder(w).Encode(&trailer)
+}
+
+// eventStreamEncoder sends schedule, carrier-status and done events
+type eventStreamEncoder struct {
+	fields schema.FieldSet
+}
+
+func (e *eventStreamEncoder) ContentType() string { return "text/event-stream" }
+
+func (e *eventStreamEncoder) Open(utils.FlushWriter, string, string) error { return nil }
+
+func (e *eventStreamEncoder) event(w utils.FlushWriter, name string, data interface{}) error {
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, dataJSON)
+	return err
+}
+
+func (e *eventStreamEncoder) Schedule(w utils.FlushWriter, schedule *schema.P2PSchedule) error {
+	return e.event(w, "schedule", e.fields.Apply(schedule))
+}
+
+func (e *eventStreamEncoder) CarrierStatus(w utils.FlushWriter, status schema.CarrierStatus) error {
+	return e.event(w, "carrier-status", status)
+}
+
+func (e *eventStreamEncoder) Close(w utils.FlushWriter, trailer schema.StreamTrailer) error {
+	return e.event(w, "done", trailer)
+}
+
+// dcsaEncoder writes the array of DCSA Commercial Schedules point to point routes. A route not matching the spec is left out and
+// counted in the Dcsa-Dropped trailer
+type dcsaEncoder struct {
+	written int
+	dropped int
+}
+
+func (e *dcsaEncoder) ContentType() string { return "application/json" }
+
+func (e *dcsaEncoder) Open(w utils.FlushWriter, _, _ string) error {
+	w.Header().Set("Trailer", schema.DCSADroppedTrailer)
+	_, err := w.Write([]byte("["))
+	return err
+}
+
+func (e *dcsaEncoder) Schedule(w utils.FlushWriter, schedule *schema.P2PSchedule) error {
+	route := schema.NewDCSAPointToPoint(schedule)
+	if err := schema.DCSAValidate.Struct(&route); err != nil {
+		log.Errorf("Schedule %s does not match the DCSA point to point shape: %v", schedule.ScheduleID, err)
+		e.dropped++
+		return nil
+	}
+	routeJSON, err := json.Marshal(&route)
+	if err != nil {
+		return err
+	}
+	if e.written > 0 {
+		routeJSON = append([]byte(","), routeJSON...)
+	}
+	e.written++
+	_, err = w.Write(routeJSON)
+	return err
+}
+
+func (e *dcsaEncoder) CarrierStatus(utils.FlushWriter, schema.CarrierStatus) error { return nil }
+
+func (e *dcsaEncoder) Close(w utils.FlushWriter, _ schema.StreamTrailer) error {
+	_, err := w.Write([]byte("]"))
+	w.Header().Set(schema.DCSADroppedTrailer, strconv.Itoa(e.dropped))
+	return err
+}
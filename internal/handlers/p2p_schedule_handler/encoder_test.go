@@ -0,0 +1,57 @@
+package p2p_schedule_handler
+
+import (
+	"encoding/json"
+	"github.com/neckchi/schedulehub/internal/schema"
+	"github.com/neckchi/schedulehub/internal/utils"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func dcsaTestSchedule(zone string) *schema.P2PSchedule {
+	return &schema.P2PSchedule{
+		ScheduleID: "test", Scac: "MAEU", PointFrom: "CNSHA", PointTo: "DEHAM", Etd: "2024-03-10T08:00:00", Eta: "2024-04-12T06:00:00",
+		Legs: []*schema.Leg{{
+			PointFrom:       &schema.PointBase{LocationCode: "CNSHA", TimeZone: zone},
+			PointTo:         &schema.PointBase{LocationCode: "DEHAM", TimeZone: zone},
+			Etd:             "2024-03-10T08:00:00",
+			Eta:             "2024-04-12T06:00:00",
+			Transportations: schema.Transportation{TransportType: schema.Vessel, TransportName: "EVER ACE"},
+			Voyages:         &schema.Voyage{InternalVoyage: "001W"},
+		}},
+	}
+}
+
+func TestDCSAEncoderReportsDroppedRoutes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fw := utils.NewFlushWriter(w)
+		encoder := &dcsaEncoder{}
+		_ = encoder.Open(fw, "CNSHA", "DEHAM")
+		_ = encoder.Schedule(fw, dcsaTestSchedule("+08:00"))
+		_ = encoder.Schedule(fw, dcsaTestSchedule("")) // no zone and no utc time, the date-times carry no offset
+		_ = encoder.Close(fw, schema.StreamTrailer{Done: true, Total: 2})
+	}))
+	defer server.Close()
+
+	rsp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rsp.Body.Close()
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var routes []schema.DCSAPointToPoint
+	if err := json.Unmarshal(body, &routes); err != nil {
+		t.Fatalf("body %s is not an array of routes: %v", body, err)
+	}
+	if len(routes) != 1 {
+		t.Errorf("got %d routes, want 1", len(routes))
+	}
+	if got := rsp.Trailer.Get(schema.DCSADroppedTrailer); got != "1" {
+		t.Errorf("%s trailer = %q, want 1", schema.DCSADroppedTrailer, got)
+	}
+}
@@ -0,0 +1,268 @@
+package p2p_schedule_handler
+
+import (
+	"archive/zip"
+	"compress/flate"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"github.com/neckchi/schedulehub/internal/schema"
+	"github.com/neckchi/schedulehub/internal/utils"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	legsLayout      = "legs"
+	xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+var scheduleColumns = []string{
+	"scheduleId", "scac", "pointFrom", "pointTo", "etd", "eta", "transitTime", "transshipments", "transshipmentPorts",
+	"firstVessel", "firstVesselIMO", "firstVoyage", "firstService", "cyCutoff", "docCutoff", "vgmCutoff",
+	"lastVessel", "lastVesselIMO", "lastVoyage", "lastService", "lane", "interline",
+}
+
+var legColumns = []string{
+	"scheduleId", "scac", "leg", "pointFrom", "pointTo", "etd", "eta", "transitTime", "transportType", "transportName",
+	"referenceType", "reference", "voyage", "serviceCode", "serviceName", "cyCutoff", "docCutoff", "vgmCutoff", "operator",
+}
+
+// numericColumns are written as numbers in the spreadsheet so that they can be summed and sorted
+var numericColumns = map[string]bool{"transitTime": true, "transshipments": true, "leg": true}
+
+func exportColumns(layout string) []string {
+	if layout == legsLayout {
+		return legColumns
+	}
+	return scheduleColumns
+}
+
+// legFields flattens the vessel, voyage and service of the leg
+func legFields(leg *schema.Leg) (vessel, imo, voyage, service string) {
+	if leg == nil {
+		return
+	}
+	vessel, imo = leg.Transportations.TransportName, leg.Transportations.Reference
+	if leg.Voyages != nil {
+		voyage = leg.Voyages.InternalVoyage
+	}
+	if leg.Services != nil {
+		service = leg.Services.ServiceCode
+	}
+	return
+}
+
+func legCutoffs(leg *schema.Leg) (cy, doc, vgm string) {
+	if leg == nil || leg.Cutoffs == nil {
+		return
+	}
+	return leg.Cutoffs.CyCutoffDate, leg.Cutoffs.DocCutoffDate, leg.Cutoffs.VgmCutoffDate
+}
+
+// exportRows flattens the schedule into one row, or one row per leg with the legs layout
+func exportRows(schedule *schema.P2PSchedule, layout string) [][]string {
+	if layout == legsLayout {
+		rows := make([][]string, 0, len(schedule.Legs))
+		for i, leg := range schedule.Legs {
+			var from, to, voyage, serviceCode, serviceName string
+			if leg.PointFrom != nil {
+				from = leg.PointFrom.LocationCode
+			}
+			if leg.PointTo != nil {
+				to = leg.PointTo.LocationCode
+			}
+			if leg.Voyages != nil {
+				voyage = leg.Voyages.InternalVoyage
+			}
+			if leg.Services != nil {
+				serviceCode, serviceName = leg.Services.ServiceCode, leg.Services.ServiceName
+			}
+			cy, doc, vgm := legCutoffs(leg)
+			rows = append(rows, []string{
+				schedule.ScheduleID, schedule.Scac, strconv.Itoa(i + 1), from, to, leg.Etd, leg.Eta, strconv.Itoa(leg.TransitTime),
+				string(leg.Transportations.TransportType), leg.Transportations.TransportName, leg.Transportations.ReferenceType,
+				leg.Transportations.Reference, voyage, serviceCode, serviceName, cy, doc, vgm, leg.Scac,
+			})
+		}
+		return rows
+	}
+	var first, last *schema.Leg
+	if len(schedule.Legs) > 0 {
+		first, last = schedule.Legs[0], schedule.Legs[len(schedule.Legs)-1]
+	}
+	firstVessel, firstIMO, firstVoyage, firstService := legFields(first)
+	lastVessel, lastIMO, lastVoyage, lastService := legFields(last)
+	cy, doc, vgm := legCutoffs(first)
+	interline := ""
+	if schedule.Interline {
+		interline = "true"
+	}
+	return [][]string{{
+		schedule.ScheduleID, schedule.Scac, schedule.PointFrom, schedule.PointTo, schedule.Etd, schedule.Eta,
+		strconv.Itoa(schedule.TransitTime), strconv.Itoa(transshipments(schedule)), strings.Join(transshipmentPorts(schedule), " "),
+		firstVessel, firstIMO, firstVoyage, firstService, cy, doc, vgm, lastVessel, lastIMO, lastVoyage, lastService,
+		schedule.Lane, interline,
+	}}
+}
+
+// csvEncoder writes the header and then the rows of every schedule as it comes in
+type csvEncoder struct {
+	layout string
+	writer *csv.Writer
+}
+
+func newCSVEncoder(layout string) *csvEncoder {
+	return &csvEncoder{layout: layout}
+}
+
+func (e *csvEncoder) ContentType() string { return "text/csv; charset=utf-8" }
+
+func (e *csvEncoder) Filename(origin, destination string) string {
+	return fmt.Sprintf("p2p_schedules_%s_%s.csv", origin, destination)
+}
+
+func (e *csvEncoder) Open(w utils.FlushWriter, _, _ string) error {
+	e.writer = csv.NewWriter(w)
+	return e.flush(exportColumns(e.layout))
+}
+
+func (e *csvEncoder) flush(rows ...[]string) error {
+	for _, row := range rows {
+		if err := e.writer.Write(row); err != nil {
+			return err
+		}
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func (e *csvEncoder) Schedule(_ utils.FlushWriter, schedule *schema.P2PSchedule) error {
+	return e.flush(exportRows(schedule, e.layout)...)
+}
+
+func (e *csvEncoder) CarrierStatus(utils.FlushWriter, schema.CarrierStatus) error { return nil }
+
+func (e *csvEncoder) Close(utils.FlushWriter, schema.StreamTrailer) error { return nil }
+
+// the static parts of a workbook with a single sheet, the cells use inline strings so no shared string table is needed
+var xlsxParts = []struct{ name, content string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Schedules" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// xlsxEncoder streams the sheet inside the zip archive, the rows are flushed to the client as the schedules come in
+type xlsxEncoder struct {
+	layout  string
+	archive *zip.Writer
+	sheet   io.Writer
+	deflate *flate.Writer // compressor of the sheet, the part created last
+	row     int
+}
+
+func newXLSXEncoder(layout string) *xlsxEncoder {
+	return &xlsxEncoder{layout: layout}
+}
+
+func (e *xlsxEncoder) ContentType() string { return xlsxContentType }
+
+func (e *xlsxEncoder) Filename(origin, destination string) string {
+	return fmt.Sprintf("p2p_schedules_%s_%s.xlsx", origin, destination)
+}
+
+func (e *xlsxEncoder) Open(w utils.FlushWriter, _, _ string) error {
+	e.archive = zip.NewWriter(w)
+	// the compressor is kept so every batch of rows can be pushed out of it, zip.Writer.Flush alone leaves them in deflate
+	e.archive.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+		deflate, err := flate.NewWriter(out, flate.DefaultCompression)
+		e.deflate = deflate
+		return deflate, err
+	})
+	for _, part := range xlsxParts {
+		partWriter, err := e.archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(partWriter, part.content); err != nil {
+			return err
+		}
+	}
+	sheet, err := e.archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	e.sheet = sheet
+	if _, err := io.WriteString(e.sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return err
+	}
+	return e.writeRows(nil, exportColumns(e.layout))
+}
+
+// columnName turns the column index into the spreadsheet letters(0 -> A, 26 -> AA)
+func columnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
+
+func (e *xlsxEncoder) writeRows(columns []string, rows ...[]string) error {
+	var sheetXML strings.Builder
+	for _, row := range rows {
+		e.row++
+		_, _ = fmt.Fprintf(&sheetXML, `<row r="%d">`, e.row)
+		for i, value := range row {
+			if value == "" {
+				continue
+			}
+			ref := columnName(i) + strconv.Itoa(e.row)
+			if columns != nil && numericColumns[columns[i]] {
+				_, _ = fmt.Fprintf(&sheetXML, `<c r="%s"><v>%s</v></c>`, ref, value)
+				continue
+			}
+			_, _ = fmt.Fprintf(&sheetXML, `<c r="%s" t="inlineStr"><is><t>`, ref)
+			_ = xml.EscapeText(&sheetXML, []byte(value))
+			sheetXML.WriteString(`</t></is></c>`)
+		}
+		sheetXML.WriteString(`</row>`)
+	}
+	if _, err := io.WriteString(e.sheet, sheetXML.String()); err != nil {
+		return err
+	}
+	if err := e.deflate.Flush(); err != nil {
+		return err
+	}
+	return e.archive.Flush()
+}
+
+func (e *xlsxEncoder) Schedule(_ utils.FlushWriter, schedule *schema.P2PSchedule) error {
+	return e.writeRows(exportColumns(e.layout), exportRows(schedule, e.layout)...)
+}
+
+func (e *xlsxEncoder) CarrierStatus(utils.FlushWriter, schema.CarrierStatus) error { return nil }
+
+func (e *xlsxEncoder) Close(utils.FlushWriter, schema.StreamTrailer) error {
+	if _, err := io.WriteString(e.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return e.archive.Close()
+}
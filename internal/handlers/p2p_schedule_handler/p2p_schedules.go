@@ -2,6 +2,7 @@ package p2p_schedule_handler
 
 import (
 	"context"
+	"fmt"
 	"github.com/neckchi/schedulehub/external/carrier_p2p_schedule"
 	"github.com/neckchi/schedulehub/internal/database"
 	"github.com/neckchi/schedulehub/internal/exceptions"
@@ -13,6 +14,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"net/http"
 	"runtime"
+	"strings"
 )
 
 func btoMb(b uint64) uint64 {
@@ -38,6 +40,7 @@ type P2PScheduleService struct {
 	env    *env.Manager
 	ps     *carrier_p2p_schedule.P2PScheduleServiceFactory
 	redis  database.RedisRepository
+	lanes  *LaneHistory
 }
 
 func NewP2PScheduleService(
@@ -45,20 +48,47 @@ func NewP2PScheduleService(
 	env *env.Manager,
 	ps *carrier_p2p_schedule.P2PScheduleServiceFactory,
 	redis database.RedisRepository,
+	lanes *LaneHistory,
 ) *P2PScheduleService {
-	return &P2PScheduleService{client, env, ps, redis}
+	return &P2PScheduleService{client, env, ps, redis, lanes}
 }
 
 func P2PScheduleHandler(s *P2PScheduleService) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		fw := utils.NewFlushWriter(w)
 		queryParams, _ := r.Context().Value(middleware.P2PQueryParamsKey).(schema.QueryParams)
+		s.lanes.Record(&queryParams)
 		ctx, cancel := context.WithCancel(r.Context())
 		defer cancel() // Ensure cancellation when function exits
-		service := NewScheduleStreamingService(ctx, s.client, s.env, s.ps, &queryParams)
-		fanOutscheduleChannels := service.FanOutScheduleChannels()
-		fannedInStream := service.FanIn(fanOutscheduleChannels...)
-		service.StreamResponse(fw, fannedInStream)
+		settings, _ := r.Context().Value(middleware.ScheduleConfig).(map[string]interface{})
+		encoder := NegotiateEncoder(r.Header.Get("Accept"), &queryParams)
+		w.Header().Set("Content-Type", encoder.ContentType())
+		if file, ok := encoder.(attachment); ok {
+			filename := file.Filename(strings.Join(queryParams.OriginPorts(), "-"), strings.Join(queryParams.DestinationPorts(), "-"))
+			w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		}
+		w.Header().Add("Vary", "Accept")
+		if encoder.ContentType() == "text/event-stream" {
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("X-Accel-Buffering", "no") // keep the proxies from buffering the events
+		}
+		service := NewScheduleStreamingService(ctx, s.client, s.env, s.ps, s.redis, &queryParams)
+		// only the event stream writes the carrier status
+		if encoder.ContentType() == "text/event-stream" && !queryParams.Paginated() {
+			service.EnableCarrierStatus()
+		}
+		stream, finish := service.Pipeline(settings)
+		if service.Buffered() {
+			buffer := utils.NewResponseBuffer(w)
+			service.StreamResponse(utils.NewFlushWriter(buffer), stream, encoder)
+			finish()
+			if ctx.Err() == nil {
+				utils.WriteWithETag(w, r, buffer.Bytes())
+			}
+		} else {
+			service.StreamResponse(fw, stream, encoder)
+			finish()
+		}
 		go func() {
 			err := s.redis.Set(r.URL.String())
 			if err != nil {
@@ -0,0 +1,42 @@
+package p2p_schedule_handler
+
+import (
+	"cmp"
+	"github.com/neckchi/schedulehub/internal/schema"
+	"time"
+)
+
+const defaultPageLimit = 100
+
+// Paginated requests give up the progressive streaming. The whole product is served from the result cache(or fetched and cached
+// at the first page) and sorted deterministically, so every page of the same query cuts the same list at the same position.
+
+// ProductSchedules returns the full normalized product of the query
+func (sss *ScheduleStreamingService) ProductSchedules(expiry time.Duration) []*schema.P2PSchedule {
+	if product, hit := sss.CachedProduct(); hit {
+		return product.Schedules
+	}
+	collected := make([]*schema.P2PSchedule, 0, 64)
+	for schedules := range sss.FanIn(sss.FanOutScheduleChannels()...) {
+		collected = append(collected, schedules...)
+	}
+	// the fan in closes early when the context ends, the product is cut short then
+	if sss.ctx.Err() == nil {
+		sss.CacheProduct(collected, expiry)
+	}
+	return collected
+}
+
+// Page dedupes and sorts the product and streams the requested page. The token of the following page is written at the end of the response
+func (sss *ScheduleStreamingService) Page(schedules []*schema.P2PSchedule, weights map[string]interface{}) <-chan []*schema.P2PSchedule {
+	if sss.queryParams.Dedupe {
+		schedules = DedupeScheduleList(schedules)
+	}
+	SortScheduleList(schedules, sss.queryParams.SortBy, weights)
+	start := min(sss.queryParams.Offset, len(schedules))
+	end := min(start+cmp.Or(sss.queryParams.Limit, defaultPageLimit), len(schedules))
+	if end < len(schedules) {
+		sss.nextPageToken = sss.queryParams.EncodePageToken(end)
+	}
+	return sss.ReplayProduct(&schema.Product{Schedules: schedules[start:end]})
+}
@@ -0,0 +1,75 @@
+package p2p_schedule_handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/neckchi/schedulehub/internal/schema"
+	"slices"
+	"testing"
+)
+
+// TestPage walks the pages of one query with the token of the page before, the product comes in a different order every time
+func TestPage(t *testing.T) {
+	product := func(reversed bool) []*schema.P2PSchedule {
+		schedules := make([]*schema.P2PSchedule, 5)
+		for i := range schedules {
+			schedules[i] = &schema.P2PSchedule{ScheduleID: fmt.Sprint(i), Scac: "MAEU", Etd: fmt.Sprintf("2024-03-1%dT08:00:00", i)}
+		}
+		if reversed {
+			slices.Reverse(schedules)
+		}
+		return schedules
+	}
+	tests := []struct {
+		limit int
+		pages [][]string
+	}{
+		{limit: 2, pages: [][]string{{"0", "1"}, {"2", "3"}, {"4"}}},
+		{limit: 5, pages: [][]string{{"0", "1", "2", "3", "4"}}},
+		{limit: 10, pages: [][]string{{"0", "1", "2", "3", "4"}}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("limit %d", tt.limit), func(t *testing.T) {
+			query := &schema.QueryParams{PointFrom: "CNSHA", PointTo: "DEHAM", StartDate: "2024-03-10", SearchRange: 4, Limit: tt.limit}
+			for page, want := range tt.pages {
+				offset, err := query.DecodePageToken()
+				if err != nil {
+					t.Fatalf("page %d: %v", page, err)
+				}
+				query.Offset = offset
+				sss := &ScheduleStreamingService{ctx: context.Background(), queryParams: query}
+				var got []string
+				for schedules := range sss.Page(product(page%2 == 1), nil) {
+					for _, schedule := range schedules {
+						got = append(got, schedule.ScheduleID)
+					}
+				}
+				if !slices.Equal(got, want) {
+					t.Errorf("page %d = %v, want %v", page, got, want)
+				}
+				last := page == len(tt.pages)-1
+				if last != (sss.nextPageToken == "") {
+					t.Fatalf("page %d has next page token %q", page, sss.nextPageToken)
+				}
+				query.PageToken = sss.nextPageToken
+			}
+		})
+	}
+}
+
+func TestPageOutOfRange(t *testing.T) {
+	query := &schema.QueryParams{PointFrom: "CNSHA", PointTo: "DEHAM", Limit: 2}
+	query.PageToken = query.EncodePageToken(10)
+	offset, err := query.DecodePageToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	query.Offset = offset
+	sss := &ScheduleStreamingService{ctx: context.Background(), queryParams: query}
+	for schedules := range sss.Page([]*schema.P2PSchedule{{ScheduleID: "0", Etd: "2024-03-10T08:00:00"}}, nil) {
+		t.Errorf("got %d schedules past the end of the product", len(schedules))
+	}
+	if sss.nextPageToken != "" {
+		t.Errorf("next page token %q past the end of the product", sss.nextPageToken)
+	}
+}
@@ -0,0 +1,112 @@
+package p2p_schedule_handler
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/neckchi/schedulehub/internal/exceptions"
+	"github.com/neckchi/schedulehub/internal/middleware"
+	"github.com/neckchi/schedulehub/internal/schema"
+	"github.com/neckchi/schedulehub/internal/utils"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const eventLayout = "2006-01-02T15:04:05"
+
+// planSchedule checks the schedule against the deadline(end of the arriveBy day) and the day the cargo gets ready. The binding
+// cutoff is the earliest cutoff of the first leg, a schedule without cutoff is bound by its departure
+func planSchedule(schedule *schema.P2PSchedule, deadline time.Time, cargoReady time.Time) *schema.PlannedSchedule {
+	planned := &schema.PlannedSchedule{P2PSchedule: schedule, LastCutoff: cmp.Or(firstCutoff(schedule), schedule.Etd)}
+	eta, _ := time.Parse(eventLayout, schedule.Eta)
+	planned.SlackHours = int(deadline.Sub(eta).Hours())
+	if eta.After(deadline) {
+		planned.Reasons = append(planned.Reasons, fmt.Sprintf("arrives %s after the deadline", eta.Format(eventLayout)))
+	}
+	if !cargoReady.IsZero() {
+		cutoff, _ := time.Parse(eventLayout, planned.LastCutoff)
+		if cutoff.Before(cargoReady) {
+			planned.Reasons = append(planned.Reasons, fmt.Sprintf("cutoff %s is before the cargo is ready", planned.LastCutoff))
+		}
+	}
+	planned.Feasible = len(planned.Reasons) == 0
+	return planned
+}
+
+// rankPlannedSchedules puts the feasible schedules first, the least slack(latest departure still arriving in time) on top, then the
+// latest cutoff and the shortest transit. The infeasible ones follow by arrival
+func rankPlannedSchedules(planned []*schema.PlannedSchedule) {
+	slices.SortStableFunc(planned, func(a, b *schema.PlannedSchedule) int {
+		if a.Feasible != b.Feasible {
+			if a.Feasible {
+				return -1
+			}
+			return 1
+		}
+		if !a.Feasible {
+			return cmp.Or(cmp.Compare(a.Eta, b.Eta), tieBreak(a.P2PSchedule, b.P2PSchedule))
+		}
+		return cmp.Or(
+			cmp.Compare(a.SlackHours, b.SlackHours),
+			cmp.Compare(b.LastCutoff, a.LastCutoff),
+			cmp.Compare(a.TransitTime, b.TransitTime),
+			tieBreak(a.P2PSchedule, b.P2PSchedule),
+		)
+	})
+}
+
+func PlannerHandler(s *P2PScheduleService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		plannerParams, _ := r.Context().Value(middleware.P2PPlannerQueryParamsKey).(schema.PlannerQueryParams)
+		settings, _ := r.Context().Value(middleware.ScheduleConfig).(map[string]interface{})
+		queryParams := plannerParams.SearchQuery(utils.IntSetting(utils.MapSetting(settings, "planner"), "maxTransitTime", 42))
+		s.lanes.Record(&queryParams)
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel() // Ensure cancellation when function exits
+
+		arriveBy, _ := time.Parse("2006-01-02", plannerParams.ArriveBy)
+		deadline := arriveBy.Add(24*time.Hour - time.Second)
+		var cargoReady time.Time
+		if plannerParams.CargoReadyDate != "" {
+			cargoReady, _ = time.Parse("2006-01-02", plannerParams.CargoReadyDate)
+		}
+
+		service := NewScheduleStreamingService(ctx, s.client, s.env, s.ps, s.redis, &queryParams)
+		stream, finish := service.Pipeline(settings)
+		planned := make([]*schema.PlannedSchedule, 0, 64)
+		for schedules := range stream {
+			for _, schedule := range schedules {
+				planned = append(planned, planSchedule(schedule, deadline, cargoReady))
+			}
+		}
+		finish()
+		rankPlannedSchedules(planned)
+
+		result := schema.PlannerResult{
+			Origin:         strings.Join(queryParams.OriginPorts(), ","),
+			Destination:    strings.Join(queryParams.DestinationPorts(), ","),
+			ArriveBy:       plannerParams.ArriveBy,
+			CargoReadyDate: plannerParams.CargoReadyDate,
+			Schedules:      planned,
+		}
+		if len(planned) == 0 || !planned[0].Feasible {
+			result.Message = "No schedule arrives by the requested date."
+		}
+		rsp, err := json.Marshal(&result)
+		if err != nil {
+			exceptions.InternalErrorHandler(w, err)
+			return
+		}
+		utils.WriteWithETag(w, r, rsp)
+		go func() {
+			err := s.redis.Set(r.URL.String())
+			if err != nil {
+				log.Error(err)
+			}
+		}()
+	})
+}
@@ -3,6 +3,7 @@ package p2p_schedule_handler
 import (
 	"github.com/neckchi/schedulehub/internal/schema"
 	"slices"
+	"strings"
 )
 
 type ScheduleFilterOption func(*schema.P2PSchedule, *schema.QueryParams) bool
@@ -50,6 +51,105 @@ func WithService() ScheduleFilterOption {
 	}
 }
 
+func WithServiceName() ScheduleFilterOption {
+	return func(schedule *schema.P2PSchedule, query *schema.QueryParams) bool {
+		if query.ServiceName != "" {
+			return slices.ContainsFunc(schedule.Legs, func(leg *schema.Leg) bool {
+				return leg.Services != nil && containsFold(leg.Services.ServiceName, query.ServiceName)
+			})
+		}
+		return true
+	}
+}
+
+func WithVesselName() ScheduleFilterOption {
+	return func(schedule *schema.P2PSchedule, query *schema.QueryParams) bool {
+		if query.VesselName != "" {
+			return slices.ContainsFunc(schedule.Legs, func(leg *schema.Leg) bool {
+				return containsFold(leg.Transportations.TransportName, query.VesselName)
+			})
+		}
+		return true
+	}
+}
+
+func WithMaxTransitTime() ScheduleFilterOption {
+	return func(schedule *schema.P2PSchedule, query *schema.QueryParams) bool {
+		if query.MaxTransit != nil {
+			return schedule.TransitTime <= *query.MaxTransit
+		}
+		return true
+	}
+}
+
+func WithMaxTransshipments() ScheduleFilterOption {
+	return func(schedule *schema.P2PSchedule, query *schema.QueryParams) bool {
+		if query.MaxTSP != nil {
+			return transshipments(schedule) <= *query.MaxTSP
+		}
+		return true
+	}
+}
+
+// WithExcludedTSP drops the schedules calling at one of the excluded ports, a two letter code excludes the whole country
+func WithExcludedTSP() ScheduleFilterOption {
+	return func(schedule *schema.P2PSchedule, query *schema.QueryParams) bool {
+		if len(query.ExcludeTSP) > 0 {
+			return !slices.ContainsFunc(transshipmentPorts(schedule), func(port string) bool {
+				return slices.ContainsFunc(query.ExcludeTSP, func(excluded string) bool {
+					return strings.HasPrefix(port, excluded)
+				})
+			})
+		}
+		return true
+	}
+}
+
+// WithTransportModes keeps the schedules whose legs all use one of the allowed modes
+func WithTransportModes() ScheduleFilterOption {
+	return func(schedule *schema.P2PSchedule, query *schema.QueryParams) bool {
+		if len(query.Modes) > 0 {
+			return !slices.ContainsFunc(schedule.Legs, func(leg *schema.Leg) bool {
+				return !slices.Contains(query.Modes, leg.Transportations.TransportType)
+			})
+		}
+		return true
+	}
+}
+
+// WithCutoffAfter keeps the schedules whose earliest cutoff(or departure when there is none) is after the requested time
+func WithCutoffAfter() ScheduleFilterOption {
+	return func(schedule *schema.P2PSchedule, query *schema.QueryParams) bool {
+		if query.CutoffAfter != "" {
+			cutoff := firstCutoff(schedule)
+			if cutoff == "" {
+				cutoff = schedule.Etd
+			}
+			// event dates share the same layout so a date only value compares as the start of the day
+			return cutoff > query.CutoffAfter
+		}
+		return true
+	}
+}
+
+// transshipmentPorts lists the ports where the cargo changes legs, one per leg after the first. The end of the previous leg only
+// stands in when the leg has no start point
+func transshipmentPorts(schedule *schema.P2PSchedule) []string {
+	ports := make([]string, 0, len(schedule.Legs))
+	for i := 1; i < len(schedule.Legs); i++ {
+		if leg := schedule.Legs[i]; leg.PointFrom != nil {
+			ports = append(ports, leg.PointFrom.LocationCode)
+		} else if previous := schedule.Legs[i-1]; previous.PointTo != nil {
+			ports = append(ports, previous.PointTo.LocationCode)
+		}
+	}
+	return ports
+}
+
+func containsFold(value, substr string) bool {
+	return strings.Contains(strings.ToUpper(value), strings.ToUpper(substr))
+}
+
 func ScheduleFilters(opts ...ScheduleFilterOption) ScheduleFilterOption {
 	return func(schedule *schema.P2PSchedule, query *schema.QueryParams) bool {
 		result := true
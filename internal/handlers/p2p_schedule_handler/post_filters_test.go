@@ -0,0 +1,119 @@
+package p2p_schedule_handler
+
+import (
+	"github.com/neckchi/schedulehub/internal/schema"
+	"slices"
+	"testing"
+)
+
+func directSchedule() *schema.P2PSchedule {
+	return &schema.P2PSchedule{
+		Scac: "MAEU", PointFrom: "CNSHA", PointTo: "DEHAM", Etd: "2024-03-10T08:00:00", Eta: "2024-04-12T06:00:00", TransitTime: 33,
+		Legs: []*schema.Leg{{
+			PointFrom:       &schema.PointBase{LocationCode: "CNSHA"},
+			PointTo:         &schema.PointBase{LocationCode: "DEHAM"},
+			Cutoffs:         &schema.Cutoff{CyCutoffDate: "2024-03-08T12:00:00", DocCutoffDate: "2024-03-07T12:00:00"},
+			Transportations: schema.Transportation{TransportType: schema.Vessel, TransportName: "EVER ACE", Reference: "9893890"},
+			Services:        &schema.Service{ServiceCode: "AE7", ServiceName: "Asia Europe 7"},
+		}},
+	}
+}
+
+func transshipmentSchedule() *schema.P2PSchedule {
+	return &schema.P2PSchedule{
+		Scac: "MSCU", PointFrom: "CNSHA", PointTo: "DEHAM", Etd: "2024-03-11T08:00:00", Eta: "2024-04-20T06:00:00", TransitTime: 40,
+		Transshipment: true,
+		Legs: []*schema.Leg{
+			{
+				PointFrom:       &schema.PointBase{LocationCode: "CNSHA"},
+				PointTo:         &schema.PointBase{LocationCode: "SGSIN"},
+				Transportations: schema.Transportation{TransportType: schema.Feeder, TransportName: "NORDIC LIGHT", Reference: "1234567"},
+				Services:        &schema.Service{ServiceCode: "FE1", ServiceName: "Far East Feeder"},
+			},
+			{
+				PointFrom:       &schema.PointBase{LocationCode: "SGSIN"},
+				PointTo:         &schema.PointBase{LocationCode: "DEHAM"},
+				Transportations: schema.Transportation{TransportType: schema.Vessel, TransportName: "MSC ANNA", Reference: "9876543"},
+			},
+		},
+	}
+}
+
+func TestScheduleFilters(t *testing.T) {
+	filter := ScheduleFilters(
+		WithDirectOnly(),
+		WithTSP(),
+		WithVesselIMO(),
+		WithService(),
+		WithServiceName(),
+		WithVesselName(),
+		WithMaxTransitTime(),
+		WithMaxTransshipments(),
+		WithExcludedTSP(),
+		WithTransportModes(),
+		WithCutoffAfter(),
+	)
+	zero, thirtyFive := 0, 35
+	tests := []struct {
+		name              string
+		query             schema.QueryParams
+		keepDirect        bool
+		keepTransshipment bool
+	}{
+		{name: "no filter", keepDirect: true, keepTransshipment: true},
+		{name: "direct only", query: schema.QueryParams{DirectOnly: true}, keepDirect: true},
+		{name: "transshipment port", query: schema.QueryParams{TSP: "SGSIN"}, keepTransshipment: true},
+		{name: "vessel imo of the second leg", query: schema.QueryParams{VesselIMO: "9876543"}, keepTransshipment: true},
+		{name: "service code", query: schema.QueryParams{Service: "AE7"}, keepDirect: true},
+		{name: "service code of a leg without service", query: schema.QueryParams{Service: "SE2"}},
+		{name: "part of the service name", query: schema.QueryParams{ServiceName: "feeder"}, keepTransshipment: true},
+		{name: "part of the vessel name", query: schema.QueryParams{VesselName: "ever"}, keepDirect: true},
+		{name: "max transit time", query: schema.QueryParams{MaxTransit: &thirtyFive}, keepDirect: true},
+		{name: "zero transit time", query: schema.QueryParams{MaxTransit: &zero}},
+		{name: "zero transshipments", query: schema.QueryParams{MaxTSP: &zero}, keepDirect: true},
+		{name: "excluded port", query: schema.QueryParams{ExcludeTSP: []string{"SGSIN"}}, keepDirect: true},
+		{name: "excluded country", query: schema.QueryParams{ExcludeTSP: []string{"LK", "SG"}}, keepDirect: true},
+		{name: "excluded port not called", query: schema.QueryParams{ExcludeTSP: []string{"LKCMB"}}, keepDirect: true, keepTransshipment: true},
+		{name: "vessel mode only", query: schema.QueryParams{Modes: []schema.TransportType{schema.Vessel}}, keepDirect: true},
+		{
+			name:       "vessel and feeder modes",
+			query:      schema.QueryParams{Modes: []schema.TransportType{schema.Feeder, schema.Vessel}},
+			keepDirect: true, keepTransshipment: true,
+		},
+		{name: "cutoff after the earliest cutoff", query: schema.QueryParams{CutoffAfter: "2024-03-08"}, keepTransshipment: true},
+		{name: "departure stands in for the cutoff", query: schema.QueryParams{CutoffAfter: "2024-03-11T09:00:00"}},
+		{name: "cutoff after a date", query: schema.QueryParams{CutoffAfter: "2024-03-07"}, keepDirect: true, keepTransshipment: true},
+		{name: "filters add up", query: schema.QueryParams{DirectOnly: true, VesselName: "anna"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filter(directSchedule(), &tt.query); got != tt.keepDirect {
+				t.Errorf("direct schedule kept = %v, want %v", got, tt.keepDirect)
+			}
+			if got := filter(transshipmentSchedule(), &tt.query); got != tt.keepTransshipment {
+				t.Errorf("transshipment schedule kept = %v, want %v", got, tt.keepTransshipment)
+			}
+		})
+	}
+}
+
+func TestTransshipmentPorts(t *testing.T) {
+	withoutStart := transshipmentSchedule()
+	withoutStart.Legs[1].PointFrom = nil
+	tests := []struct {
+		name     string
+		schedule *schema.P2PSchedule
+		want     []string
+	}{
+		{name: "direct", schedule: directSchedule(), want: []string{}},
+		{name: "start of the next leg", schedule: transshipmentSchedule(), want: []string{"SGSIN"}},
+		{name: "end of the previous leg", schedule: withoutStart, want: []string{"SGSIN"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := transshipmentPorts(tt.schedule); !slices.Equal(got, tt.want) {
+				t.Errorf("transshipmentPorts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,386 @@
+package p2p_schedule_handler
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"github.com/neckchi/schedulehub/external/carrier_p2p_schedule"
+	"github.com/neckchi/schedulehub/external/interfaces"
+	"github.com/neckchi/schedulehub/internal/database"
+	"github.com/neckchi/schedulehub/internal/exceptions"
+	httpclient "github.com/neckchi/schedulehub/internal/http"
+	"github.com/neckchi/schedulehub/internal/middleware"
+	"github.com/neckchi/schedulehub/internal/schema"
+	env "github.com/neckchi/schedulehub/internal/secret"
+	"github.com/neckchi/schedulehub/internal/utils"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	prewarmConfigPath   = "service.prewarm.p2p"
+	p2pConfigPath       = "service.registry.p2p"
+	prewarmStartupDelay = 30 * time.Second
+	prewarmFetchTimeout = 30 * time.Second
+)
+
+type lane struct {
+	PointFrom string
+	PointTo   string
+}
+
+func (l lane) String() string {
+	return l.PointFrom + "-" + l.PointTo
+}
+
+// LaneHistory counts the lanes requested by the users so that the prewarmer can learn the popular ones
+type LaneHistory struct {
+	mu     sync.Mutex
+	counts map[lane]int
+}
+
+func NewLaneHistory() *LaneHistory {
+	return &LaneHistory{counts: make(map[lane]int)}
+}
+
+func (h *LaneHistory) Record(q *schema.QueryParams) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, pair := range q.PortPairs() {
+		if pair.PointFrom != "" && pair.PointTo != "" {
+			h.counts[lane{pair.PointFrom, pair.PointTo}]++
+		}
+	}
+}
+
+// Top returns the n most requested lanes and halves every counter afterwards so that recent demand outweighs the old one
+func (h *LaneHistory) Top(n int) []lane {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	lanes := make([]lane, 0, len(h.counts))
+	for l := range h.counts {
+		lanes = append(lanes, l)
+	}
+	slices.SortFunc(lanes, func(a, b lane) int {
+		return cmp.Or(cmp.Compare(h.counts[b], h.counts[a]), cmp.Compare(a.String(), b.String()))
+	})
+	for l, count := range h.counts {
+		if count/2 == 0 {
+			delete(h.counts, l)
+		} else {
+			h.counts[l] = count / 2
+		}
+	}
+	return lanes[:min(n, len(lanes))]
+}
+
+type CarrierPrewarmStatus struct {
+	Fetched   int    `json:"fetched"`
+	Failed    int    `json:"failed"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+type PrewarmStatus struct {
+	Enabled        bool                                         `json:"enabled"`
+	Running        bool                                         `json:"running"`
+	Runs           int                                          `json:"runs"`
+	LastStartedAt  string                                       `json:"lastStartedAt,omitempty"`
+	LastFinishedAt string                                       `json:"lastFinishedAt,omitempty"`
+	NextRunAt      string                                       `json:"nextRunAt,omitempty"`
+	Lanes          []string                                     `json:"lanes"`
+	Carriers       map[schema.CarrierCode]*CarrierPrewarmStatus `json:"carriers"`
+}
+
+// Prewarmer refreshes the carrier schedule cache for the popular lanes before the users ask for them
+type Prewarmer struct {
+	client  *httpclient.HttpClient
+	env     *env.Manager
+	ps      *carrier_p2p_schedule.P2PScheduleServiceFactory
+	redis   database.RedisRepository
+	history *LaneHistory
+	mu      sync.RWMutex
+	status  PrewarmStatus
+}
+
+func NewPrewarmer(
+	client *httpclient.HttpClient,
+	env *env.Manager,
+	ps *carrier_p2p_schedule.P2PScheduleServiceFactory,
+	redis database.RedisRepository,
+	history *LaneHistory,
+) *Prewarmer {
+	return &Prewarmer{
+		client:  client,
+		env:     env,
+		ps:      ps,
+		redis:   redis,
+		history: history,
+		status:  PrewarmStatus{Lanes: []string{}, Carriers: map[schema.CarrierCode]*CarrierPrewarmStatus{}},
+	}
+}
+
+// Run prewarms the cache on the configured interval until ctx is canceled. It is meant to be started once in its own goroutine
+func (p *Prewarmer) Run(ctx context.Context) {
+	wait := prewarmStartupDelay // give the config watcher a chance to load config.yaml
+	for {
+		p.updateStatus(func(s *PrewarmStatus) { s.NextRunAt = time.Now().Add(wait).Format(time.RFC3339) })
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		settings, err := middleware.AppConfig(prewarmConfigPath)
+		if err != nil {
+			log.Errorf("Prewarm: failed to read config: %v", err)
+		}
+		wait = time.Duration(utils.IntSetting(settings, "interval", 60)) * time.Minute
+		enabled := utils.BoolSetting(settings, "enabled", false)
+		p.updateStatus(func(s *PrewarmStatus) { s.Enabled = enabled })
+		if enabled {
+			p.prewarm(ctx, settings)
+		}
+	}
+}
+
+func (p *Prewarmer) Status() PrewarmStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	status := p.status
+	status.Carriers = make(map[schema.CarrierCode]*CarrierPrewarmStatus, len(p.status.Carriers))
+	for scac, carrierStatus := range p.status.Carriers {
+		copied := *carrierStatus
+		status.Carriers[scac] = &copied
+	}
+	return status
+}
+
+func (p *Prewarmer) updateStatus(fn func(*PrewarmStatus)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fn(&p.status)
+}
+
+// lanes merges the lanes listed in config.yaml with the top lanes learnt from the request history
+func (p *Prewarmer) lanes(settings map[string]interface{}) []lane {
+	seen := make(map[lane]bool)
+	lanes := make([]lane, 0)
+	addLane := func(l lane) {
+		if seen[l] {
+			return
+		}
+		if schema.RequestValidate.Var(l.PointFrom, "portCodeValidation") != nil || schema.RequestValidate.Var(l.PointTo, "portCodeValidation") != nil {
+			log.Warnf("Prewarm: skipping invalid lane %s", l)
+			return
+		}
+		seen[l] = true
+		lanes = append(lanes, l)
+	}
+	for _, configured := range utils.StringListSetting(settings, "lanes") {
+		ports := strings.FieldsFunc(configured, func(r rune) bool { return r == '-' || r == ':' || r == ' ' })
+		if len(ports) != 2 {
+			log.Warnf("Prewarm: skipping invalid lane %s", configured)
+			continue
+		}
+		addLane(lane{strings.ToUpper(ports[0]), strings.ToUpper(ports[1])})
+	}
+	for _, l := range p.history.Top(utils.IntSetting(settings, "topLanes", 50)) {
+		addLane(l)
+	}
+	return lanes
+}
+
+func (p *Prewarmer) searchRanges(settings map[string]interface{}) []int {
+	ranges := make([]int, 0, 4)
+	for _, value := range utils.StringListSetting(settings, "searchRanges") {
+		searchRange, err := strconv.Atoi(value)
+		if err != nil || schema.RequestValidate.Var(searchRange, "min=1,max=16") != nil {
+			log.Warnf("Prewarm: skipping invalid search range %s", value)
+			continue
+		}
+		ranges = append(ranges, searchRange)
+	}
+	if len(ranges) == 0 {
+		ranges = append(ranges, 4)
+	}
+	return ranges
+}
+
+// rateInterval converts the per carrier limit(calls per minute) into the pause between two carrier calls
+func (p *Prewarmer) rateInterval(settings map[string]interface{}, scac schema.CarrierCode) time.Duration {
+	rateLimits := utils.MapSetting(settings, "rateLimits")
+	perMinute := utils.IntSetting(rateLimits, string(scac), utils.IntSetting(rateLimits, "default", 30))
+	if perMinute <= 0 {
+		perMinute = 1
+	}
+	return time.Minute / time.Duration(perMinute)
+}
+
+// endpoints groups the carriers by the host they are fetched from, e.g. CMDU, APLU, ANNU and CHNL all call the CMA api and have
+// to share its rate limit
+func (p *Prewarmer) endpoints(carriers []schema.CarrierCode) (map[string][]schema.CarrierCode, []string) {
+	groups := make(map[string][]schema.CarrierCode)
+	hosts := make([]string, 0)
+	for _, scac := range carriers {
+		host := string(scac)
+		if config, ok := p.ps.CarrierConfig(scac); ok {
+			if baseURL, err := url.Parse(config.BaseURL); err == nil && baseURL.Host != "" {
+				host = baseURL.Host
+			}
+		}
+		if _, ok := groups[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		groups[host] = append(groups[host], scac)
+	}
+	return groups, hosts
+}
+
+func (p *Prewarmer) prewarm(ctx context.Context, settings map[string]interface{}) {
+	p2pSettings, err := middleware.AppConfig(p2pConfigPath)
+	if err != nil {
+		log.Errorf("Prewarm: failed to read carrier config: %v", err)
+		return
+	}
+	carriers := middleware.DefaultCarriers(utils.MapSetting(p2pSettings, "activeCarriers"))
+	maxSearchRanges := utils.MapSetting(p2pSettings, "maxSearchRange")
+	lanes := p.lanes(settings)
+	searchRanges := p.searchRanges(settings)
+	startDateType := schema.StartDateType(utils.StringSetting(settings, "startDateType", string(schema.Departure)))
+
+	laneNames := make([]string, 0, len(lanes))
+	for _, l := range lanes {
+		laneNames = append(laneNames, l.String())
+	}
+	startTime := time.Now()
+	p.updateStatus(func(s *PrewarmStatus) {
+		s.Running = true
+		s.Runs++
+		s.LastStartedAt = startTime.Format(time.RFC3339)
+		s.Lanes = laneNames
+		s.Carriers = make(map[schema.CarrierCode]*CarrierPrewarmStatus, len(carriers))
+		for _, scac := range carriers {
+			s.Carriers[scac] = &CarrierPrewarmStatus{}
+		}
+	})
+	log.Infof("Prewarm: started for %d lanes and %d carriers", len(lanes), len(carriers))
+
+	// Each endpoint gets its own worker so that the rate limit of one carrier api never slows down the others. The carriers sharing
+	// an endpoint take turns at the strictest of their limits
+	groups, hosts := p.endpoints(carriers)
+	var wg sync.WaitGroup
+	for _, host := range hosts {
+		interval := time.Duration(0)
+		for _, scac := range groups[host] {
+			interval = max(interval, p.rateInterval(settings, scac))
+		}
+		wg.Add(1)
+		go func(group []schema.CarrierCode, interval time.Duration) {
+			defer wg.Done()
+			p.prewarmEndpoint(ctx, group, lanes, searchRanges, maxSearchRanges, startDateType, interval)
+		}(groups[host], interval)
+	}
+	wg.Wait()
+
+	p.updateStatus(func(s *PrewarmStatus) {
+		s.Running = false
+		s.LastFinishedAt = time.Now().Format(time.RFC3339)
+	})
+	log.Infof("Prewarm: finished in %.3fs", time.Since(startTime).Seconds())
+}
+
+func (p *Prewarmer) prewarmEndpoint(ctx context.Context, carriers []schema.CarrierCode, lanes []lane, searchRanges []int, maxSearchRanges map[string]interface{}, startDateType schema.StartDateType, interval time.Duration) {
+	services := make(map[schema.CarrierCode]interfaces.Schedule[[]*schema.P2PSchedule, *schema.QueryParams], len(carriers))
+	for _, scac := range carriers {
+		service, err := p.ps.CreateScheduleService(scac)
+		if err != nil {
+			p.recordResult(scac, err)
+			continue
+		}
+		services[scac] = service
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	first := true
+	for _, l := range lanes {
+		for _, searchRange := range searchRanges {
+			for _, scac := range carriers {
+				service, ok := services[scac]
+				if !ok {
+					continue
+				}
+				if !first {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+					}
+				}
+				first = false
+				p.prewarmLane(ctx, service, scac, l, searchRange, maxSearchRange(maxSearchRanges, scac), startDateType)
+			}
+		}
+	}
+}
+
+// prewarmLane fetches the lane from the carrier even when it is cached and overwrites the cached response, so the users keep
+// getting a fresh copy instead of one about to expire
+func (p *Prewarmer) prewarmLane(ctx context.Context, service interfaces.Schedule[[]*schema.P2PSchedule, *schema.QueryParams], scac schema.CarrierCode, l lane, searchRange int, maxSearchRange int, startDateType schema.StartDateType) {
+	query := &schema.QueryParams{
+		PointFrom:     l.PointFrom,
+		PointTo:       l.PointTo,
+		StartDateType: startDateType,
+		StartDate:     time.Now().Format("2006-01-02"),
+		SearchRange:   searchRange,
+		SCAC:          []schema.CarrierCode{scac},
+	}
+	config, _ := p.ps.CarrierConfig(scac)
+	// the lane and its location lookups are written straight away, the request queue is left to the requests
+	refreshCtx := httpclient.WithCacheRefresh(httpclient.WithWriteThrough(ctx), config.CacheKey)
+	refreshCtx = httpclient.WithRefreshHook(refreshCtx, func(namespace string) {
+		if namespace == config.CacheKey {
+			InvalidateProducts(p.redis, query)
+		}
+	})
+	var err error
+	for _, chunk := range windowChunks(query, maxSearchRange) {
+		fetchCtx, cancel := context.WithTimeout(refreshCtx, prewarmFetchTimeout)
+		_, chunkErr := service.FetchSchedule(fetchCtx, p.client, p.env, chunk, scac)
+		cancel()
+		err = cmp.Or(err, chunkErr)
+	}
+	p.recordResult(scac, err)
+}
+
+func (p *Prewarmer) recordResult(scac schema.CarrierCode, err error) {
+	p.updateStatus(func(s *PrewarmStatus) {
+		carrierStatus, ok := s.Carriers[scac]
+		if !ok {
+			carrierStatus = &CarrierPrewarmStatus{}
+			s.Carriers[scac] = carrierStatus
+		}
+		if err != nil {
+			carrierStatus.Failed++
+			carrierStatus.LastError = err.Error()
+			return
+		}
+		carrierStatus.Fetched++
+	})
+}
+
+func PrewarmStatusHandler(p *Prewarmer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := p.Status()
+		rsp, err := json.Marshal(&status)
+		if err != nil {
+			exceptions.InternalErrorHandler(w, err)
+			return
+		}
+		_, _ = w.Write(rsp)
+	})
+}
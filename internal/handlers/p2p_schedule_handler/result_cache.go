@@ -0,0 +1,120 @@
+package p2p_schedule_handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/neckchi/schedulehub/internal/database"
+	"github.com/neckchi/schedulehub/internal/schema"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+const (
+	productNamespace           = "p2p product"
+	productGenerationNamespace = "p2p product generation"
+	productGenerationExpiry    = 24 * time.Hour
+)
+
+// The final product is cached per canonical query and lane generation. Whenever a carrier entry of the lane is refreshed
+// the generation is bumped, so every product built from the older carrier data is no longer reachable and simply expires.
+
+func laneGenerationKey(q *schema.QueryParams) string {
+	return fmt.Sprintf("%s:%s:%s:%s:%d", q.PointFrom, q.PointTo, q.StartDateType, q.StartDate, q.SearchRange)
+}
+
+// productCacheKey combines the generation of every port pair as the product of a multi port search is built from all of them
+func productCacheKey(redis database.RedisRepository, q *schema.QueryParams) string {
+	generations := make([]string, 0, 1)
+	for _, pair := range q.PortPairs() {
+		generation, exist := redis.Get(productGenerationNamespace, laneGenerationKey(pair))
+		if !exist {
+			generation = []byte("0")
+		}
+		generations = append(generations, string(generation))
+	}
+	return fmt.Sprintf("%s#%s", q.CanonicalKey(), strings.Join(generations, "."))
+}
+
+// InvalidateProducts drops every cached product of the lane once any carrier entry for it has been refreshed
+func InvalidateProducts(redis database.RedisRepository, q *schema.QueryParams) {
+	if _, err := redis.Increment(productGenerationNamespace, laneGenerationKey(q), productGenerationExpiry); err != nil {
+		log.Errorf("Failed to invalidate cached products of %s: %v", laneGenerationKey(q), err)
+	}
+}
+
+// CachedProduct looks up the final normalized product of the query
+func (sss *ScheduleStreamingService) CachedProduct() (*schema.Product, bool) {
+	cached, exist := sss.redis.Get(productNamespace, productCacheKey(sss.redis, sss.queryParams))
+	if !exist {
+		return nil, false
+	}
+	var product schema.Product
+	if err := json.Unmarshal(cached, &product); err != nil {
+		log.Errorf("Failed to decode cached product: %v", err)
+		return nil, false
+	}
+	return &product, true
+}
+
+// CacheProduct stores the final normalized product unless one of the carriers failed, as the result would be incomplete. The
+// schedules have to be the whole stream, the caller checks that it was not cut short
+func (sss *ScheduleStreamingService) CacheProduct(schedules []*schema.P2PSchedule, expiry time.Duration) {
+	if sss.incomplete.Load() {
+		return
+	}
+	product := schema.Product{
+		Origin:      strings.Join(sss.queryParams.OriginPorts(), ","),
+		Destination: strings.Join(sss.queryParams.DestinationPorts(), ","),
+		Schedules:   schedules,
+	}
+	productJSON, err := json.Marshal(&product)
+	if err != nil {
+		log.Errorf("Failed to encode product: %v", err)
+		return
+	}
+	sss.redis.AddToChannel(productNamespace, productCacheKey(sss.redis, sss.queryParams), productJSON, expiry)
+}
+
+// ReplayProduct streams the cached product through the same channel shape as the fanned in carrier schedules
+func (sss *ScheduleStreamingService) ReplayProduct(product *schema.Product) <-chan []*schema.P2PSchedule {
+	out := make(chan []*schema.P2PSchedule)
+	go func() {
+		defer close(out)
+		if len(product.Schedules) == 0 {
+			return
+		}
+		select {
+		case <-sss.ctx.Done():
+		case out <- product.Schedules:
+		}
+	}()
+	return out
+}
+
+// CollectSchedules passes the stream through while keeping a copy of every schedule for caching. collected waits for the
+// collector to stop and tells whether the stream ran to its end, a consumer that returns early has to cancel the context first
+func (sss *ScheduleStreamingService) CollectSchedules(stream <-chan []*schema.P2PSchedule) (<-chan []*schema.P2PSchedule, func() ([]*schema.P2PSchedule, bool)) {
+	out := make(chan []*schema.P2PSchedule)
+	done := make(chan struct{})
+	var collected []*schema.P2PSchedule
+	complete := false
+	go func() {
+		defer close(done)
+		defer close(out)
+		for schedules := range stream {
+			collected = append(collected, schedules...)
+			select {
+			case <-sss.ctx.Done():
+				return
+			case out <- schedules:
+			}
+		}
+		// the fan in closes early as well when the context ends
+		complete = sss.ctx.Err() == nil
+	}()
+	return out, func() ([]*schema.P2PSchedule, bool) {
+		<-done
+		return collected, complete
+	}
+}
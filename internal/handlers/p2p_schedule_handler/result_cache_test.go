@@ -0,0 +1,53 @@
+package p2p_schedule_handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/neckchi/schedulehub/internal/schema"
+	"testing"
+)
+
+// TestCollectSchedules stops reading after the first batch in the early cases, the copy must not be taken as the whole product then
+func TestCollectSchedules(t *testing.T) {
+	tests := []struct {
+		name     string
+		read     int
+		complete bool
+	}{
+		{name: "drained", read: 3, complete: true},
+		{name: "early return", read: 1},
+		{name: "nothing read", read: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			sss := &ScheduleStreamingService{ctx: ctx, queryParams: &schema.QueryParams{}}
+			stream := make(chan []*schema.P2PSchedule)
+			go func() {
+				defer close(stream)
+				for i := range 3 {
+					select {
+					case <-ctx.Done():
+						return
+					case stream <- []*schema.P2PSchedule{{ScheduleID: fmt.Sprint(i)}}:
+					}
+				}
+			}()
+			out, collected := sss.CollectSchedules(stream)
+			for range tt.read {
+				<-out
+			}
+			if tt.read < 3 {
+				cancel()
+			}
+			schedules, complete := collected()
+			if complete != tt.complete {
+				t.Errorf("complete = %v, want %v", complete, tt.complete)
+			}
+			if complete && len(schedules) != 3 {
+				t.Errorf("collected %d schedules, want 3", len(schedules))
+			}
+		})
+	}
+}
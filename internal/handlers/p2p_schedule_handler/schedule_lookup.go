@@ -0,0 +1,160 @@
+package p2p_schedule_handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/neckchi/schedulehub/internal/exceptions"
+	"github.com/neckchi/schedulehub/internal/middleware"
+	"github.com/neckchi/schedulehub/internal/schema"
+	"github.com/neckchi/schedulehub/internal/utils"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	scheduleIndexNamespace = "p2p schedule index"
+	scheduleIndexExpiry    = 7 * 24 * time.Hour
+)
+
+// scheduleIndexEntry keeps the carrier query that produced the schedule so that it can be asked again, together with the last copy
+type scheduleIndexEntry struct {
+	Query    schema.QueryParams  `json:"query"`
+	Schedule *schema.P2PSchedule `json:"schedule"`
+}
+
+// indexSchedules remembers every schedule by its id together with the window of the chunk it was fetched in, so the lookup asks
+// the carrier for no more than it accepts in one call. The copy is overwritten whenever the carrier is asked again
+func (sss *ScheduleStreamingService) indexSchedules(scac schema.CarrierCode, chunk *schema.QueryParams, schedules []*schema.P2PSchedule) {
+	query := schema.QueryParams{
+		PointFrom:     chunk.PointFrom,
+		PointTo:       chunk.PointTo,
+		StartDateType: chunk.StartDateType,
+		StartDate:     chunk.StartDate,
+		SearchRange:   chunk.SearchRange,
+		SCAC:          []schema.CarrierCode{scac},
+	}
+	indexed := make([]scheduleIndexEntry, 0, len(schedules))
+	for _, schedule := range schedules {
+		indexed = append(indexed, scheduleIndexEntry{Query: query, Schedule: schedule})
+	}
+	sss.putIndex(indexed)
+}
+
+// indexInterline remembers the stitched schedules with the requested lane and both carriers, they are looked up by stitching the
+// two carriers again at the same hub
+func (sss *ScheduleStreamingService) indexInterline(lane *schema.QueryParams, schedules []*schema.P2PSchedule) {
+	indexed := make([]scheduleIndexEntry, 0, len(schedules))
+	for _, schedule := range schedules {
+		carriers := make([]schema.CarrierCode, 0, 2)
+		for _, scac := range strings.Split(schedule.Scac, "+") {
+			carriers = append(carriers, schema.CarrierCode(scac))
+		}
+		query := schema.QueryParams{
+			PointFrom:     lane.PointFrom,
+			PointTo:       lane.PointTo,
+			StartDateType: lane.StartDateType,
+			StartDate:     lane.StartDate,
+			SearchRange:   lane.SearchRange,
+			SCAC:          carriers,
+			Connections:   true,
+		}
+		indexed = append(indexed, scheduleIndexEntry{Query: query, Schedule: schedule})
+	}
+	sss.putIndex(indexed)
+}
+
+func (sss *ScheduleStreamingService) putIndex(indexed []scheduleIndexEntry) {
+	entries := make(map[string][]byte, len(indexed))
+	for _, entry := range indexed {
+		entryJSON, err := json.Marshal(&entry)
+		if err != nil {
+			log.Errorf("Failed to encode schedule %s: %v", entry.Schedule.ScheduleID, err)
+			continue
+		}
+		entries[entry.Schedule.ScheduleID] = entryJSON
+	}
+	if len(entries) > 0 {
+		_ = sss.redis.Put(scheduleIndexNamespace, entries, scheduleIndexExpiry)
+	}
+}
+
+// RehydrateSchedule asks the carrier(or its cache) again for the schedule. The last known copy is only returned when the
+// carrier could not be reached, a sailing the carrier no longer offers is reported as nil. The window is still chunked as the
+// entries indexed with the whole request window may be longer than what the carrier accepts
+func (sss *ScheduleStreamingService) RehydrateSchedule(scheduleID string, lastKnown *schema.P2PSchedule) *schema.P2PSchedule {
+	if len(sss.queryParams.SCAC) == 0 {
+		return lastKnown
+	}
+	settings, _ := middleware.AppConfig(p2pConfigPath)
+	sss.searchRanges = utils.MapSetting(settings, "maxSearchRange")
+	var schedules []*schema.P2PSchedule
+	if lastKnown.Interline {
+		schedules = sss.restitchSchedules(lastKnown, settings)
+	} else {
+		schedules = sss.FetchCarrierWindow(sss.queryParams.SCAC[0], sss.queryParams)
+	}
+	for _, schedule := range schedules {
+		if schedule.ScheduleID == scheduleID {
+			return schedule
+		}
+	}
+	if sss.incomplete.Load() {
+		return lastKnown
+	}
+	return nil
+}
+
+func ScheduleLookupHandler(s *P2PScheduleService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheduleID := r.PathValue("scheduleId")
+		if err := schema.RequestValidate.Var(scheduleID, "len=32,hexadecimal"); err != nil {
+			exceptions.RequestErrorHandler(w, fmt.Errorf("invalid scheduleId: %s", scheduleID))
+			return
+		}
+		cached, exist := s.redis.Get(scheduleIndexNamespace, scheduleID)
+		if !exist {
+			exceptions.NotFoundErrorHandler(w, fmt.Errorf("schedule %s not found", scheduleID))
+			return
+		}
+		var entry scheduleIndexEntry
+		if err := json.Unmarshal(cached, &entry); err != nil || entry.Schedule == nil {
+			exceptions.InternalErrorHandler(w, fmt.Errorf("failed to decode schedule %s: %v", scheduleID, err))
+			return
+		}
+		service := NewScheduleStreamingService(r.Context(), s.client, s.env, s.ps, s.redis, &entry.Query)
+		schedule := service.RehydrateSchedule(scheduleID, entry.Schedule)
+		go func() {
+			err := s.redis.Set(r.URL.String())
+			if err != nil {
+				log.Error(err)
+			}
+		}()
+		if schedule == nil {
+			exceptions.NotFoundErrorHandler(w, fmt.Errorf("schedule %s is no longer offered by %s", scheduleID, entry.Schedule.Scac))
+			return
+		}
+		rsp, err := json.Marshal(schedule)
+		if err != nil {
+			exceptions.InternalErrorHandler(w, err)
+			return
+		}
+		utils.WriteWithETag(w, r, rsp)
+	})
+}
+
+// restitchSchedules runs the interline search of the indexed lane again, only at the hub of the schedule
+func (sss *ScheduleStreamingService) restitchSchedules(lastKnown *schema.P2PSchedule, settings map[string]interface{}) []*schema.P2PSchedule {
+	hub := interlineHub(lastKnown)
+	if hub == "" {
+		return nil
+	}
+	sss.connections = newConnectionSettings(utils.MapSetting(settings, "connections"))
+	sss.connections.hubs = []string{hub}
+	var schedules []*schema.P2PSchedule
+	for stitched := range sss.InterlineSchedules(sss.queryParams) {
+		schedules = append(schedules, stitched...)
+	}
+	return schedules
+}
@@ -0,0 +1,138 @@
+package p2p_schedule_handler
+
+import (
+	"cmp"
+	"github.com/neckchi/schedulehub/internal/schema"
+	"github.com/neckchi/schedulehub/internal/utils"
+	"slices"
+	"time"
+)
+
+// defaultRankingWeights is used for sortBy=best when config.yaml has no ranking weights. lower score ranks higher
+var defaultRankingWeights = map[string]float64{
+	"transitTime":    0.5,
+	"transshipments": 0.3,
+	"etd":            0.1,
+	"eta":            0.1,
+}
+
+// eventTime turns the event date into seconds so that it can be scored like any other criterion
+func eventTime(date string) float64 {
+	parsed, _ := time.Parse("2006-01-02T15:04:05", date)
+	return float64(parsed.Unix())
+}
+
+func transshipments(schedule *schema.P2PSchedule) int {
+	return max(len(schedule.Legs)-1, 0)
+}
+
+// firstCutoff is the earliest cutoff of the first leg, schedules without cutoff get an empty string
+func firstCutoff(schedule *schema.P2PSchedule) string {
+	if len(schedule.Legs) == 0 || schedule.Legs[0].Cutoffs == nil {
+		return ""
+	}
+	cutoffs := slices.DeleteFunc([]string{
+		schedule.Legs[0].Cutoffs.CyCutoffDate,
+		schedule.Legs[0].Cutoffs.DocCutoffDate,
+		schedule.Legs[0].Cutoffs.VgmCutoffDate,
+	}, func(cutoff string) bool { return cutoff == "" })
+	if len(cutoffs) == 0 {
+		return ""
+	}
+	return slices.Min(cutoffs)
+}
+
+// tieBreak keeps the order stable across requests when the sort key is the same
+func tieBreak(a, b *schema.P2PSchedule) int {
+	return cmp.Or(cmp.Compare(a.Etd, b.Etd), cmp.Compare(a.Eta, b.Eta), cmp.Compare(a.Scac, b.Scac))
+}
+
+func compareCutoff(a, b *schema.P2PSchedule) int {
+	cutoffA, cutoffB := firstCutoff(a), firstCutoff(b)
+	switch {
+	case cutoffA == cutoffB:
+		return 0
+	case cutoffA == "":
+		return 1
+	case cutoffB == "":
+		return -1
+	}
+	return cmp.Compare(cutoffA, cutoffB)
+}
+
+// rankScores normalizes every criterion into 0..1 across the schedules and adds them up by weight
+func rankScores(schedules []*schema.P2PSchedule, weights map[string]interface{}) map[*schema.P2PSchedule]float64 {
+	criteria := map[string]func(*schema.P2PSchedule) float64{
+		"transitTime":    func(s *schema.P2PSchedule) float64 { return float64(s.TransitTime) },
+		"transshipments": func(s *schema.P2PSchedule) float64 { return float64(transshipments(s)) },
+		"etd":            func(s *schema.P2PSchedule) float64 { return eventTime(s.Etd) },
+		"eta":            func(s *schema.P2PSchedule) float64 { return eventTime(s.Eta) },
+	}
+	scores := make(map[*schema.P2PSchedule]float64, len(schedules))
+	for name, criterion := range criteria {
+		weight := utils.FloatSetting(weights, name, defaultRankingWeights[name])
+		if weight == 0 {
+			continue
+		}
+		values := make([]float64, len(schedules))
+		for i, schedule := range schedules {
+			values[i] = criterion(schedule)
+		}
+		lowest, highest := slices.Min(values), slices.Max(values)
+		if highest == lowest {
+			continue
+		}
+		for i, schedule := range schedules {
+			scores[schedule] += weight * (values[i] - lowest) / (highest - lowest)
+		}
+	}
+	return scores
+}
+
+// SortScheduleList sorts the schedules in place
+func SortScheduleList(schedules []*schema.P2PSchedule, sortBy schema.SortBy, weights map[string]interface{}) {
+	var compare func(a, b *schema.P2PSchedule) int
+	switch sortBy {
+	case schema.SortByEta:
+		compare = func(a, b *schema.P2PSchedule) int { return cmp.Compare(a.Eta, b.Eta) }
+	case schema.SortByTransitTime:
+		compare = func(a, b *schema.P2PSchedule) int { return cmp.Compare(a.TransitTime, b.TransitTime) }
+	case schema.SortByTransshipments:
+		compare = func(a, b *schema.P2PSchedule) int { return cmp.Compare(transshipments(a), transshipments(b)) }
+	case schema.SortByCutoff:
+		compare = compareCutoff
+	case schema.SortByBest:
+		if len(schedules) == 0 {
+			return
+		}
+		scores := rankScores(schedules, weights)
+		compare = func(a, b *schema.P2PSchedule) int { return cmp.Compare(scores[a], scores[b]) }
+	default:
+		compare = func(a, b *schema.P2PSchedule) int { return cmp.Compare(a.Etd, b.Etd) }
+	}
+	slices.SortStableFunc(schedules, func(a, b *schema.P2PSchedule) int {
+		return cmp.Or(compare(a, b), tieBreak(a, b))
+	})
+}
+
+// SortSchedules buffers the whole stream and sends the sorted schedules in one go. It gives up the progressive streaming
+// so it is only plugged in when the client asks for sortBy
+func (sss *ScheduleStreamingService) SortSchedules(stream <-chan []*schema.P2PSchedule, weights map[string]interface{}) <-chan []*schema.P2PSchedule {
+	out := make(chan []*schema.P2PSchedule)
+	go func() {
+		defer close(out)
+		buffered := make([]*schema.P2PSchedule, 0, 64)
+		for schedules := range stream {
+			buffered = append(buffered, schedules...)
+		}
+		if len(buffered) == 0 {
+			return
+		}
+		SortScheduleList(buffered, sss.queryParams.SortBy, weights)
+		select {
+		case <-sss.ctx.Done():
+		case out <- buffered:
+		}
+	}()
+	return out
+}
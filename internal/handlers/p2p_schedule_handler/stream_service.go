@@ -2,10 +2,13 @@ package p2p_schedule_handler
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/go-playground/validator/v10"
+	"github.com/neckchi/schedulehub/external"
 	"github.com/neckchi/schedulehub/external/carrier_p2p_schedule"
+	"github.com/neckchi/schedulehub/external/interfaces"
+	"github.com/neckchi/schedulehub/internal/database"
 	httpclient "github.com/neckchi/schedulehub/internal/http"
 	"github.com/neckchi/schedulehub/internal/schema"
 	env "github.com/neckchi/schedulehub/internal/secret"
@@ -13,16 +16,27 @@ import (
 	log "github.com/sirupsen/logrus"
 	"iter"
 	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // ScheduleService encapsulates the dependencies and methods for handling schedules
 type ScheduleStreamingService struct {
-	ctx         context.Context
-	client      *httpclient.HttpClient
-	env         *env.Manager
-	p2p         *carrier_p2p_schedule.P2PScheduleServiceFactory
-	queryParams *schema.QueryParams
+	ctx           context.Context
+	client        *httpclient.HttpClient
+	env           *env.Manager
+	p2p           *carrier_p2p_schedule.P2PScheduleServiceFactory
+	redis         database.RedisRepository
+	queryParams   *schema.QueryParams
+	incomplete    atomic.Bool // one of the carriers failed so the result must not be cached
+	nextPageToken string
+	connections   connectionSettings
+	searchRanges  map[string]interface{}    // maxSearchRange section read once for the request
+	statuses      chan schema.CarrierStatus // nil unless the client wants to follow the carriers
+	failed        sync.Map                  // carrier lanes that failed, keyed by scac:pointFrom:pointTo
+	buffered      bool                      // the whole result is known before the first schedule is written
 }
 
 // NewScheduleService creates a new instance of ScheduleService
@@ -31,6 +45,7 @@ func NewScheduleStreamingService(
 	client *httpclient.HttpClient,
 	env *env.Manager,
 	p2p *carrier_p2p_schedule.P2PScheduleServiceFactory,
+	redis database.RedisRepository,
 	queryParams *schema.QueryParams,
 ) *ScheduleStreamingService {
 	return &ScheduleStreamingService{
@@ -38,56 +53,231 @@ func NewScheduleStreamingService(
 		client:      client,
 		env:         env,
 		p2p:         p2p,
+		redis:       redis,
 		queryParams: queryParams,
 	}
 }
 
+// Pipeline builds the schedule stream of the query out of the stages the query asks for. finish has to be called once the
+// consumer is done with the stream, it stops the stages and caches the product when the result cache is enabled and the stream
+// was read to the end
+func (sss *ScheduleStreamingService) Pipeline(settings map[string]interface{}) (<-chan []*schema.P2PSchedule, func()) {
+	resultCache := utils.MapSetting(settings, "resultCache")
+	useResultCache := utils.BoolSetting(resultCache, "enabled", false)
+	expiry := time.Duration(utils.IntSetting(resultCache, "expiry", 60)) * time.Minute
+	ranking := utils.MapSetting(settings, "ranking")
+	sss.connections = newConnectionSettings(utils.MapSetting(settings, "connections"))
+	sss.searchRanges = utils.MapSetting(settings, "maxSearchRange")
+	if sss.queryParams.Paginated() {
+		// pages are always backed by the result cache, otherwise they would not be stable
+		sss.buffered = true
+		return sss.Page(sss.ProductSchedules(expiry), ranking), func() {}
+	}
+
+	var fannedInStream <-chan []*schema.P2PSchedule
+	var collected func() ([]*schema.P2PSchedule, bool)
+	product, cacheHit := (*schema.Product)(nil), false
+	if useResultCache {
+		product, cacheHit = sss.CachedProduct()
+	}
+	// the consumer may stop reading before the end of the stream, finish cancels whatever is still running then
+	ctx, cancel := context.WithCancel(sss.ctx)
+	sss.ctx = ctx
+	if cacheHit {
+		fannedInStream = sss.ReplayProduct(product)
+	} else {
+		fanOutscheduleChannels := sss.FanOutScheduleChannels()
+		fannedInStream = sss.FanIn(fanOutscheduleChannels...)
+		if useResultCache {
+			fannedInStream, collected = sss.CollectSchedules(fannedInStream)
+		}
+	}
+	if sss.queryParams.Dedupe {
+		fannedInStream = sss.DedupeSchedules(fannedInStream)
+	}
+	if sss.queryParams.SortBy != "" {
+		fannedInStream = sss.SortSchedules(fannedInStream, ranking)
+	}
+	sss.buffered = cacheHit || sss.queryParams.SortBy != ""
+	return fannedInStream, func() {
+		cancel()
+		if collected == nil {
+			return
+		}
+		if schedules, complete := collected(); complete {
+			sss.CacheProduct(schedules, expiry)
+		}
+	}
+}
+
+// Buffered tells whether the stream of the pipeline is replayed from the result cache or held back for sorting, the response can
+// then be written at once with its ETag
+func (sss *ScheduleStreamingService) Buffered() bool {
+	return sss.buffered
+}
+
 func (sss *ScheduleStreamingService) FanOutScheduleChannels() []<-chan []*schema.P2PSchedule {
-	fanOutChannels := make([]<-chan []*schema.P2PSchedule, 0, len(sss.queryParams.SCAC))
-	compositeFilter := ScheduleFilters(WithDirectOnly(), WithTSP(), WithVesselIMO(), WithService())
-	for _, scac := range sss.queryParams.SCAC {
-		p2pScheduleChan := sss.ConsolidateSchedule(scac)
-		if sss.queryParams.TSP != "" || sss.queryParams.VesselIMO != "" || sss.queryParams.Service != "" || sss.queryParams.DirectOnly {
-			filterSchedule := sss.FilterSchedule(p2pScheduleChan, compositeFilter)
-			fanOutChannels = append(fanOutChannels, sss.ValidateSchedules(filterSchedule))
-		} else {
-			fanOutChannels = append(fanOutChannels, sss.ValidateSchedules(p2pScheduleChan))
+	pairs := sss.queryParams.PortPairs()
+	fanOutChannels := make([]<-chan []*schema.P2PSchedule, 0, len(pairs)*len(sss.queryParams.SCAC))
+	compositeFilter := ScheduleFilters(
+		WithDirectOnly(),
+		WithTSP(),
+		WithVesselIMO(),
+		WithService(),
+		WithServiceName(),
+		WithVesselName(),
+		WithMaxTransitTime(),
+		WithMaxTransshipments(),
+		WithExcludedTSP(),
+		WithTransportModes(),
+		WithCutoffAfter(),
+	)
+	// a multi port search asks every carrier for every port pair
+	for _, pair := range pairs {
+		p2pScheduleChans := make([]<-chan []*schema.P2PSchedule, 0, len(sss.queryParams.SCAC)+1)
+		for _, scac := range sss.queryParams.SCAC {
+			p2pScheduleChans = append(p2pScheduleChans, sss.ConsolidateSchedule(scac, pair))
+		}
+		// an interline schedule always transships so there is no point in stitching a direct only search
+		if sss.queryParams.Connections && !sss.queryParams.DirectOnly && len(sss.connections.hubs) > 0 {
+			p2pScheduleChans = append(p2pScheduleChans, sss.InterlineSchedules(pair))
+		}
+		for _, p2pScheduleChan := range p2pScheduleChans {
+			if sss.queryParams.PostFilters() {
+				filterSchedule := sss.FilterSchedule(p2pScheduleChan, compositeFilter)
+				fanOutChannels = append(fanOutChannels, sss.ValidateSchedules(filterSchedule))
+			} else {
+				fanOutChannels = append(fanOutChannels, sss.ValidateSchedules(p2pScheduleChan))
+			}
 		}
 	}
 	return fanOutChannels
 
 }
 
+// EnableCarrierStatus makes the carriers report when they have answered, it has to be called before the pipeline is built. The
+// reports are only read by StreamResponse, so a pipeline drained before the response starts(pagination) must not enable them
+func (sss *ScheduleStreamingService) EnableCarrierStatus() {
+	sss.statuses = make(chan schema.CarrierStatus)
+}
+
+// Complete tells whether every carrier has answered
+func (sss *ScheduleStreamingService) Complete() bool {
+	return !sss.incomplete.Load()
+}
+
+// reportCarrierStatus is sent before the schedules of the carrier so it is always read before the stream is closed
+func (sss *ScheduleStreamingService) reportCarrierStatus(scac schema.CarrierCode, pair *schema.QueryParams, schedules []*schema.P2PSchedule) {
+	if sss.statuses == nil {
+		return
+	}
+	status := schema.CarrierStatus{Scac: string(scac), Lane: pair.PointFrom + "-" + pair.PointTo, Status: "ok", Schedules: len(schedules)}
+	if _, failed := sss.failed.Load(fmt.Sprintf("%s:%s:%s", scac, pair.PointFrom, pair.PointTo)); failed {
+		status.Status = "failed"
+	} else if len(schedules) == 0 {
+		status.Status = "empty"
+	}
+	select {
+	case <-sss.ctx.Done():
+	case sss.statuses <- status:
+	}
+}
+
 // ConsolidateSchedule creates a channel for schedule consolidation
-func (sss *ScheduleStreamingService) ConsolidateSchedule(scac schema.CarrierCode) <-chan []*schema.P2PSchedule {
+func (sss *ScheduleStreamingService) ConsolidateSchedule(scac schema.CarrierCode, pair *schema.QueryParams) <-chan []*schema.P2PSchedule {
 	stream := make(chan []*schema.P2PSchedule)
 	go func() {
 		defer close(stream)
+		schedules := sss.FetchCarrierWindow(scac, pair)
+		sss.reportCarrierStatus(scac, pair, schedules)
 		select {
 		case <-sss.ctx.Done():
 			return
-		case stream <- sss.FetchCarrierSchedule(scac):
+		case stream <- schedules:
 
 		}
 	}()
 	return stream
 }
 
-// FetchCarrierSchedule fetches schedule for a specific carrier
-func (sss *ScheduleStreamingService) FetchCarrierSchedule(scac schema.CarrierCode) []*schema.P2PSchedule {
+// FetchCarrierSchedule fetches schedule for a specific carrier and port pair in one call
+func (sss *ScheduleStreamingService) FetchCarrierSchedule(scac schema.CarrierCode, pair *schema.QueryParams) []*schema.P2PSchedule {
+	return sss.fetchChunk(scac, pair, pair, pair)
+}
+
+// fetchChunk fetches the window of the chunk. The products are built from the whole pair so its generation is the one to bump, and
+// the one of the requested lane when the pair is a leg of its interline search
+func (sss *ScheduleStreamingService) fetchChunk(scac schema.CarrierCode, lane *schema.QueryParams, pair *schema.QueryParams, chunk *schema.QueryParams) []*schema.P2PSchedule {
 	if sss.ctx.Err() != nil {
 		log.Infof("Context canceled before fetching schedule for %s", scac)
 		return nil
 	}
+	laneKey, queryKey := noRouteCacheKeys(scac, chunk)
+	if _, exist := sss.redis.Get(noRouteNamespace, laneKey); exist {
+		log.Infof("Skip %s as it does not serve %s-%s", scac, pair.PointFrom, pair.PointTo)
+		return nil
+	}
+	if _, exist := sss.redis.Get(noRouteNamespace, queryKey); exist {
+		log.Infof("Skip %s as it has no schedule for the requested period", scac)
+		return nil
+	}
 	service, err := sss.p2p.CreateScheduleService(scac)
 	if err != nil {
 		log.Errorf("Failed to create schedule service: %s", err)
 		return nil
 	}
-	schedules, _ := service.FetchSchedule(sss.ctx, sss.client, sss.env, sss.queryParams, scac)
+	config, _ := sss.p2p.CarrierConfig(scac)
+	ctx := httpclient.WithRefreshHook(sss.ctx, func(namespace string) {
+		if namespace == config.CacheKey {
+			InvalidateProducts(sss.redis, pair)
+			if lane != pair {
+				InvalidateProducts(sss.redis, lane)
+			}
+		}
+	})
+	schedules, err := service.FetchSchedule(ctx, sss.client, sss.env, chunk, scac)
+	if err != nil && !errors.Is(err, httpclient.ErrNotFound) && !errors.Is(err, interfaces.ErrLocationUnavailable) {
+		sss.incomplete.Store(true)
+		sss.failed.Store(fmt.Sprintf("%s:%s:%s", scac, pair.PointFrom, pair.PointTo), true)
+	}
+	sss.cacheNoRoute(scac, laneKey, queryKey, schedules, err)
+	for _, schedule := range schedules {
+		external.ApplyTimeZones(schedule)
+		schedule.ScheduleID = schedule.GenerateScheduleID()
+		if sss.queryParams.MultiPort() {
+			schedule.Lane = pair.PointFrom + "-" + pair.PointTo
+		}
+	}
+	if len(schedules) > 0 {
+		go sss.indexSchedules(scac, chunk, schedules)
+	}
 	return schedules
 }
 
+const noRouteNamespace = "p2p no route"
+
+// noRouteCacheKeys returns the key for the whole lane(carrier answered 404) and the key for the requested period(carrier answered nothing)
+func noRouteCacheKeys(scac schema.CarrierCode, q *schema.QueryParams) (string, string) {
+	laneKey := fmt.Sprintf("%s:%s:%s", scac, q.PointFrom, q.PointTo)
+	queryKey := fmt.Sprintf("%s:%s:%s:%d", laneKey, q.StartDateType, q.StartDate, q.SearchRange)
+	return laneKey, queryKey
+}
+
+// cacheNoRoute remembers the negative carrier response with a shorter TTL so that we dont waste the carrier quota on lanes it doesnt serve.
+// An empty list is only taken as no schedule when the carrier answered, any error means it was not asked or did not answer
+func (sss *ScheduleStreamingService) cacheNoRoute(scac schema.CarrierCode, laneKey, queryKey string, schedules []*schema.P2PSchedule, err error) {
+	config, exists := sss.p2p.CarrierConfig(scac)
+	if !exists || config.NoRouteDuration <= 0 || sss.ctx.Err() != nil {
+		return
+	}
+	switch {
+	case errors.Is(err, httpclient.ErrNotFound):
+		sss.redis.AddToChannel(noRouteNamespace, laneKey, []byte(err.Error()), config.NoRouteDuration)
+	case err == nil && len(schedules) == 0:
+		sss.redis.AddToChannel(noRouteNamespace, queryKey, []byte("no schedule"), config.NoRouteDuration)
+	}
+}
+
 func (sss *ScheduleStreamingService) PostFilter(schedules []*schema.P2PSchedule, filter ScheduleFilterOption) iter.Seq[*schema.P2PSchedule] {
 	return func(yield func(*schema.P2PSchedule) bool) {
 		for _, schedule := range schedules {
@@ -176,43 +366,49 @@ func (sss *ScheduleStreamingService) FanIn(channels ...<-chan []*schema.P2PSched
 	return fannedInStream
 }
 
-// StreamResponse handles the streaming of response data
-func (sss *ScheduleStreamingService) StreamResponse(w utils.FlushWriter, fannedIn <-chan []*schema.P2PSchedule) {
-	_, _ = w.Write([]byte(fmt.Sprintf(
-		`{"origin":"%s","destination":"%s","schedules":[`, sss.queryParams.PointFrom, sss.queryParams.PointTo,
-	)))
+// StreamResponse writes the schedules with the negotiated encoder as soon as they come in. The carrier status is interleaved when
+// it is enabled. A failed write cancels the pipeline as the client is gone
+func (sss *ScheduleStreamingService) StreamResponse(w utils.FlushWriter, fannedIn <-chan []*schema.P2PSchedule, encoder ScheduleEncoder) {
+	if err := encoder.Open(w, strings.Join(sss.queryParams.OriginPorts(), ","), strings.Join(sss.queryParams.DestinationPorts(), ",")); err != nil {
+		log.Errorf("Failed to write the response: %v", err)
+		return
+	}
 	w.Flush() // Flush data right away
 
 	scheduleCount := 0
-	first := true
-	doneProcessing := make(chan int) // Signal when processing is done
-
-	go func() {
-		defer close(doneProcessing)
-		for schedules := range fannedIn {
-			select {
-			case <-sss.ctx.Done():
+	statuses := sss.statuses
+	for fannedIn != nil {
+		select {
+		case <-sss.ctx.Done():
+			return
+		case status := <-statuses:
+			if err := encoder.CarrierStatus(w, status); err != nil {
+				log.Errorf("Failed to write the carrier status: %v", err)
 				return
-			default:
-				for _, schedule := range schedules {
-					if !first {
-						_, _ = w.Write([]byte(","))
-					}
-					first = false
-					scheduleJSON, _ := json.Marshal(schedule) // this need to be changed
-					_, _ = w.Write(scheduleJSON)
-					w.Flush()
-					scheduleCount++
+			}
+			w.Flush()
+		case schedules, ok := <-fannedIn:
+			if !ok {
+				fannedIn = nil
+				continue
+			}
+			for _, schedule := range schedules {
+				if err := encoder.Schedule(w, schedule); err != nil {
+					log.Errorf("Failed to write schedule %s: %v", schedule.ScheduleID, err)
+					return
 				}
+				scheduleCount++
 			}
+			w.Flush()
 		}
-	}()
+	}
 
-	<-doneProcessing // Block until goroutine finishes (ensures JSON is properly closed)
+	trailer := schema.StreamTrailer{Done: true, Total: scheduleCount, Complete: sss.Complete(), NextPageToken: sss.nextPageToken}
 	if scheduleCount == 0 {
-		_, _ = w.Write([]byte(`],"message":"No available schedules for the requested route."}`))
-	} else {
-		_, _ = w.Write([]byte(`]}`))
+		trailer.Message = noScheduleMessage
+	}
+	if err := encoder.Close(w, trailer); err != nil {
+		log.Errorf("Failed to write the response: %v", err)
 	}
 	w.Flush()
 }
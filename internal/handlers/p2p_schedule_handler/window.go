@@ -0,0 +1,86 @@
+package p2p_schedule_handler
+
+import (
+	"github.com/neckchi/schedulehub/internal/schema"
+	"github.com/neckchi/schedulehub/internal/utils"
+	"sync"
+	"time"
+)
+
+// defaultMaxSearchRange is the window every carrier adapter was built and run against before the searches got chunked
+const defaultMaxSearchRange = 4
+
+// maxSearchRange gives the weeks the carrier accepts in one call out of the maxSearchRange section of service.registry.p2p, keyed
+// by scac. The carriers not listed take the default entry
+func maxSearchRange(limits map[string]interface{}, scac schema.CarrierCode) int {
+	return utils.IntSetting(limits, string(scac), utils.IntSetting(limits, "default", defaultMaxSearchRange))
+}
+
+// windowChunks splits the search window into consecutive chunks the carrier can answer in one call
+func windowChunks(pair *schema.QueryParams, maxSearchRange int) []*schema.QueryParams {
+	if maxSearchRange <= 0 {
+		maxSearchRange = defaultMaxSearchRange
+	}
+	if pair.SearchRange <= maxSearchRange {
+		return []*schema.QueryParams{pair}
+	}
+	startDate, err := time.Parse("2006-01-02", pair.StartDate)
+	if err != nil {
+		return []*schema.QueryParams{pair}
+	}
+	chunks := make([]*schema.QueryParams, 0, pair.SearchRange/maxSearchRange+1)
+	for week := 0; week < pair.SearchRange; week += maxSearchRange {
+		chunk := *pair
+		chunk.StartDate = startDate.AddDate(0, 0, week*7).Format("2006-01-02")
+		chunk.SearchRange = min(maxSearchRange, pair.SearchRange-week)
+		chunks = append(chunks, &chunk)
+	}
+	return chunks
+}
+
+// FetchCarrierWindow fetches the search window of the pair chunk by chunk when it is longer than what the carrier supports. Every
+// chunk is a call of its own so it is cached on its own, the sailings showing up on both sides of a chunk boundary are merged
+func (sss *ScheduleStreamingService) FetchCarrierWindow(scac schema.CarrierCode, pair *schema.QueryParams) []*schema.P2PSchedule {
+	return sss.fetchWindow(scac, pair, pair)
+}
+
+// fetchWindow fetches the window of the pair for the product of the requested lane, the two differ on the legs of an interline
+// search
+func (sss *ScheduleStreamingService) fetchWindow(scac schema.CarrierCode, lane *schema.QueryParams, pair *schema.QueryParams) []*schema.P2PSchedule {
+	chunks := windowChunks(pair, maxSearchRange(sss.searchRanges, scac))
+	if len(chunks) == 1 {
+		return sss.fetchChunk(scac, lane, pair, pair)
+	}
+	results := make([][]*schema.P2PSchedule, len(chunks))
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk *schema.QueryParams) {
+			defer wg.Done()
+			results[i] = sss.fetchChunk(scac, lane, pair, chunk)
+		}(i, chunk)
+	}
+	wg.Wait()
+	return mergeChunks(results)
+}
+
+// mergeChunks concatenates the chunks in window order, a sailing showing up on both sides of a chunk boundary is kept once
+func mergeChunks(results [][]*schema.P2PSchedule) []*schema.P2PSchedule {
+	type boundaryKey struct {
+		ScheduleID string
+		Etd        string
+	}
+	seen := make(map[boundaryKey]bool)
+	merged := make([]*schema.P2PSchedule, 0)
+	for _, schedules := range results {
+		for _, schedule := range schedules {
+			key := boundaryKey{schedule.ScheduleID, schedule.Etd}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, schedule)
+		}
+	}
+	return merged
+}
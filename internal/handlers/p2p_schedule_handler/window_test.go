@@ -0,0 +1,123 @@
+package p2p_schedule_handler
+
+import (
+	"github.com/neckchi/schedulehub/internal/schema"
+	"slices"
+	"testing"
+)
+
+func TestWindowChunks(t *testing.T) {
+	type chunk struct {
+		startDate   string
+		searchRange int
+	}
+	tests := []struct {
+		name           string
+		startDate      string
+		searchRange    int
+		maxSearchRange int
+		want           []chunk
+	}{
+		{name: "within the limit", startDate: "2024-03-10", searchRange: 4, maxSearchRange: 4, want: []chunk{{"2024-03-10", 4}}},
+		{name: "even split", startDate: "2024-03-10", searchRange: 8, maxSearchRange: 4, want: []chunk{{"2024-03-10", 4}, {"2024-04-07", 4}}},
+		{
+			name: "remainder", startDate: "2024-12-20", searchRange: 10, maxSearchRange: 4,
+			want: []chunk{{"2024-12-20", 4}, {"2025-01-17", 4}, {"2025-02-14", 2}},
+		},
+		{name: "one week chunks", startDate: "2024-02-26", searchRange: 2, maxSearchRange: 1, want: []chunk{{"2024-02-26", 1}, {"2024-03-04", 1}}},
+		{name: "no limit falls back to the default", startDate: "2024-03-10", searchRange: 6, maxSearchRange: 0, want: []chunk{{"2024-03-10", 4}, {"2024-04-07", 2}}},
+		{name: "unparsable date is left alone", startDate: "10/03/2024", searchRange: 8, maxSearchRange: 4, want: []chunk{{"10/03/2024", 8}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := &schema.QueryParams{PointFrom: "CNSHA", PointTo: "DEHAM", StartDate: tt.startDate, SearchRange: tt.searchRange}
+			var got []chunk
+			for _, c := range windowChunks(pair, tt.maxSearchRange) {
+				if c.PointFrom != pair.PointFrom || c.PointTo != pair.PointTo {
+					t.Errorf("chunk of %s-%s, want the ports of the pair", c.PointFrom, c.PointTo)
+				}
+				got = append(got, chunk{c.StartDate, c.SearchRange})
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("windowChunks() = %v, want %v", got, tt.want)
+			}
+			if pair.StartDate != tt.startDate || pair.SearchRange != tt.searchRange {
+				t.Error("windowChunks() changed the pair")
+			}
+		})
+	}
+}
+
+func TestMergeChunks(t *testing.T) {
+	schedule := func(id, etd string) *schema.P2PSchedule {
+		return &schema.P2PSchedule{ScheduleID: id, Etd: etd}
+	}
+	tests := []struct {
+		name   string
+		chunks [][]*schema.P2PSchedule
+		want   []string
+	}{
+		{name: "no chunk", chunks: nil, want: nil},
+		{
+			name: "window order",
+			chunks: [][]*schema.P2PSchedule{
+				{schedule("a", "2024-03-10"), schedule("b", "2024-03-12")},
+				{schedule("c", "2024-04-08")},
+			},
+			want: []string{"a@2024-03-10", "b@2024-03-12", "c@2024-04-08"},
+		},
+		{
+			name: "sailing on both sides of the boundary",
+			chunks: [][]*schema.P2PSchedule{
+				{schedule("a", "2024-03-10"), schedule("b", "2024-04-07")},
+				{schedule("b", "2024-04-07"), schedule("c", "2024-04-08")},
+			},
+			want: []string{"a@2024-03-10", "b@2024-04-07", "c@2024-04-08"},
+		},
+		{
+			name: "weekly service keeps every departure",
+			chunks: [][]*schema.P2PSchedule{
+				{schedule("a", "2024-03-10")},
+				{schedule("a", "2024-04-07")},
+			},
+			want: []string{"a@2024-03-10", "a@2024-04-07"},
+		},
+		{
+			name:   "failed chunk",
+			chunks: [][]*schema.P2PSchedule{nil, {schedule("c", "2024-04-08")}},
+			want:   []string{"c@2024-04-08"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, s := range mergeChunks(tt.chunks) {
+				got = append(got, s.ScheduleID+"@"+s.Etd)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("mergeChunks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxSearchRange(t *testing.T) {
+	limits := map[string]interface{}{"default": 4, "MAEU": 8}
+	tests := []struct {
+		name   string
+		limits map[string]interface{}
+		scac   schema.CarrierCode
+		want   int
+	}{
+		{name: "listed carrier", limits: limits, scac: "MAEU", want: 8},
+		{name: "default entry", limits: limits, scac: "CMDU", want: 4},
+		{name: "no section", limits: nil, scac: "MAEU", want: defaultMaxSearchRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maxSearchRange(tt.limits, tt.scac); got != tt.want {
+				t.Errorf("maxSearchRange(%s) = %d, want %d", tt.scac, got, tt.want)
+			}
+		})
+	}
+}
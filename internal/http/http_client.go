@@ -3,6 +3,7 @@ package httpclient
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"io"
@@ -14,6 +15,66 @@ import (
 	"time"
 )
 
+var (
+	// ErrNotFound is returned when the carrier answers 404 which usually means there is no route between the requested ports
+	ErrNotFound = errors.New("resource not found")
+	// ErrUnauthorized is returned when the carrier rejects the credentials, e.g. the token has been revoked before it expired
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+type refreshHookKey struct{}
+
+// WithRefreshHook returns a context that calls hook with the cache namespace whenever Fetch caches a fresh response from the carrier.
+// Derived caches use it to find out that the data they were built from has been refreshed.
+func WithRefreshHook(ctx context.Context, hook func(namespace string)) context.Context {
+	return context.WithValue(ctx, refreshHookKey{}, hook)
+}
+
+func notifyRefresh(ctx context.Context, namespace string) {
+	if hook, ok := ctx.Value(refreshHookKey{}).(func(string)); ok {
+		hook(namespace)
+	}
+}
+
+type cacheRefreshKey struct{}
+
+// WithCacheRefresh returns a context under which Fetch skips the cached response of the namespace and overwrites it with the fresh
+// one. The other namespaces(tokens, locations) are still served from the cache
+func WithCacheRefresh(ctx context.Context, namespace string) context.Context {
+	return context.WithValue(ctx, cacheRefreshKey{}, namespace)
+}
+
+func refreshing(ctx context.Context, namespace string) bool {
+	refreshed, ok := ctx.Value(cacheRefreshKey{}).(string)
+	return ok && refreshed == namespace
+}
+
+type writeThroughKey struct{}
+
+// WithWriteThrough returns a context under which Fetch writes every fresh response straight away instead of queueing it for the
+// next Set. The background jobs use it as there is no request around to flush the queue for them
+func WithWriteThrough(ctx context.Context) context.Context {
+	return context.WithValue(ctx, writeThroughKey{}, true)
+}
+
+func writingThrough(ctx context.Context) bool {
+	writeThrough, _ := ctx.Value(writeThroughKey{}).(bool)
+	return writeThrough
+}
+
+// cacheResponse queues the response for the next Set unless the namespace is refreshed or the context writes through, the queued
+// entries never replace a cached value so a refresh is written straight away
+func (hc *HttpClientWrapper) cacheResponse(ctx context.Context, namespace, key string, result []byte, expiry time.Duration) {
+	if refreshing(ctx, namespace) || writingThrough(ctx) {
+		if err := hc.redisDb.Put(namespace, map[string][]byte{key: result}, expiry); err != nil {
+			log.Errorf("failed to cache %s: %v", key, err)
+		}
+	} else {
+		hc.redisDb.AddToChannel(namespace, key, result, expiry)
+	}
+	notifyRefresh(ctx, namespace)
+}
+
 func (hc *HttpClientWrapper) methodRegister(ctx context.Context, method string, urlString *string, params *map[string]string, headers *map[string]string) (*http.Request, error) {
 	var request *http.Request
 	var err error
@@ -150,8 +211,8 @@ func (hc *HttpClientWrapper) Fetch(ctx context.Context, method string, urlString
 			log.Error(lastErr)
 			break
 		}
-		// Check Redis cache before making HTTP request at first time
-		if attempt == 0 {
+		// Check Redis cache before making HTTP request at first time. A non-positive expiry or a refresh bypasses the cache
+		if attempt == 0 && expiry > 0 && !refreshing(ctx, namespace) {
 			cacheResult, exist := hc.redisDb.Get(namespace, request.URL.String())
 			if exist {
 				return cacheResult, nil
@@ -180,17 +241,29 @@ func (hc *HttpClientWrapper) Fetch(ctx context.Context, method string, urlString
 			case http.StatusOK:
 				result, err = io.ReadAll(resp.Body)
 				if err == nil {
-					hc.redisDb.AddToChannel(namespace, request.URL.String(), result, expiry)
+					if expiry > 0 {
+						hc.cacheResponse(ctx, namespace, request.URL.String(), result, expiry)
+					}
 					return result, nil
 				}
 
 			case http.StatusPartialContent:
 				result, err = hc.fetchPartialContent(childCtx, method, urlString, params, headers, resp)
 				if err == nil {
-					hc.redisDb.AddToChannel(namespace, request.URL.String(), result, expiry)
+					if expiry > 0 {
+						hc.cacheResponse(ctx, namespace, request.URL.String(), result, expiry)
+					}
 					return result, nil
 				}
 
+			case http.StatusUnauthorized:
+				Err := fmt.Errorf("Failed to process the request for %s due to http status %d: %w", request.URL, resp.StatusCode, ErrUnauthorized)
+				return nil, Err
+
+			case http.StatusNotFound:
+				Err := fmt.Errorf("Failed to process the request for %s due to http status %d: %w", request.URL, resp.StatusCode, ErrNotFound)
+				return nil, Err
+
 			default:
 				Err := fmt.Errorf("Failed to process the request for %s due to http status %d", request.URL, resp.StatusCode)
 				return nil, Err
@@ -23,7 +23,9 @@ type appConfig string
 
 const ScheduleConfig appConfig = "scheduleConfig"
 
-func GetAppConfig(path string) func(http.Handler) http.Handler {
+// watchAppConfig starts the config watcher once. it wont spawn as many goroutine as the incoming request
+// And the gorouinte will keep reading the config yaml every 5 mins.
+func watchAppConfig() {
 	configOnce.Do(func() {
 		currentDir, err := os.Getwd()
 		if err != nil {
@@ -35,11 +37,22 @@ func GetAppConfig(path string) func(http.Handler) http.Handler {
 		}
 		go configService.Watch(time.Minute * 5)
 	})
+}
+
+// AppConfig returns the latest config of a particular service for the background workers that live outside the middleware stack
+func AppConfig(path string) (map[string]interface{}, error) {
+	watchAppConfig()
+	c := controller.Controller{
+		Config: &config,
+	}
+	return c.Config.Get(path)
+}
+
+func GetAppConfig(path string) func(http.Handler) http.Handler {
+	watchAppConfig()
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			var err error
-			// this will be called once. it wont spawn as many goroutine as the incoming request
-			// And the gorouinte will keep reading the config yaml every 5 mins.
 			//We can consider link all app secret and  config to AWS PS standard tier with the sync.once func
 			c := controller.Controller{
 				Config: &config,
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/neckchi/schedulehub/internal/exceptions"
+	"github.com/neckchi/schedulehub/internal/schema"
+	"github.com/neckchi/schedulehub/internal/utils"
+)
+
+const (
+	P2PBatchQueryParamsKey queryContextKey = "p2PBatchQueryParams"
+	maxBatchBodySize                       = 1 << 20
+)
+
+// P2PBatchValidation validates the lane queries posted to the batch endpoint. Every lane goes through the same checks as a single
+// p2p request, the lane index is part of the error so the client knows which one to fix.
+func P2PBatchValidation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		settings, _ := r.Context().Value(ScheduleConfig).(map[string]interface{})
+		var batch schema.BatchQuery
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBodySize))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&batch); err != nil {
+			exceptions.RequestErrorHandler(w, fmt.Errorf("invalid batch request: %v", err))
+			return
+		}
+		maxLanes := utils.IntSetting(utils.MapSetting(settings, "batch"), "maxLanes", 40)
+		if len(batch.Lanes) == 0 || len(batch.Lanes) > maxLanes {
+			exceptions.RequestErrorHandler(w, fmt.Errorf("a batch must contain between 1 and %d lanes", maxLanes))
+			return
+		}
+
+		for i := range batch.Lanes {
+			lane := &batch.Lanes[i]
+			if lane.Limit != 0 || lane.PageToken != "" || len(lane.Fields) > 0 {
+				exceptions.RequestErrorHandler(w, fmt.Errorf("lane %d: limit, pageToken and fields are not supported in a batch", i))
+				return
+			}
+			if err := PrepareP2PQuery(lane, settings); err != nil {
+				exceptions.RequestErrorHandler(w, fmt.Errorf("lane %d: %v", i, err))
+				return
+			}
+		}
+
+		ctx := context.WithValue(r.Context(), P2PBatchQueryParamsKey, batch)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
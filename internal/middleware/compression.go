@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// compressor is what gzip.Writer and brotli.Writer have in common, Flush pushes out what was written so far
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// coding is a content coding the responses can go out with. The suffix tells its representation apart in the ETag, a strong
+// ETag must differ per content coding
+type coding struct {
+	name    string
+	suffix  string
+	writers *sync.Pool
+}
+
+// brotliQuality trades the ratio for speed, the schedules are compressed as they stream and every chunk is flushed
+const brotliQuality = 4
+
+// codings in the order of preference when the client weighs them the same. Both writers are reused across responses, a
+// gzip.Writer allocates close to 1MB of state
+var codings = []coding{
+	{name: "br", suffix: "-br", writers: &sync.Pool{New: func() interface{} { return brotli.NewWriterLevel(nil, brotliQuality) }}},
+	{name: "gzip", suffix: "-gzip", writers: &sync.Pool{New: func() interface{} { return gzip.NewWriter(nil) }}},
+}
+
+// negotiateCoding reads the q-values of Accept-Encoding and picks the coding with the highest one, * covers the codings that
+// are not listed and q=0 refuses one. nil leaves the response uncompressed
+func negotiateCoding(acceptEncoding string) *coding {
+	weights := map[string]float64{}
+	for _, entry := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(entry), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "x-gzip" {
+			name = "gzip"
+		}
+		if name != "" {
+			weights[name] = q
+		}
+	}
+	var best *coding
+	bestQ := 0.0
+	for i := range codings {
+		q, listed := weights[codings[i].name]
+		if !listed {
+			q = weights["*"]
+		}
+		if q > bestQ {
+			best, bestQ = &codings[i], q
+		}
+	}
+	return best
+}
+
+// compressible leaves out the formats that are compressed already(xlsx is a zip)
+func compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "json") || mediaType == "application/x-ndjson"
+}
+
+// compressWriter compresses the body once the handler has settled the headers. Flush pushes the compressed bytes of every chunk
+// to the client so the schedule stream stays a stream
+type compressWriter struct {
+	http.ResponseWriter
+	coding      *coding
+	cw          compressor
+	wroteHeader bool
+}
+
+func (c *compressWriter) WriteHeader(statusCode int) {
+	if c.wroteHeader {
+		return
+	}
+	c.wroteHeader = true
+	header := c.Header()
+	header.Add("Vary", "Accept-Encoding")
+	if header.Get("Content-Encoding") == "" && compressible(header.Get("Content-Type")) {
+		if etag := header.Get("ETag"); strings.HasSuffix(etag, `"`) {
+			header.Set("ETag", strings.TrimSuffix(etag, `"`)+c.coding.suffix+`"`)
+		}
+		// 1xx, 204 and 304 carry no body
+		if statusCode >= http.StatusOK && statusCode != http.StatusNoContent && statusCode != http.StatusNotModified {
+			header.Set("Content-Encoding", c.coding.name)
+			header.Del("Content-Length")
+			c.cw = c.coding.writers.Get().(compressor)
+			c.cw.Reset(c.ResponseWriter)
+		}
+	}
+	c.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (c *compressWriter) Write(b []byte) (int, error) {
+	if !c.wroteHeader {
+		c.WriteHeader(http.StatusOK)
+	}
+	if c.cw != nil {
+		return c.cw.Write(b)
+	}
+	return c.ResponseWriter.Write(b)
+}
+
+func (c *compressWriter) Flush() {
+	if c.cw != nil {
+		if err := c.cw.Flush(); err != nil {
+			return
+		}
+	}
+	if flusher, ok := c.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (c *compressWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+func (c *compressWriter) close() {
+	if c.cw == nil {
+		return
+	}
+	_ = c.cw.Close()
+	c.cw.Reset(nil)
+	c.coding.writers.Put(c.cw)
+	c.cw = nil
+}
+
+// stripCodingSuffixes takes the suffixes of every coding off the ETags of If-None-Match, a client holding the gzip
+// representation gets its 304 when it asks for br next time
+func stripCodingSuffixes(ifNoneMatch string) string {
+	for _, c := range codings {
+		ifNoneMatch = strings.ReplaceAll(ifNoneMatch, c.suffix+`"`, `"`)
+	}
+	return ifNoneMatch
+}
+
+// Compress compresses the response with br or gzip, whichever the client weighs higher. The suffix the ETag gets is taken off
+// If-None-Match again so the handler compares it against the ETag of the plain body
+func Compress(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		coding := negotiateCoding(r.Header.Get("Accept-Encoding"))
+		if r.Method == http.MethodHead || coding == nil {
+			w.Header().Add("Vary", "Accept-Encoding")
+			next.ServeHTTP(w, r)
+			return
+		}
+		if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", stripCodingSuffixes(ifNoneMatch))
+		}
+		cw := &compressWriter{ResponseWriter: w, coding: coding}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	}
+	return http.HandlerFunc(fn)
+}
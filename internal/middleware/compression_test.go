@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/neckchi/schedulehub/internal/utils"
+)
+
+func TestNegotiateCoding(t *testing.T) {
+	tests := []struct {
+		acceptEncoding string
+		want           string
+	}{
+		{acceptEncoding: "", want: ""},
+		{acceptEncoding: "identity", want: ""},
+		{acceptEncoding: "gzip", want: "gzip"},
+		{acceptEncoding: "x-gzip", want: "gzip"},
+		{acceptEncoding: "br", want: "br"},
+		{acceptEncoding: "gzip, deflate, br", want: "br"},
+		{acceptEncoding: "br;q=0.5, gzip;q=0.8", want: "gzip"},
+		{acceptEncoding: "BR;q=1.0, GZIP;q=0.9", want: "br"},
+		{acceptEncoding: "br;q=0, gzip", want: "gzip"},
+		{acceptEncoding: "gzip;q=0, br;q=0", want: ""},
+		{acceptEncoding: "*", want: "br"},
+		{acceptEncoding: "*;q=0.1, br;q=0", want: "gzip"},
+		{acceptEncoding: "gzip;q=0.2, *;q=0.5", want: "br"},
+		{acceptEncoding: "gzip;q=abc", want: "gzip"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			got := ""
+			if c := negotiateCoding(tt.acceptEncoding); c != nil {
+				got = c.name
+			}
+			if got != tt.want {
+				t.Errorf("negotiateCoding(%q) = %q, want %q", tt.acceptEncoding, got, tt.want)
+			}
+		})
+	}
+}
+
+func decompress(t *testing.T, contentEncoding string, body io.Reader) string {
+	t.Helper()
+	var reader io.Reader
+	switch contentEncoding {
+	case "gzip":
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = gz
+	case "br":
+		reader = brotli.NewReader(body)
+	default:
+		reader = body
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to read the %q body: %v", contentEncoding, err)
+	}
+	return string(decoded)
+}
+
+func TestCompress(t *testing.T) {
+	const body = `{"origin":"CNSHA","destination":"DEHAM","schedules":[]}`
+	plainETag := utils.StrongETag([]byte(body))
+	buffered := Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", r.URL.Query().Get("type"))
+		utils.WriteWithETag(w, r, []byte(body))
+	}))
+	tests := []struct {
+		name           string
+		method         string
+		contentType    string
+		acceptEncoding string
+		ifNoneMatch    string
+		status         int
+		encoding       string
+		etag           string
+	}{
+		{name: "identity", acceptEncoding: "", status: http.StatusOK, etag: plainETag},
+		{name: "gzip", acceptEncoding: "gzip", status: http.StatusOK, encoding: "gzip", etag: strings.TrimSuffix(plainETag, `"`) + `-gzip"`},
+		{name: "br", acceptEncoding: "gzip, br", status: http.StatusOK, encoding: "br", etag: strings.TrimSuffix(plainETag, `"`) + `-br"`},
+		{name: "head", method: http.MethodHead, acceptEncoding: "br", status: http.StatusOK, etag: plainETag},
+		{
+			name:        "xlsx is a zip already",
+			contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", acceptEncoding: "br",
+			status: http.StatusOK, etag: plainETag,
+		},
+		{
+			name: "304 of the br etag", acceptEncoding: "br", ifNoneMatch: strings.TrimSuffix(plainETag, `"`) + `-br"`,
+			status: http.StatusNotModified, etag: strings.TrimSuffix(plainETag, `"`) + `-br"`,
+		},
+		{
+			name: "304 of the gzip etag asked with br", acceptEncoding: "br", ifNoneMatch: `"other", ` + strings.TrimSuffix(plainETag, `"`) + `-gzip"`,
+			status: http.StatusNotModified, etag: strings.TrimSuffix(plainETag, `"`) + `-br"`,
+		},
+		{
+			name: "the compressed etag does not match the identity body", acceptEncoding: "identity", ifNoneMatch: strings.TrimSuffix(plainETag, `"`) + `-br"`,
+			status: http.StatusOK, etag: plainETag,
+		},
+		{name: "stale etag", acceptEncoding: "gzip", ifNoneMatch: `"stale-gzip"`, status: http.StatusOK, encoding: "gzip", etag: strings.TrimSuffix(plainETag, `"`) + `-gzip"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contentType := tt.contentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			r := httptest.NewRequest(tt.method, "/schedules/p2p?type="+contentType, nil)
+			r.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			if tt.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			rsp := httptest.NewRecorder()
+			buffered.ServeHTTP(rsp, r)
+			if rsp.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rsp.Code, tt.status)
+			}
+			if got := rsp.Header().Get("Content-Encoding"); got != tt.encoding {
+				t.Errorf("Content-Encoding = %q, want %q", got, tt.encoding)
+			}
+			if got := rsp.Header().Get("ETag"); got != tt.etag {
+				t.Errorf("ETag = %s, want %s", got, tt.etag)
+			}
+			if got := rsp.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", got)
+			}
+			switch {
+			case tt.method == http.MethodHead: // the server drops the body
+			case tt.status == http.StatusOK:
+				if got := decompress(t, tt.encoding, rsp.Body); got != body {
+					t.Errorf("body = %q, want %q", got, body)
+				}
+			case rsp.Body.Len() > 0:
+				t.Errorf("%d carries a body of %d bytes", rsp.Code, rsp.Body.Len())
+			}
+		})
+	}
+}
+
+// TestCompressFlushesEveryChunk reads the first chunk while the handler still holds the second one back, the compressor has to
+// push it out on Flush
+func TestCompressFlushesEveryChunk(t *testing.T) {
+	for _, coding := range []string{"gzip", "br"} {
+		t.Run(coding, func(t *testing.T) {
+			release := make(chan struct{})
+			server := httptest.NewServer(Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/x-ndjson")
+				_, _ = io.WriteString(w, `{"scac":"MAEU"}`+"\n")
+				w.(http.Flusher).Flush()
+				<-release
+				_, _ = io.WriteString(w, `{"scac":"MSCU"}`+"\n")
+			})))
+			defer server.Close()
+			defer close(release)
+
+			r, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.Header.Set("Accept-Encoding", coding) // set by hand, the transport leaves the body compressed then
+			rsp, err := server.Client().Do(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rsp.Body.Close()
+			if got := rsp.Header.Get("Content-Encoding"); got != coding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, coding)
+			}
+			var reader io.Reader = rsp.Body
+			if coding == "gzip" {
+				if reader, err = gzip.NewReader(rsp.Body); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				reader = brotli.NewReader(rsp.Body)
+			}
+			line, err := bufio.NewReader(reader).ReadString('\n')
+			if err != nil {
+				t.Fatalf("failed to read the first chunk: %v", err)
+			}
+			if line != `{"scac":"MAEU"}`+"\n" {
+				t.Errorf("first chunk = %q", line)
+			}
+		})
+	}
+}
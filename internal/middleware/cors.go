@@ -10,7 +10,7 @@ import (
 
 var originAllowlist = []string{"*"}
 
-var methodAllowlist = []string{"GET", "OPTIONS"}
+var methodAllowlist = []string{"GET", "POST", "OPTIONS"}
 
 func isPreflight(r *http.Request) bool {
 	return r.Method == "OPTIONS" &&
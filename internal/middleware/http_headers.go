@@ -10,9 +10,8 @@ func AddHeaders(next http.Handler) http.Handler {
 		headers := map[string]string{
 			"Connection":    "Keep-Alive",
 			"Cache-Control": "max-age=7200,stale-while-revalidate=86400",
-			"Content-Type":  "application/json",
-			//"Content-Type": "text/event-stream",
-			//"Context-Type":     "application/x-ndjson; charset=utf-8",
+			// the p2p stream overrides it with application/x-ndjson or text/event-stream when the client accepts them
+			"Content-Type":     "application/json",
 			"X-Correlation-ID": correlationID,
 		}
 		for key, value := range headers {
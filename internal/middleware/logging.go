@@ -49,6 +49,17 @@ func (e *extendWriter) WriteHeader(statusCode int) {
 	e.statusCode = statusCode
 }
 
+// Flush passes the flush through, otherwise the streamed responses would only reach the client once they are complete
+func (e *extendWriter) Flush() {
+	if flusher, ok := e.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (e *extendWriter) Unwrap() http.ResponseWriter {
+	return e.ResponseWriter
+}
+
 var (
 	customizeOnce   sync.Once
 	customFormatter *CustomLogFormatter
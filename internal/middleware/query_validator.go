@@ -5,27 +5,48 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/neckchi/schedulehub/internal/exceptions"
 	"github.com/neckchi/schedulehub/internal/schema"
+	"github.com/neckchi/schedulehub/internal/utils"
 	log "github.com/sirupsen/logrus"
 )
 
 type queryContextKey string
 
 const (
-	P2PQueryParamsKey queryContextKey = "p2PQueryParams"
-	VVQueryParamsKey  queryContextKey = "VVQueryParams"
+	P2PQueryParamsKey        queryContextKey = "p2PQueryParams"
+	VVQueryParamsKey         queryContextKey = "VVQueryParams"
+	P2PPlannerQueryParamsKey queryContextKey = "p2PPlannerQueryParams"
 )
 
+// excludedCarriers are only queried when the client asks for them explicitly.
+var excludedCarriers = map[schema.CarrierCode]bool{
+	schema.ANNU: true,
+	schema.CHNL: true,
+}
+
+// DefaultCarriers returns the active carriers that are queried when no SCAC is requested.
+func DefaultCarriers(scacConfig map[string]interface{}) []schema.CarrierCode {
+	activeCarrierCodes := make([]schema.CarrierCode, 0, len(scacConfig))
+	for carrierCode := range scacConfig {
+		if !excludedCarriers[schema.CarrierCode(carrierCode)] {
+			activeCarrierCodes = append(activeCarrierCodes, schema.CarrierCode(carrierCode))
+		}
+	}
+	return activeCarrierCodes
+}
+
 // allowedParams creates a map of valid JSON field tags for a given struct.
 func allowedParams(schemaStruct interface{}) map[string]struct{} {
 	val := reflect.ValueOf(schemaStruct)
 	jsonTags := make(map[string]struct{}, val.Type().NumField())
 	for i := 0; i < val.Type().NumField(); i++ {
-		if tag := val.Type().Field(i).Tag.Get("json"); tag != "" {
+		if tag := val.Type().Field(i).Tag.Get("json"); tag != "" && tag != "-" {
 			jsonTags[tag] = struct{}{}
 		}
 	}
@@ -58,6 +79,125 @@ func validateStruct(w http.ResponseWriter, params interface{}) bool {
 	return true
 }
 
+// activeCarriers checks the requested carriers against the config and falls back to the default carriers when none is requested.
+func activeCarriers(scacConfig map[string]interface{}, scacList []string) ([]schema.CarrierCode, error) {
+	if len(scacList) == 0 {
+		return DefaultCarriers(scacConfig), nil
+	}
+	activeCarrierCodes := make([]schema.CarrierCode, 0, len(scacList))
+	for _, carrierCode := range scacList {
+		if active, ok := scacConfig[carrierCode].(bool); !ok || !active {
+			return nil, fmt.Errorf("inactive or invalid SCAC: %s", carrierCode)
+		}
+		activeCarrierCodes = append(activeCarrierCodes, schema.CarrierCode(carrierCode))
+	}
+	return activeCarrierCodes, nil
+}
+
+// expandPorts turns pointFrom/pointTo into the list of ports. Each value may be a comma separated list of UN/LOCODEs or the name
+// of a port group defined in config.yaml
+func expandPorts(values []string, portGroups map[string]interface{}) []string {
+	ports := make([]string, 0, len(values))
+	for _, value := range values {
+		for _, port := range strings.Split(value, ",") {
+			port = strings.TrimSpace(port)
+			if group := utils.StringListSetting(portGroups, port); len(group) > 0 {
+				ports = append(ports, group...)
+			} else if port != "" {
+				ports = append(ports, port)
+			}
+		}
+	}
+	seen := make(map[string]bool, len(ports))
+	return slices.DeleteFunc(ports, func(port string) bool {
+		duplicated := seen[port]
+		seen[port] = true
+		return duplicated
+	})
+}
+
+// applyPorts fills the origins and destinations of the query. pointFrom/pointTo keep the first port so that the single port
+// validation still applies
+func applyPorts(requestParams *schema.QueryParams, from, to []string, settings map[string]interface{}) error {
+	portGroups := utils.MapSetting(settings, "portGroups")
+	origins, destinations := expandPorts(from, portGroups), expandPorts(to, portGroups)
+	if len(origins) > 0 {
+		requestParams.PointFrom = origins[0]
+	}
+	if len(destinations) > 0 {
+		requestParams.PointTo = destinations[0]
+	}
+	if len(origins) <= 1 && len(destinations) <= 1 {
+		return nil
+	}
+	requestParams.Origins, requestParams.Destinations = origins, destinations
+	if maxPortPairs := utils.IntSetting(settings, "maxPortPairs", 16); len(origins)*len(destinations) > maxPortPairs {
+		return fmt.Errorf("too many port pairs: %d origins x %d destinations exceeds %d", len(origins), len(destinations), maxPortPairs)
+	}
+	return nil
+}
+
+// PrepareP2PQuery runs the checks of a p2p request on a query built outside the query string(e.g. a batch lane or a GraphQL
+// field): the carriers are resolved against the config, pointFrom/pointTo may name a port group and the struct is validated
+func PrepareP2PQuery(requestParams *schema.QueryParams, settings map[string]interface{}) error {
+	scacConfig, ok := settings["activeCarriers"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("invalid schedule configuration")
+	}
+	scacList := make([]string, 0, len(requestParams.SCAC))
+	for _, scac := range requestParams.SCAC {
+		scacList = append(scacList, string(scac))
+	}
+	activeCarrierCodes, err := activeCarriers(scacConfig, scacList)
+	if err != nil {
+		return err
+	}
+	requestParams.SCAC = activeCarrierCodes
+	if err := applyPorts(requestParams, []string{requestParams.PointFrom}, []string{requestParams.PointTo}, settings); err != nil {
+		return err
+	}
+	return invalidField(schema.RequestValidate.Struct(requestParams))
+}
+
+// PrepareVesselVoyageQuery validates a vessel voyage query built outside the query string
+func PrepareVesselVoyageQuery(requestParams *schema.QueryParamsForVesselVoyage) error {
+	return invalidField(schema.RequestValidate.Struct(requestParams))
+}
+
+// invalidField words the first failing field like validateStruct does
+func invalidField(err error) error {
+	if errs, ok := err.(validator.ValidationErrors); ok && len(errs) > 0 {
+		return fmt.Errorf("invalid field value in '%s': %v", errs[0].Field(), errs[0].Value())
+	}
+	return err
+}
+
+// optionalInt parses a numeric filter, nil means the filter is not requested
+func optionalInt(query map[string][]string, key string) (*int, error) {
+	values, ok := query[key]
+	if !ok || len(values) == 0 || values[0] == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(values[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid field value in '%s': %v", key, values[0])
+	}
+	return &value, nil
+}
+
+// splitValues accepts both repeated parameters and comma separated values
+func splitValues(values []string) []string {
+	split := make([]string, 0, len(values))
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				split = append(split, item)
+			}
+		}
+	}
+	return split
+}
+
 // P2PQueryValidation validates query parameters for point-to-point requests.
 func P2PQueryValidation(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -66,9 +206,8 @@ func P2PQueryValidation(next http.Handler) http.Handler {
 			return
 		}
 
-		// Initialize active carriers
-		activeCarrierCodes := make([]schema.CarrierCode, 0, 15)
-		scacConfig, ok := r.Context().Value(ScheduleConfig).(map[string]interface{})["activeCarriers"].(map[string]interface{})
+		settings, _ := r.Context().Value(ScheduleConfig).(map[string]interface{})
+		scacConfig, ok := settings["activeCarriers"].(map[string]interface{})
 		if !ok {
 			err := fmt.Errorf("invalid schedule configuration")
 			log.Error(err)
@@ -76,33 +215,36 @@ func P2PQueryValidation(next http.Handler) http.Handler {
 			return
 		}
 
-		excludedCarriers := map[schema.CarrierCode]bool{
-			schema.ANNU: true,
-			schema.CHNL: true,
-		}
-
 		// Process SCAC parameters
-		if scacList := query["scac"]; len(scacList) > 0 {
-			for _, carrierCode := range scacList {
-				if active, ok := scacConfig[carrierCode].(bool); !ok || !active {
-					err := fmt.Errorf("inactive or invalid SCAC: %s", carrierCode)
-					log.Error(err)
-					exceptions.RequestErrorHandler(w, err)
-					return
-				}
-				activeCarrierCodes = append(activeCarrierCodes, schema.CarrierCode(carrierCode))
-			}
-		} else {
-			for carrierCode := range scacConfig {
-				if !excludedCarriers[schema.CarrierCode(carrierCode)] {
-					activeCarrierCodes = append(activeCarrierCodes, schema.CarrierCode(carrierCode))
-				}
-			}
+		activeCarrierCodes, err := activeCarriers(scacConfig, query["scac"])
+		if err != nil {
+			log.Error(err)
+			exceptions.RequestErrorHandler(w, err)
+			return
 		}
 
 		// Parse query parameters
 		searchRange, _ := strconv.Atoi(query.Get("searchRange"))
 		directOnly, _ := strconv.ParseBool(query.Get("directOnly"))
+		dedupe, _ := strconv.ParseBool(query.Get("dedupe"))
+		connections, _ := strconv.ParseBool(query.Get("connections"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		maxTransit, err := optionalInt(query, "maxTransitTime")
+		if err != nil {
+			log.Error(err)
+			exceptions.RequestErrorHandler(w, err)
+			return
+		}
+		maxTSP, err := optionalInt(query, "maxTransshipments")
+		if err != nil {
+			log.Error(err)
+			exceptions.RequestErrorHandler(w, err)
+			return
+		}
+		modes := make([]schema.TransportType, 0, len(query["transportModes"]))
+		for _, mode := range splitValues(query["transportModes"]) {
+			modes = append(modes, schema.TransportType(mode))
+		}
 		requestParams := schema.QueryParams{
 			PointFrom:     query.Get("pointFrom"),
 			PointTo:       query.Get("pointTo"),
@@ -114,17 +256,114 @@ func P2PQueryValidation(next http.Handler) http.Handler {
 			TSP:           query.Get("transhipmentPort"),
 			VesselIMO:     query.Get("vesselIMO"),
 			Service:       query.Get("service"),
+			ServiceName:   query.Get("serviceName"),
+			VesselName:    query.Get("vesselName"),
+			MaxTransit:    maxTransit,
+			MaxTSP:        maxTSP,
+			ExcludeTSP:    expandPorts(query["excludeTransshipment"], utils.MapSetting(settings, "portGroups")),
+			Modes:         modes,
+			CutoffAfter:   query.Get("cutoffAfter"),
+			SortBy:        schema.SortBy(query.Get("sortBy")),
+			Dedupe:        dedupe,
+			Connections:   connections,
+			Format:        query.Get("format"),
+			Layout:        query.Get("layout"),
+			Limit:         limit,
+			PageToken:     query.Get("pageToken"),
+			Fields:        splitValues(query["fields"]),
+		}
+		if err := applyPorts(&requestParams, query["pointFrom"], query["pointTo"], settings); err != nil {
+			log.Error(err)
+			exceptions.RequestErrorHandler(w, err)
+			return
 		}
 
 		if !validateStruct(w, requestParams) {
 			return
 		}
+		offset, err := requestParams.DecodePageToken()
+		if err != nil {
+			log.Error(err)
+			exceptions.RequestErrorHandler(w, err)
+			return
+		}
+		requestParams.Offset = offset
+		if requestParams.FieldSet, err = schema.ParseFieldSet(requestParams.Fields, schema.P2PSchedule{}); err != nil {
+			log.Error(err)
+			exceptions.RequestErrorHandler(w, err)
+			return
+		}
 
 		ctx := context.WithValue(r.Context(), P2PQueryParamsKey, requestParams)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// PlannerQueryValidation validates query parameters for the arrival deadline planner.
+func PlannerQueryValidation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if !validateQueryParams(w, query, schema.PlannerQueryParams{}) {
+			return
+		}
+		settings, _ := r.Context().Value(ScheduleConfig).(map[string]interface{})
+		scacConfig, ok := settings["activeCarriers"].(map[string]interface{})
+		if !ok {
+			err := fmt.Errorf("invalid schedule configuration")
+			log.Error(err)
+			exceptions.RequestErrorHandler(w, err)
+			return
+		}
+		activeCarrierCodes, err := activeCarriers(scacConfig, query["scac"])
+		if err != nil {
+			log.Error(err)
+			exceptions.RequestErrorHandler(w, err)
+			return
+		}
+
+		searchRange, _ := strconv.Atoi(query.Get("searchRange"))
+		directOnly, _ := strconv.ParseBool(query.Get("directOnly"))
+		maxTransit, err := optionalInt(query, "maxTransitTime")
+		if err != nil {
+			log.Error(err)
+			exceptions.RequestErrorHandler(w, err)
+			return
+		}
+		requestParams := schema.QueryParams{SCAC: activeCarrierCodes}
+		if err := applyPorts(&requestParams, query["pointFrom"], query["pointTo"], settings); err != nil {
+			log.Error(err)
+			exceptions.RequestErrorHandler(w, err)
+			return
+		}
+		plannerParams := schema.PlannerQueryParams{
+			PointFrom:      requestParams.PointFrom,
+			PointTo:        requestParams.PointTo,
+			ArriveBy:       query.Get("arriveBy"),
+			CargoReadyDate: query.Get("cargoReadyDate"),
+			SearchRange:    searchRange,
+			StartDateType:  schema.StartDateType(query.Get("startDateType")),
+			MaxTransit:     maxTransit,
+			SCAC:           activeCarrierCodes,
+			DirectOnly:     directOnly,
+			Origins:        requestParams.Origins,
+			Destinations:   requestParams.Destinations,
+		}
+
+		if !validateStruct(w, plannerParams) {
+			return
+		}
+		if plannerParams.CargoReadyDate > plannerParams.ArriveBy {
+			err := fmt.Errorf("cargoReadyDate %s is after arriveBy %s", plannerParams.CargoReadyDate, plannerParams.ArriveBy)
+			log.Error(err)
+			exceptions.RequestErrorHandler(w, err)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), P2PPlannerQueryParamsKey, plannerParams)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // VVQueryValidation validates query parameters for vessel-voyage requests.
 func VVQueryValidation(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -146,11 +385,20 @@ func VVQueryValidation(next http.Handler) http.Handler {
 			Voyage:    query.Get("voyageNum"),
 			StartDate: query.Get("startDate"),
 			DateRange: dateRange,
+			Format:    query.Get("format"),
+			Fields:    splitValues(query["fields"]),
 		}
 
 		if !validateStruct(w, requestParams) {
 			return
 		}
+		fieldSet, err := schema.ParseFieldSet(requestParams.Fields, schema.MasterVesselSchedule{})
+		if err != nil {
+			log.Error(err)
+			exceptions.RequestErrorHandler(w, err)
+			return
+		}
+		requestParams.FieldSet = fieldSet
 
 		ctx := context.WithValue(r.Context(), VVQueryParamsKey, requestParams)
 		next.ServeHTTP(w, r.WithContext(ctx))
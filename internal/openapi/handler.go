@@ -0,0 +1,47 @@
+package openapi
+
+import (
+	"embed"
+	"fmt"
+	"github.com/neckchi/schedulehub/internal/exceptions"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed swagger.html
+var swaggerPage []byte
+
+// swaggerAssets is the part of the swagger-ui dist the page loads, vendored so that /docs does not depend on a CDN
+//
+//go:embed swagger-ui
+var swaggerAssets embed.FS
+
+// DocumentHandler serves the generated document
+func DocumentHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		document, err := Spec()
+		if err != nil {
+			exceptions.InternalErrorHandler(w, fmt.Errorf("failed to generate the openapi document: %w", err))
+			return
+		}
+		_, _ = w.Write(document)
+	})
+}
+
+// SwaggerUIHandler serves the page rendering /openapi.json
+func SwaggerUIHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(swaggerPage)
+	})
+}
+
+// SwaggerAssetsHandler serves the embedded Swagger UI scripts and styles under /docs/
+func SwaggerAssetsHandler() http.Handler {
+	assets, _ := fs.Sub(swaggerAssets, "swagger-ui")
+	fileServer := http.StripPrefix("/docs/", http.FileServerFS(assets))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Del("Content-Type") // the file server derives it from the extension
+		fileServer.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,220 @@
+package openapi
+
+import (
+	"cmp"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// rule turns one validate tag(e.g. portCodeValidation) into schema keywords
+type rule func(s *Schema, param string)
+
+func pattern(expr string) rule {
+	return func(s *Schema, _ string) { s.Pattern = expr }
+}
+
+func format(name string) rule {
+	return func(s *Schema, _ string) { s.Format = name }
+}
+
+func minimum(s *Schema, param string) {
+	if value, err := strconv.ParseFloat(param, 64); err == nil && isNumber(s) {
+		s.Minimum = &value
+	}
+}
+
+func maximum(s *Schema, param string) {
+	value, err := strconv.Atoi(param)
+	switch {
+	case err != nil:
+	case isNumber(s):
+		limit := float64(value)
+		s.Maximum = &limit
+	case s.Type == "string":
+		s.MaxLength = &value
+	}
+}
+
+func isNumber(s *Schema) bool {
+	return s.Type == "integer" || s.Type == "number"
+}
+
+var commonRules = map[string]rule{
+	"oneof":                   func(s *Schema, param string) { s.Enum = strings.Fields(param) },
+	"min":                     minimum,
+	"gte":                     minimum,
+	"max":                     maximum,
+	"lte":                     maximum,
+	"portCodeValidation":      pattern(`^[A-Z]{2}[A-Z0-9]{3}$`),
+	"portOrCountryValidation": pattern(`^[A-Z]{2}([A-Z0-9]{3})?$`),
+	"isVesselFlag":            pattern(`^[A-Z]{2}$`),
+	"isValidIMO":              pattern(`^[0-9]{7}$`),
+	"imoValidation":           pattern(`^[0-9]{7}$`),
+	"isValidUTCDate":          format("date-time"),
+	"dcsaDateTime":            format("date-time"),
+	"unLocationCode":          pattern(`^[A-Z]{2}[A-Z2-9]{3}$`),
+}
+
+// the request and the response validators register isValidDate with different layouts
+var requestRules = withRules(map[string]rule{
+	"isValidDate":     format("date"),
+	"isValidDateTime": pattern(`^\d{4}-\d{2}-\d{2}(T\d{2}:\d{2}:\d{2})?$`),
+})
+
+var responseRules = withRules(map[string]rule{
+	"isValidDate": pattern(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}$`), // local time of the port, without offset
+})
+
+func withRules(rules map[string]rule) map[string]rule {
+	for name, r := range commonRules {
+		rules[name] = r
+	}
+	return rules
+}
+
+// field is a struct field as it shows up in the JSON document
+type field struct {
+	name     string
+	required bool
+	schema   *Schema
+	example  string
+}
+
+// generator builds the schemas out of the Go types. The named structs end up under components/schemas and are referenced
+type generator struct {
+	schemas map[string]*Schema
+}
+
+func newGenerator() *generator {
+	return &generator{schemas: make(map[string]*Schema)}
+}
+
+// jsonName follows encoding/json, an empty name means the field is not serialized
+func jsonName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if tag == "-" || !f.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	return strings.TrimSpace(cmp.Or(name, f.Name))
+}
+
+// fields lists the serialized fields of the struct with their validate, description and example tags applied. The fields of an
+// embedded struct are promoted as encoding/json does
+func (g *generator) fields(t reflect.Type, rules map[string]rule) []field {
+	fields := make([]field, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if embedded := f.Type; f.Anonymous && f.Tag.Get("json") == "" {
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fields = append(fields, g.fields(embedded, rules)...)
+				continue
+			}
+		}
+		name := jsonName(f)
+		if name == "" {
+			continue
+		}
+		s := g.schemaOf(f.Type, rules)
+		required := g.applyValidate(s, f.Tag.Get("validate"), rules)
+		if description := f.Tag.Get("description"); description != "" {
+			if s.Ref != "" {
+				s = &Schema{Ref: s.Ref, Description: description} // 3.1 allows siblings next to $ref
+			} else {
+				s.Description = description
+			}
+		}
+		example := f.Tag.Get("example")
+		if example != "" && s.Ref == "" {
+			s.Examples = []string{example}
+		}
+		fields = append(fields, field{name: name, required: required, schema: s, example: example})
+	}
+	return fields
+}
+
+// applyValidate maps the validate tag on the schema, the rules after dive apply to the items. It reports whether the field is required
+func (g *generator) applyValidate(s *Schema, tag string, rules map[string]rule) bool {
+	required, target := false, s
+	for _, item := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(item), "=")
+		switch {
+		case name == "required" && target == s:
+			required = true
+		case name == "dive" && target.Items != nil:
+			target = target.Items
+		case rules[name] != nil && target.Ref == "":
+			rules[name](target, param)
+		}
+	}
+	return required
+}
+
+func (g *generator) schemaOf(t reflect.Type, rules map[string]rule) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem(), rules)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem(), rules)}
+	case reflect.Struct:
+		return g.component(t, rules)
+	}
+	return &Schema{} // interface{} takes any value
+}
+
+// component registers the struct once under its type name and returns the reference
+func (g *generator) component(t reflect.Type, rules map[string]rule) *Schema {
+	ref := &Schema{Ref: "#/components/schemas/" + t.Name()}
+	if _, ok := g.schemas[t.Name()]; ok {
+		return ref
+	}
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.schemas[t.Name()] = s // registered before the fields so that recursive types terminate
+	for _, f := range g.fields(t, rules) {
+		s.Properties[f.name] = f.schema
+		if f.required {
+			s.Required = append(s.Required, f.name)
+		}
+	}
+	return ref
+}
+
+// Ref adds the response type to the components and references it
+func (g *generator) Ref(v any) *Schema {
+	return g.component(reflect.TypeOf(v), responseRules)
+}
+
+// Body adds the request body type to the components, its validate tags are read like the ones of the query parameters
+func (g *generator) Body(v any) *Schema {
+	return g.component(reflect.TypeOf(v), requestRules)
+}
+
+// QueryParameters turns the fields of the request struct into query parameters, the lists are sent as repeated parameters
+func (g *generator) QueryParameters(v any) []Parameter {
+	fields := g.fields(reflect.TypeOf(v), requestRules)
+	parameters := make([]Parameter, 0, len(fields))
+	for _, f := range fields {
+		description := f.schema.Description
+		f.schema.Description, f.schema.Examples = "", nil
+		parameters = append(parameters, Parameter{
+			Name: f.name, In: "query", Description: description, Required: f.required, Schema: f.schema, Example: f.example,
+		})
+	}
+	return parameters
+}
@@ -0,0 +1,154 @@
+package openapi
+
+import (
+	"encoding/json"
+	"github.com/neckchi/schedulehub/internal/exceptions"
+	"github.com/neckchi/schedulehub/internal/schema"
+	"sync"
+)
+
+// server points at the listener of the api, the host is left to the client
+func server(port string) []Server {
+	return []Server{{URL: "http://{host}:" + port, Variables: map[string]ServerVariable{"host": {Default: "localhost"}}}}
+}
+
+func jsonContent(s *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: s}}
+}
+
+// errorResponses are written by the exceptions package for every api
+func errorResponses(g *generator, responses map[string]*Response) map[string]*Response {
+	errorModel := jsonContent(g.Ref(exceptions.ErrorResponse{}))
+	responses["400"] = &Response{Description: "Invalid or unknown query parameter", Content: errorModel}
+	responses["500"] = &Response{Description: "Unexpected error", Content: errorModel}
+	return responses
+}
+
+// P2PEnvelope is the JSON body of /schedules/p2p. The stream writes it piece by piece, so it only exists for the document
+type P2PEnvelope struct {
+	schema.Product
+	NextPageToken string `json:"nextPageToken,omitempty" description:"Token of the next page, only when limit cut the schedules short"`
+	Message       string `json:"message,omitempty" description:"Set when no carrier has a schedule for the route"`
+}
+
+// dcsaArray is the body of format=dcsa, the documents that do not match the spec are counted in the Dcsa-Dropped trailer
+func dcsaArray(g *generator, v any, description string) *Schema {
+	return &Schema{Type: "array", Items: g.Ref(v), Description: description}
+}
+
+// notModified answers the If-None-Match of the buffered responses carrying an ETag
+var notModified = &Response{Description: "The client holds the current representation(If-None-Match matched the ETag)"}
+
+// Build generates the document out of the request and response types of the schema package
+func Build() *Document {
+	g := newGenerator()
+	text := &Schema{Type: "string"}
+	p2p := &Operation{
+		OperationID: "searchP2PSchedules",
+		Summary:     "Point to point schedules of the requested carriers",
+		Description: "The schedules are streamed as the carriers answer. The format parameter or the Accept header picks the output",
+		Tags:        []string{"p2p"},
+		Parameters:  g.QueryParameters(schema.QueryParams{}),
+		Responses: errorResponses(g, map[string]*Response{
+			"200": {Description: "Schedules of the port pair", Content: map[string]MediaType{
+				"application/json": {Schema: &Schema{OneOf: []*Schema{
+					g.Ref(P2PEnvelope{}),
+					dcsaArray(g, schema.DCSAPointToPoint{}, "format=dcsa, DCSA Commercial Schedules point to point routes"),
+				}}},
+				"application/x-ndjson": {Schema: &Schema{Type: "string", Description: "one P2PSchedule per line and a StreamTrailer line"}},
+				"text/event-stream":    {Schema: &Schema{Type: "string", Description: "schedule, carrier-status and done events"}},
+				"text/csv":             {Schema: text},
+				xlsxContentType:        {Schema: &Schema{Type: "string", Format: "binary"}},
+			}},
+			"304": notModified,
+		}),
+	}
+	g.Ref(schema.StreamTrailer{})
+	g.Ref(schema.CarrierStatus{})
+	planner := &Operation{
+		OperationID: "planP2PSchedules",
+		Summary:     "Schedules ranked by how well they make the arrival deadline",
+		Description: "The feasible schedules come first, the others carry the reasons they miss the deadline",
+		Tags:        []string{"p2p"},
+		Parameters:  g.QueryParameters(schema.PlannerQueryParams{}),
+		Responses: errorResponses(g, map[string]*Response{
+			"200": {Description: "Planned schedules of the port pair", Content: jsonContent(g.Ref(schema.PlannerResult{}))},
+			"304": notModified,
+		}),
+	}
+	batch := &Operation{
+		OperationID: "batchP2PSchedules",
+		Summary:     "Point to point schedules of several lanes in one request",
+		Description: "Every lane takes the parameters of /schedules/p2p except limit, pageToken and fields",
+		Tags:        []string{"p2p"},
+		RequestBody: &RequestBody{Required: true, Content: jsonContent(g.Body(schema.BatchQuery{}))},
+		Responses: errorResponses(g, map[string]*Response{
+			"200": {Description: "Schedules of the lanes as they come", Content: map[string]MediaType{
+				"application/x-ndjson": {Schema: &Schema{Type: "string", Description: "one BatchScheduleLine per line, a closing line per lane"}},
+			}},
+		}),
+	}
+	g.Ref(schema.BatchScheduleLine{})
+	lookup := &Operation{
+		OperationID: "getP2PSchedule",
+		Summary:     "Current version of a schedule returned by an earlier search",
+		Tags:        []string{"p2p"},
+		Parameters: []Parameter{{
+			Name: "scheduleId", In: "path", Required: true, Schema: &Schema{Type: "string", Pattern: `^[0-9a-f]{32}$`},
+			Description: "scheduleId of the schedule",
+		}},
+		Responses: errorResponses(g, map[string]*Response{
+			"200": {Description: "The schedule as the carrier offers it now", Content: jsonContent(g.Ref(schema.P2PSchedule{}))},
+			"304": notModified,
+			"404": {Description: "Unknown schedule or no longer offered by the carrier", Content: jsonContent(g.Ref(exceptions.ErrorResponse{}))},
+		}),
+	}
+	mvs := &Operation{
+		OperationID: "getMasterVoyage",
+		Summary:     "Master vessel voyage of the requested vessel",
+		Tags:        []string{"mastervoyage"},
+		Parameters:  g.QueryParameters(schema.QueryParamsForVesselVoyage{}),
+		Responses: errorResponses(g, map[string]*Response{
+			"200": {Description: "Port calls of the vessel per carrier", Content: map[string]MediaType{
+				"application/json": {Schema: &Schema{OneOf: []*Schema{
+					g.Ref(schema.MasterVesselScheduleList{}),
+					dcsaArray(g, schema.DCSAServiceSchedule{}, "format=dcsa, DCSA Operational Vessel Schedules services"),
+				}}},
+				"text/calendar": {Schema: text},
+			}},
+		}),
+	}
+	readConfig := &Operation{
+		OperationID: "readConfig",
+		Summary:     "App config of the service, the base config when the service has none",
+		Tags:        []string{"config"},
+		Parameters:  []Parameter{{Name: "serviceName", In: "path", Required: true, Schema: &Schema{Type: "string"}, Example: "service"}},
+		Responses: errorResponses(g, map[string]*Response{
+			"200": {Description: "Config of the service", Content: jsonContent(&Schema{Type: "object", AdditionalProperties: &Schema{}})},
+		}),
+	}
+	return &Document{
+		OpenAPI: "3.1.0",
+		Info: Info{
+			Title:       "Schedule Hub",
+			Version:     "1.0.0",
+			Description: "Point to point schedules and master vessel voyages of the ocean carriers",
+		},
+		Paths: map[string]*PathItem{
+			"/schedules/p2p":              {Get: p2p, Servers: server("8008")},
+			"/schedules/p2p/planner":      {Get: planner, Servers: server("8008")},
+			"/schedules/p2p/batch":        {Post: batch, Servers: server("8008")},
+			"/schedules/p2p/{scheduleId}": {Get: lookup, Servers: server("8008")},
+			"/schedules/mastervoyage":     {Get: mvs, Servers: server("8007")},
+			"/read/{serviceName}":         {Get: readConfig, Servers: server("8004")},
+		},
+		Components: Components{Schemas: g.schemas},
+	}
+}
+
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// Spec is generated once at startup, the types do not change while the service runs
+var Spec = sync.OnceValues(func() ([]byte, error) {
+	return json.MarshalIndent(Build(), "", "  ")
+})
@@ -0,0 +1,94 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBuildPaths(t *testing.T) {
+	document := Build()
+	tests := []struct {
+		path   string
+		method string
+		status []string
+	}{
+		{path: "/schedules/p2p", method: http.MethodGet, status: []string{"200", "304", "400", "500"}},
+		{path: "/schedules/p2p/planner", method: http.MethodGet, status: []string{"200", "304", "400", "500"}},
+		{path: "/schedules/p2p/batch", method: http.MethodPost, status: []string{"200", "400", "500"}},
+		{path: "/schedules/p2p/{scheduleId}", method: http.MethodGet, status: []string{"200", "304", "404"}},
+		{path: "/schedules/mastervoyage", method: http.MethodGet, status: []string{"200", "400", "500"}},
+		{path: "/read/{serviceName}", method: http.MethodGet, status: []string{"200"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			item, ok := document.Paths[tt.path]
+			if !ok {
+				t.Fatalf("path %s is missing", tt.path)
+			}
+			operation := item.Get
+			if tt.method == http.MethodPost {
+				operation = item.Post
+			}
+			if operation == nil {
+				t.Fatalf("%s %s is missing", tt.method, tt.path)
+			}
+			for _, status := range tt.status {
+				if operation.Responses[status] == nil {
+					t.Errorf("response %s is missing", status)
+				}
+			}
+		})
+	}
+	if document.Paths["/schedules/p2p/batch"].Post.RequestBody == nil {
+		t.Error("the batch has no request body")
+	}
+}
+
+func TestBuildDCSAResponses(t *testing.T) {
+	document := Build()
+	for path, component := range map[string]string{
+		"/schedules/p2p":          "#/components/schemas/DCSAPointToPoint",
+		"/schedules/mastervoyage": "#/components/schemas/DCSAServiceSchedule",
+	} {
+		found := false
+		for _, alternative := range document.Paths[path].Get.Responses["200"].Content["application/json"].Schema.OneOf {
+			found = found || (alternative.Items != nil && alternative.Items.Ref == component)
+		}
+		if !found {
+			t.Errorf("%s does not document the format=dcsa array of %s", path, component)
+		}
+	}
+	envelope := document.Components.Schemas["P2PEnvelope"]
+	for _, property := range []string{"origin", "destination", "schedules", "nextPageToken", "message"} {
+		if envelope.Properties[property] == nil {
+			t.Errorf("P2PEnvelope has no %s", property)
+		}
+	}
+}
+
+func TestSwaggerAssetsHandler(t *testing.T) {
+	tests := []struct {
+		path        string
+		status      int
+		contentType string
+	}{
+		{path: "/docs/swagger-ui-bundle.js", status: http.StatusOK, contentType: "text/javascript"},
+		{path: "/docs/swagger-ui.css", status: http.StatusOK, contentType: "text/css"},
+		{path: "/docs/missing.js", status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rsp := httptest.NewRecorder()
+			rsp.Header().Set("Content-Type", "application/json") // set by the AddHeaders middleware
+			SwaggerAssetsHandler().ServeHTTP(rsp, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rsp.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rsp.Code, tt.status)
+			}
+			if tt.contentType != "" && !strings.HasPrefix(rsp.Header().Get("Content-Type"), tt.contentType) {
+				t.Errorf("Content-Type = %q, want %s", rsp.Header().Get("Content-Type"), tt.contentType)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/neckchi/schedulehub/configs/domain"
 	"github.com/neckchi/schedulehub/configs/service"
 	"github.com/neckchi/schedulehub/internal/middleware"
+	"github.com/neckchi/schedulehub/internal/openapi"
 	log "github.com/sirupsen/logrus"
 	"net/http"
 	"os"
@@ -38,5 +39,8 @@ func AppConfigRouter() http.Handler {
 	appConfigRouter := http.NewServeMux()
 	rc := middlewareStackForrc(c.ReadConfig())
 	appConfigRouter.Handle("GET /read/{serviceName}", rc)
+	appConfigRouter.Handle("GET /openapi.json", middlewareStackForrc(openapi.DocumentHandler()))
+	appConfigRouter.Handle("GET /docs", middlewareStackForrc(openapi.SwaggerUIHandler()))
+	appConfigRouter.Handle("GET /docs/", middlewareStackForrc(openapi.SwaggerAssetsHandler()))
 	return appConfigRouter
 }
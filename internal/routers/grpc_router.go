@@ -0,0 +1,19 @@
+package routers
+
+import (
+	"github.com/neckchi/schedulehub/internal/grpc_server"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"net/http"
+)
+
+// GRPCRouter serves the gRPC services over cleartext HTTP/2, the internal services call it without TLS
+func GRPCRouter() http.Handler {
+	svc, err := sharedServices()
+	if err != nil {
+		log.WithError(err).Fatal("Failed to initialize dependencies")
+		return nil
+	}
+	return h2c.NewHandler(grpc_server.NewServer(svc.p2p, svc.voyage), &http2.Server{})
+}
@@ -1,7 +1,6 @@
 package routers
 
 import (
-	"github.com/neckchi/schedulehub/internal/dependencies"
 	"github.com/neckchi/schedulehub/internal/handlers/mvs_handler"
 	"github.com/neckchi/schedulehub/internal/middleware"
 	log "github.com/sirupsen/logrus"
@@ -9,7 +8,7 @@ import (
 )
 
 func VoyageRouter() http.Handler {
-	deps, err := dependencies.NewDependencies()
+	svc, err := sharedServices()
 	if err != nil {
 		log.WithError(err).Fatal("Failed to initialize dependencies")
 		return nil
@@ -19,22 +18,15 @@ func VoyageRouter() http.Handler {
 		middleware.CheckCORS,
 		middleware.AddCorrelationID,
 		middleware.AddHeaders,
+		middleware.Compress,
 		middleware.GetAppConfig("service.registry.mvs"),
 		middleware.Logging,
 		middleware.VVQueryValidation,
 	)
 
-	voyageService := mvs_handler.NewVoyageService(
-		deps.HTTPClient,
-		deps.EnvManager,
-		deps.VesselSvc,
-		deps.OracleDB,
-		deps.RedisDB,
-	)
-
 	voyageRouter := http.NewServeMux()
 
-	vv := middlewareStackForMVS(mvs_handler.VoyageHandler(voyageService))
+	vv := middlewareStackForMVS(mvs_handler.VoyageHandler(svc.voyage))
 	voyageRouter.Handle("GET /schedules/mastervoyage", vv)
 	return voyageRouter
 }
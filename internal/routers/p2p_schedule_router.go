@@ -1,7 +1,8 @@
 package routers
 
 import (
-	"github.com/neckchi/schedulehub/internal/dependencies"
+	"context"
+	"github.com/neckchi/schedulehub/internal/handlers/graphql_handler"
 	"github.com/neckchi/schedulehub/internal/handlers/p2p_schedule_handler"
 	"github.com/neckchi/schedulehub/internal/middleware"
 	log "github.com/sirupsen/logrus"
@@ -9,7 +10,7 @@ import (
 )
 
 func ScheduleRouter() http.Handler {
-	deps, err := dependencies.NewDependencies()
+	svc, err := sharedServices()
 	if err != nil {
 		log.WithError(err).Fatal("Failed to initialize dependencies")
 		return nil
@@ -19,20 +20,81 @@ func ScheduleRouter() http.Handler {
 		middleware.CheckCORS,
 		middleware.AddCorrelationID,
 		middleware.AddHeaders,
+		middleware.Compress,
 		middleware.GetAppConfig("service.registry.p2p"),
 		middleware.Logging,
 		middleware.P2PQueryValidation,
 	)
-	p2pService := p2p_schedule_handler.NewP2PScheduleService(
+	deps, p2pService := svc.deps, svc.p2p
+	prewarmer := p2p_schedule_handler.NewPrewarmer(
 		deps.HTTPClient,
 		deps.EnvManager,
 		deps.P2PSvc,
 		deps.RedisDB,
+		svc.lanes,
+	)
+	go prewarmer.Run(context.Background())
+	middlewareStackForPlanner := middleware.CreateStack(
+		middleware.Recovery,
+		middleware.CheckCORS,
+		middleware.AddCorrelationID,
+		middleware.AddHeaders,
+		middleware.Compress,
+		middleware.GetAppConfig("service.registry.p2p"),
+		middleware.Logging,
+		middleware.PlannerQueryValidation,
+	)
+	middlewareStackForBatch := middleware.CreateStack(
+		middleware.Recovery,
+		middleware.CheckCORS,
+		middleware.AddCorrelationID,
+		middleware.AddHeaders,
+		middleware.Compress,
+		middleware.GetAppConfig("service.registry.p2p"),
+		middleware.Logging,
+		middleware.P2PBatchValidation,
+	)
+	middlewareStackForLookup := middleware.CreateStack(
+		middleware.Recovery,
+		middleware.CheckCORS,
+		middleware.AddCorrelationID,
+		middleware.AddHeaders,
+		middleware.Compress,
+		middleware.Logging,
+	)
+	middlewareStackForPrewarm := middleware.CreateStack(
+		middleware.Recovery,
+		middleware.CheckCORS,
+		middleware.AddCorrelationID,
+		middleware.AddHeaders,
+		middleware.Compress,
+		middleware.Logging,
+	)
+	middlewareStackForGraphQL := middleware.CreateStack(
+		middleware.Recovery,
+		middleware.CheckCORS,
+		middleware.AddCorrelationID,
+		middleware.AddHeaders,
+		middleware.Compress,
+		middleware.GetAppConfig("service.registry.p2p"),
+		middleware.Logging,
 	)
 
 	p2pScheduleRouter := http.NewServeMux()
 	sh := middlewareStackForp2p(p2p_schedule_handler.P2PScheduleHandler(p2pService))
 	p2pScheduleRouter.Handle("GET /schedules/p2p", sh)
+	sp := middlewareStackForPlanner(p2p_schedule_handler.PlannerHandler(p2pService))
+	p2pScheduleRouter.Handle("GET /schedules/p2p/planner", sp)
+	sb := middlewareStackForBatch(p2p_schedule_handler.P2PBatchHandler(p2pService))
+	p2pScheduleRouter.Handle("POST /schedules/p2p/batch", sb)
+	sl := middlewareStackForLookup(p2p_schedule_handler.ScheduleLookupHandler(p2pService))
+	p2pScheduleRouter.Handle("GET /schedules/p2p/{scheduleId}", sl)
+	ps := middlewareStackForPrewarm(p2p_schedule_handler.PrewarmStatusHandler(prewarmer))
+	p2pScheduleRouter.Handle("GET /schedules/prewarm/status", ps)
+	// the vessel voyages of the legs are resolved in process, not through the mvs listener
+	gq := middlewareStackForGraphQL(graphql_handler.GraphQLHandler(graphql_handler.NewSchema(p2pService, svc.voyage)))
+	p2pScheduleRouter.Handle("POST /graphql", gq)
+	p2pScheduleRouter.Handle("GET /graphql", gq)
 	//HealthCheck
 
 	return p2pScheduleRouter
@@ -0,0 +1,43 @@
+package routers
+
+import (
+	"github.com/neckchi/schedulehub/internal/dependencies"
+	"github.com/neckchi/schedulehub/internal/handlers/mvs_handler"
+	"github.com/neckchi/schedulehub/internal/handlers/p2p_schedule_handler"
+	"sync"
+)
+
+// services are shared by the REST, GraphQL and gRPC listeners. A lane searched over gRPC lands in the same lane history the
+// prewarmer reads
+type services struct {
+	deps   *dependencies.Dependencies
+	lanes  *p2p_schedule_handler.LaneHistory
+	p2p    *p2p_schedule_handler.P2PScheduleService
+	voyage *mvs_handler.VoyageService
+}
+
+var sharedServices = sync.OnceValues(func() (*services, error) {
+	deps, err := dependencies.NewDependencies()
+	if err != nil {
+		return nil, err
+	}
+	lanes := p2p_schedule_handler.NewLaneHistory()
+	return &services{
+		deps:  deps,
+		lanes: lanes,
+		p2p: p2p_schedule_handler.NewP2PScheduleService(
+			deps.HTTPClient,
+			deps.EnvManager,
+			deps.P2PSvc,
+			deps.RedisDB,
+			lanes,
+		),
+		voyage: mvs_handler.NewVoyageService(
+			deps.HTTPClient,
+			deps.EnvManager,
+			deps.VesselSvc,
+			deps.OracleDB,
+			deps.RedisDB,
+		),
+	}, nil
+})
@@ -0,0 +1,314 @@
+package schema
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DCSADroppedTrailer is the HTTP trailer counting the schedules left out of a DCSA response because they do not match the spec
+const DCSADroppedTrailer = "Dcsa-Dropped"
+
+// DCSAValidate checks the DCSA documents against the shape of the Commercial Schedules(CS 1.0) and Operational Vessel
+// Schedules(OVS 3.0) specs before they are sent to the partners
+var DCSAValidate *validator.Validate
+
+func init() {
+	DCSAValidate = validator.New(validator.WithRequiredStructEnabled())
+	errDateTime := DCSAValidate.RegisterValidation("dcsaDateTime", func(fl validator.FieldLevel) bool {
+		_, err := time.Parse(time.RFC3339, fl.Field().String())
+		return err == nil
+	})
+	if errDateTime != nil {
+		return
+	}
+	errLocode := DCSAValidate.RegisterValidation("unLocationCode", func(fl validator.FieldLevel) bool {
+		return regexp.MustCompile(`^[A-Z]{2}[A-Z2-9]{3}$`).MatchString(fl.Field().String())
+	})
+	if errLocode != nil {
+		return
+	}
+}
+
+type DCSALocation struct {
+	LocationName   string `json:"locationName,omitempty" validate:"omitempty,max=100"`
+	LocationType   string `json:"locationType,omitempty" validate:"omitempty,oneof=UNLO FACI"`
+	UNLocationCode string `json:"UNLocationCode" validate:"required,unLocationCode"`
+	FacilityCode   string `json:"facilitySMDGCode,omitempty" validate:"omitempty,max=6"`
+}
+
+// DCSA Commercial Schedules, point to point routes
+
+type DCSAPlace struct {
+	FacilityTypeCode string       `json:"facilityTypeCode" validate:"required,oneof=POTE PBPL BRTH"`
+	Location         DCSALocation `json:"location"`
+	DateTime         string       `json:"dateTime" validate:"required,dcsaDateTime"`
+}
+
+type DCSACutOffTime struct {
+	CutOffDateTimeCode string `json:"cutOffDateTimeCode" validate:"required,oneof=DCO VCO FCO LCO ECP EFC"`
+	CutOffDateTime     string `json:"cutOffDateTime" validate:"required,dcsaDateTime"`
+}
+
+type DCSAVessel struct {
+	VesselIMONumber string `json:"vesselIMONumber,omitempty" validate:"omitempty,len=7,numeric"`
+	Name            string `json:"name,omitempty" validate:"omitempty,max=50"`
+}
+
+type DCSAServicePartner struct {
+	CarrierCode               string `json:"carrierCode" validate:"required,max=4"`
+	CarrierCodeListProvider   string `json:"carrierCodeListProvider" validate:"required,oneof=SMDG NMFTA"`
+	CarrierServiceName        string `json:"carrierServiceName,omitempty" validate:"omitempty,max=50"`
+	CarrierServiceCode        string `json:"carrierServiceCode,omitempty" validate:"omitempty,max=11"`
+	CarrierImportVoyageNumber string `json:"carrierImportVoyageNumber,omitempty" validate:"omitempty,max=50"`
+	CarrierExportVoyageNumber string `json:"carrierExportVoyageNumber" validate:"required,max=50"`
+}
+
+type DCSATransport struct {
+	ModeOfTransport string               `json:"modeOfTransport" validate:"required,oneof=VESSEL RAIL TRUCK BARGE RAIL_TRUCK BARGE_TRUCK BARGE_RAIL MULTIMODAL"`
+	ServicePartners []DCSAServicePartner `json:"servicePartners,omitempty" validate:"dive"`
+	Vessel          *DCSAVessel          `json:"vessel,omitempty" validate:"omitempty"`
+}
+
+type DCSALeg struct {
+	SequenceNumber int           `json:"sequenceNumber" validate:"required,gte=1"`
+	Transport      DCSATransport `json:"transport"`
+	Departure      DCSAPlace     `json:"departure"`
+	Arrival        DCSAPlace     `json:"arrival"`
+}
+
+type DCSAPointToPoint struct {
+	PlaceOfReceipt            DCSAPlace        `json:"placeOfReceipt"`
+	PlaceOfDelivery           DCSAPlace        `json:"placeOfDelivery"`
+	ReceiptTypeAtOrigin       string           `json:"receiptTypeAtOrigin" validate:"required,oneof=CY SD CFS"`
+	DeliveryTypeAtDestination string           `json:"deliveryTypeAtDestination" validate:"required,oneof=CY SD CFS"`
+	CutOffTimes               []DCSACutOffTime `json:"cutOffTimes,omitempty" validate:"dive"`
+	TransitTime               int              `json:"transitTime" validate:"gte=0"`
+	Legs                      []DCSALeg        `json:"legs" validate:"required,min=1,dive"`
+}
+
+// DCSA Operational Vessel Schedules, service/vessel/transport calls
+
+type DCSATimestamp struct {
+	EventTypeCode       string `json:"eventTypeCode" validate:"required,oneof=ARRI DEPA"`
+	EventClassifierCode string `json:"eventClassifierCode" validate:"required,oneof=PLN EST ACT"`
+	EventDateTime       string `json:"eventDateTime" validate:"required,dcsaDateTime"`
+}
+
+type DCSATransportCall struct {
+	TransportCallReference    string          `json:"transportCallReference" validate:"required,max=100"`
+	CarrierImportVoyageNumber string          `json:"carrierImportVoyageNumber,omitempty" validate:"omitempty,max=50"`
+	CarrierExportVoyageNumber string          `json:"carrierExportVoyageNumber,omitempty" validate:"omitempty,max=50"`
+	Location                  DCSALocation    `json:"location"`
+	Timestamps                []DCSATimestamp `json:"timestamps" validate:"required,min=1,dive"`
+}
+
+type DCSAVesselSchedule struct {
+	VesselOperatorSMDGLinerCode string              `json:"vesselOperatorSMDGLinerCode,omitempty" validate:"omitempty,max=10"`
+	VesselIMONumber             string              `json:"vesselIMONumber" validate:"required,len=7,numeric"`
+	VesselName                  string              `json:"vesselName,omitempty" validate:"omitempty,max=35"`
+	IsDummyVessel               bool                `json:"isDummyVessel"`
+	TransportCalls              []DCSATransportCall `json:"transportCalls" validate:"required,min=1,dive"`
+}
+
+type DCSAServiceSchedule struct {
+	CarrierServiceName string               `json:"carrierServiceName,omitempty" validate:"omitempty,max=50"`
+	CarrierServiceCode string               `json:"carrierServiceCode,omitempty" validate:"omitempty,max=11"`
+	VesselSchedules    []DCSAVesselSchedule `json:"vesselSchedules" validate:"required,min=1,dive"`
+}
+
+var dcsaModeOfTransport = map[TransportType]string{
+	Vessel:     "VESSEL",
+	Feeder:     "VESSEL",
+	Barge:      "BARGE",
+	Rail:       "RAIL",
+	Truck:      "TRUCK",
+	Road:       "TRUCK",
+	Truckrail:  "RAIL_TRUCK",
+	Roadrail:   "RAIL_TRUCK",
+	Intermodal: "MULTIMODAL",
+}
+
+// smdgLinerCodes maps the SCAC to the SMDG liner code the OVS spec identifies the vessel operator with
+var smdgLinerCodes = map[string]string{
+	"ANNU": "ANL",
+	"APLU": "APL",
+	"CHNL": "CNC",
+	"CMDU": "CMA",
+	"COSU": "COS",
+	"HDMU": "HMM",
+	"HLCU": "HLC",
+	"MAEI": "MSK",
+	"MAEU": "MSK",
+	"MSCU": "MSC",
+	"ONEY": "ONE",
+	"OOLU": "OOL",
+	"ZIMU": "ZIM",
+}
+
+// ZoneLocation accepts either an IANA zone or a UTC offset(e.g. +08:00)
+func ZoneLocation(zone string) *time.Location {
+	if zone == "" {
+		return nil
+	}
+	if offset, err := time.Parse("-07:00", zone); err == nil {
+		_, seconds := offset.Zone()
+		return time.FixedZone(zone, seconds)
+	}
+	if location, err := time.LoadLocation(zone); err == nil {
+		return location
+	}
+	return nil
+}
+
+// dcsaDateTime writes the local time with the offset of the port, DCSA only takes RFC 3339 date-times. The UTC time stands in when
+// the zone of the port is unknown, a time with neither is left as it is and fails the validation
+func dcsaDateTime(local, zone, utc string) string {
+	if location := ZoneLocation(zone); location != nil {
+		if localTime, err := time.ParseInLocation("2006-01-02T15:04:05", local, location); err == nil {
+			return localTime.Format(time.RFC3339)
+		}
+	}
+	return cmp.Or(utc, local)
+}
+
+// dcsaPoint falls back to the port code of the schedule when the carrier left out the point of the leg
+func dcsaPoint(point *PointBase, portCode string, local, utc string) DCSAPlace {
+	place := DCSAPlace{FacilityTypeCode: "POTE", Location: DCSALocation{LocationType: "UNLO", UNLocationCode: portCode}, DateTime: cmp.Or(utc, local)}
+	if point != nil {
+		place.Location.LocationName, place.Location.UNLocationCode = point.LocationName, point.LocationCode
+		place.DateTime = dcsaDateTime(local, point.TimeZone, utc)
+	}
+	return place
+}
+
+// NewDCSAPointToPoint maps the normalized schedule onto a DCSA point to point route
+func NewDCSAPointToPoint(schedule *P2PSchedule) DCSAPointToPoint {
+	route := DCSAPointToPoint{
+		PlaceOfReceipt:            dcsaPoint(nil, schedule.PointFrom, schedule.Etd, schedule.EtdUTC),
+		PlaceOfDelivery:           dcsaPoint(nil, schedule.PointTo, schedule.Eta, schedule.EtaUTC),
+		ReceiptTypeAtOrigin:       "CY",
+		DeliveryTypeAtDestination: "CY",
+		TransitTime:               schedule.TransitTime,
+		Legs:                      make([]DCSALeg, 0, len(schedule.Legs)),
+	}
+	if len(schedule.Legs) > 0 {
+		first := schedule.Legs[0]
+		route.PlaceOfReceipt = dcsaPoint(first.PointFrom, schedule.PointFrom, schedule.Etd, schedule.EtdUTC)
+		route.PlaceOfDelivery = dcsaPoint(schedule.Legs[len(schedule.Legs)-1].PointTo, schedule.PointTo, schedule.Eta, schedule.EtaUTC)
+		if first.Cutoffs != nil {
+			var zone string
+			if first.PointFrom != nil {
+				zone = first.PointFrom.TimeZone
+			}
+			for _, cutoff := range []DCSACutOffTime{
+				{CutOffDateTimeCode: "DCO", CutOffDateTime: dcsaDateTime(first.Cutoffs.DocCutoffDate, zone, "")},
+				{CutOffDateTimeCode: "FCO", CutOffDateTime: dcsaDateTime(first.Cutoffs.CyCutoffDate, zone, "")},
+				{CutOffDateTimeCode: "VCO", CutOffDateTime: dcsaDateTime(first.Cutoffs.VgmCutoffDate, zone, "")},
+			} {
+				if cutoff.CutOffDateTime != "" {
+					route.CutOffTimes = append(route.CutOffTimes, cutoff)
+				}
+			}
+		}
+	}
+	for i, leg := range schedule.Legs {
+		transport := DCSATransport{ModeOfTransport: cmp.Or(dcsaModeOfTransport[leg.Transportations.TransportType], "VESSEL")}
+		if leg.Transportations.ReferenceType == "IMO" || leg.Transportations.TransportName != "" {
+			transport.Vessel = &DCSAVessel{Name: leg.Transportations.TransportName}
+			if leg.Transportations.ReferenceType == "IMO" {
+				transport.Vessel.VesselIMONumber = leg.Transportations.Reference
+			}
+		}
+		if leg.Voyages != nil {
+			partner := DCSAServicePartner{
+				CarrierCode:               cmp.Or(leg.Scac, schedule.Scac),
+				CarrierCodeListProvider:   "NMFTA",
+				CarrierImportVoyageNumber: leg.Voyages.ExternalVoyage,
+				CarrierExportVoyageNumber: leg.Voyages.InternalVoyage,
+			}
+			if leg.Services != nil {
+				partner.CarrierServiceCode, partner.CarrierServiceName = leg.Services.ServiceCode, leg.Services.ServiceName
+			}
+			transport.ServicePartners = []DCSAServicePartner{partner}
+		}
+		route.Legs = append(route.Legs, DCSALeg{
+			SequenceNumber: i + 1,
+			Transport:      transport,
+			Departure:      dcsaPoint(leg.PointFrom, schedule.PointFrom, leg.Etd, leg.EtdUTC),
+			Arrival:        dcsaPoint(leg.PointTo, schedule.PointTo, leg.Eta, leg.EtaUTC),
+		})
+	}
+	return route
+}
+
+// callVoyageNumber takes the last voyage of a call listing several, a list decoded from the carrier json holds interface{} values
+func callVoyageNumber(voyage interface{}, fallback string) string {
+	switch v := voyage.(type) {
+	case string:
+		return v
+	case []string:
+		if len(v) > 0 {
+			return v[len(v)-1]
+		}
+	case []interface{}:
+		if len(v) > 0 && v[len(v)-1] != nil {
+			return fmt.Sprint(v[len(v)-1])
+		}
+	}
+	return fallback
+}
+
+// NewDCSAServiceSchedule maps the master vessel schedule onto the OVS service schedule. The consecutive loading and unloading
+// calls of the same port(same key) are merged into one transport call with ARRI and DEPA timestamps
+func NewDCSAServiceSchedule(schedule *MasterVesselSchedule) DCSAServiceSchedule {
+	service := DCSAServiceSchedule{}
+	if schedule.Services != nil {
+		service.CarrierServiceCode, service.CarrierServiceName = schedule.Services.ServiceCode, schedule.Services.ServiceName
+	}
+	vesselSchedule := DCSAVesselSchedule{VesselOperatorSMDGLinerCode: smdgLinerCodes[schedule.Scac]}
+	if schedule.Vessel != nil {
+		vesselSchedule.VesselIMONumber, vesselSchedule.VesselName = schedule.Vessel.Imo, schedule.Vessel.VesselName
+	}
+	var lastKey string
+	for _, call := range schedule.Calls {
+		if call.Port == nil {
+			continue
+		}
+		key := fmt.Sprintf("%s|%v", call.Port.PortCode, call.Key)
+		if key != lastKey || len(vesselSchedule.TransportCalls) == 0 {
+			vesselSchedule.TransportCalls = append(vesselSchedule.TransportCalls, DCSATransportCall{
+				TransportCallReference: strings.Join([]string{schedule.Scac, callVoyageNumber(call.Voyage, schedule.Voyage), call.Port.PortCode, fmt.Sprint(call.Seq)}, "-"),
+				Location: DCSALocation{
+					LocationName:   call.Port.PortName,
+					LocationType:   "UNLO",
+					UNLocationCode: call.Port.PortCode,
+					FacilityCode:   call.Port.TerminalCode,
+				},
+			})
+			lastKey = key
+		}
+		transportCall := &vesselSchedule.TransportCalls[len(vesselSchedule.TransportCalls)-1]
+		eventType := "DEPA"
+		if call.PortEvent == EventType["UNL"] {
+			eventType = "ARRI"
+			transportCall.CarrierImportVoyageNumber = callVoyageNumber(call.Voyage, schedule.Voyage)
+		} else {
+			transportCall.CarrierExportVoyageNumber = callVoyageNumber(call.Voyage, schedule.Voyage)
+		}
+		for _, timestamp := range []DCSATimestamp{
+			{EventTypeCode: eventType, EventClassifierCode: "PLN", EventDateTime: dcsaDateTime(call.PlannedEventDate, call.Port.TimeZone, "")},
+			{EventTypeCode: eventType, EventClassifierCode: "EST", EventDateTime: dcsaDateTime(call.EstimatedEventDate, call.Port.TimeZone, call.EstimatedEventDateUTC)},
+			{EventTypeCode: eventType, EventClassifierCode: "ACT", EventDateTime: dcsaDateTime(call.ActualEventDate, call.Port.TimeZone, call.ActualEventDateUTC)},
+		} {
+			if timestamp.EventDateTime != "" {
+				transportCall.Timestamps = append(transportCall.Timestamps, timestamp)
+			}
+		}
+	}
+	service.VesselSchedules = []DCSAVesselSchedule{vesselSchedule}
+	return service
+}
@@ -0,0 +1,118 @@
+package schema
+
+import (
+	"testing"
+)
+
+func TestDCSADateTime(t *testing.T) {
+	tests := []struct {
+		name  string
+		local string
+		zone  string
+		utc   string
+		want  string
+	}{
+		{name: "iana zone", local: "2024-03-10T08:00:00", zone: "Asia/Shanghai", want: "2024-03-10T08:00:00+08:00"},
+		{name: "offset zone", local: "2024-03-10T08:00:00", zone: "-05:00", want: "2024-03-10T08:00:00-05:00"},
+		{name: "unknown zone", local: "2024-07-01T12:00:00", zone: "Europe/Hamburg", utc: "2024-07-01T10:00:00Z", want: "2024-07-01T10:00:00Z"},
+		{name: "zone before utc", local: "2024-07-01T12:00:00", zone: "Europe/Berlin", utc: "2024-07-01T10:00:00Z", want: "2024-07-01T12:00:00+02:00"},
+		{name: "utc without zone", local: "2024-03-10T08:00:00", utc: "2024-03-10T00:00:00Z", want: "2024-03-10T00:00:00Z"},
+		{name: "neither", local: "2024-03-10T08:00:00", want: "2024-03-10T08:00:00"},
+		{name: "empty", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dcsaDateTime(tt.local, tt.zone, tt.utc); got != tt.want {
+				t.Errorf("dcsaDateTime(%q, %q, %q) = %q, want %q", tt.local, tt.zone, tt.utc, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDCSAPointToPoint(t *testing.T) {
+	leg := func(zoneFrom, zoneTo, etdUTC, etaUTC string) *Leg {
+		return &Leg{
+			PointFrom:       &PointBase{LocationCode: "CNSHA", TimeZone: zoneFrom},
+			PointTo:         &PointBase{LocationCode: "DEHAM", TimeZone: zoneTo},
+			Etd:             "2024-03-10T08:00:00",
+			Eta:             "2024-04-12T06:00:00",
+			EtdUTC:          etdUTC,
+			EtaUTC:          etaUTC,
+			Transportations: Transportation{TransportType: Vessel, TransportName: "EVER ACE", ReferenceType: "IMO", Reference: "9893890"},
+			Voyages:         &Voyage{InternalVoyage: "001W"},
+		}
+	}
+	tests := []struct {
+		name  string
+		leg   *Leg
+		valid bool
+	}{
+		{name: "zones of the ports", leg: leg("Asia/Shanghai", "Europe/Berlin", "", ""), valid: true},
+		{name: "utc times", leg: leg("", "", "2024-03-10T00:00:00Z", "2024-04-12T04:00:00Z"), valid: true},
+		{name: "local times only", leg: leg("", "", "", "")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := &P2PSchedule{
+				Scac: "MAEU", PointFrom: "CNSHA", PointTo: "DEHAM", Etd: tt.leg.Etd, Eta: tt.leg.Eta, EtdUTC: tt.leg.EtdUTC,
+				EtaUTC: tt.leg.EtaUTC, Legs: []*Leg{tt.leg},
+			}
+			route := NewDCSAPointToPoint(schedule)
+			if err := DCSAValidate.Struct(&route); (err == nil) != tt.valid {
+				t.Errorf("DCSAValidate.Struct() error = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestNewDCSAServiceSchedule(t *testing.T) {
+	schedule := &MasterVesselSchedule{
+		Scac:   "MAEU",
+		Voyage: "412W",
+		Vessel: &VesselDetails{VesselName: "MAERSK ESSEN", Imo: "9456771"},
+		Calls: []PortCalls{
+			{Seq: 1, Key: "A", PortEvent: EventType["UNL"], Port: &Port{PortCode: "SGSIN", TimeZone: "Asia/Singapore"}, EstimatedEventDate: "2024-03-10T08:00:00"},
+			{Seq: 2, Key: "A", PortEvent: EventType["LOA"], Port: &Port{PortCode: "SGSIN", TimeZone: "Asia/Singapore"}, EstimatedEventDate: "2024-03-11T20:00:00"},
+			{Seq: 3, Key: "B", PortEvent: EventType["UNL"], Port: &Port{PortCode: "LKCMB"}, ActualEventDate: "2024-03-15T06:00:00", ActualEventDateUTC: "2024-03-15T00:30:00Z"},
+		},
+	}
+	service := NewDCSAServiceSchedule(schedule)
+	if err := DCSAValidate.Struct(&service); err != nil {
+		t.Fatalf("DCSAValidate.Struct() error = %v", err)
+	}
+	calls := service.VesselSchedules[0].TransportCalls
+	if len(calls) != 2 {
+		t.Fatalf("got %d transport calls, want the loading and unloading of SGSIN merged into 2", len(calls))
+	}
+	for i, want := range []string{"2024-03-10T08:00:00+08:00", "2024-03-11T20:00:00+08:00"} {
+		if got := calls[0].Timestamps[i].EventDateTime; got != want {
+			t.Errorf("timestamp %d = %q, want %q", i, got, want)
+		}
+	}
+	if got := calls[1].Timestamps[0].EventDateTime; got != "2024-03-15T00:30:00Z" {
+		t.Errorf("timestamp of LKCMB = %q, want the utc time", got)
+	}
+}
+
+func TestCallVoyageNumber(t *testing.T) {
+	tests := []struct {
+		name   string
+		voyage interface{}
+		want   string
+	}{
+		{name: "missing", voyage: nil, want: "412W"},
+		{name: "string", voyage: "413E", want: "413E"},
+		{name: "strings", voyage: []string{"412W", "413E"}, want: "413E"},
+		{name: "empty strings", voyage: []string{}, want: "412W"},
+		{name: "decoded json list", voyage: []interface{}{"412W", "413E"}, want: "413E"},
+		{name: "decoded json numbers", voyage: []interface{}{float64(412), float64(413)}, want: "413"},
+		{name: "empty decoded json list", voyage: []interface{}{}, want: "412W"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := callVoyageNumber(tt.voyage, "412W"); got != tt.want {
+				t.Errorf("callVoyageNumber(%v) = %q, want %q", tt.voyage, got, tt.want)
+			}
+		})
+	}
+}
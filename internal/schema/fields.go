@@ -0,0 +1,249 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// FieldSet is the sparse fieldset asked with the fields parameter(e.g. scac,etd,legs.transportations). A field mapped to nil is
+// kept as a whole, a nil FieldSet keeps every field
+type FieldSet map[string]FieldSet
+
+// jsonField is a serialized struct field as encoding/json sees it
+type jsonField struct {
+	name  string
+	index []int
+}
+
+var jsonFieldCache sync.Map // reflect.Type -> []jsonField
+
+// jsonFields lists the serialized fields of the struct, the fields of an embedded struct are promoted like encoding/json does
+func jsonFields(t reflect.Type) []jsonField {
+	if cached, ok := jsonFieldCache.Load(t); ok {
+		return cached.([]jsonField)
+	}
+	var fields []jsonField
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, _, _ := strings.Cut(tag, ",")
+			path := append(append([]int(nil), index...), i)
+			if f.Anonymous && name == "" {
+				embedded := f.Type
+				if embedded.Kind() == reflect.Pointer {
+					embedded = embedded.Elem()
+				}
+				if embedded.Kind() == reflect.Struct {
+					walk(embedded, path)
+					continue
+				}
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			fields = append(fields, jsonField{name: name, index: path})
+		}
+	}
+	walk(t, nil)
+	jsonFieldCache.Store(t, fields)
+	return fields
+}
+
+// elemType goes down the pointers and lists to the type the dotted path continues on
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t
+}
+
+// ParseFieldSet checks the dotted paths against the json fields of the model, the empty paths are ignored
+func ParseFieldSet(paths []string, model any) (FieldSet, error) {
+	var root FieldSet
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if root == nil {
+			root = FieldSet{}
+		}
+		node, t := root, elemType(reflect.TypeOf(model))
+		segments := strings.Split(path, ".")
+		for i, segment := range segments {
+			if t.Kind() != reflect.Struct {
+				return nil, fmt.Errorf("invalid field value in 'fields': %s, %s has no subfields", path, strings.Join(segments[:i], "."))
+			}
+			var found *jsonField
+			for _, f := range jsonFields(t) {
+				if f.name == segment {
+					found = &f
+					break
+				}
+			}
+			if found == nil {
+				return nil, fmt.Errorf("invalid field value in 'fields': %s", path)
+			}
+			child, selected := node[segment]
+			if i == len(segments)-1 {
+				node[segment] = nil // the whole field, it wins over the subfields asked before
+				break
+			}
+			if selected && child == nil {
+				break // the whole field is already kept
+			}
+			if !selected {
+				child = FieldSet{}
+				node[segment] = child
+			}
+			node, t = child, elemType(t.FieldByIndex(found.index).Type)
+		}
+	}
+	if root.collapse(elemType(reflect.TypeOf(model))) {
+		return nil, nil // every field, the plain marshal is faster than the projection
+	}
+	return root, nil
+}
+
+// collapse turns the subfields covering every field of their struct into the whole field, it reports whether f covers t
+func (f FieldSet) collapse(t reflect.Type) bool {
+	if f == nil {
+		return true
+	}
+	fields := jsonFields(t)
+	whole := len(f) == len(fields)
+	for _, field := range fields {
+		child, selected := f[field.name]
+		if !selected {
+			whole = false
+			continue
+		}
+		if child != nil && child.collapse(elemType(t.FieldByIndex(field.index).Type)) {
+			f[field.name] = nil
+		} else if child != nil {
+			whole = false
+		}
+	}
+	return whole
+}
+
+// Apply wraps v so that it serializes with the selected fields only, v goes out as it is without fieldset
+func (f FieldSet) Apply(v any) any {
+	if f == nil {
+		return v
+	}
+	return projection{value: v, fields: f}
+}
+
+type projection struct {
+	value  any
+	fields FieldSet
+}
+
+// MarshalJSON marshals the value as it is and drops the fields left out of the fieldset, so every field that is kept reads the
+// same as in the full output
+func (p projection) MarshalJSON() ([]byte, error) {
+	encoded, err := json.Marshal(p.value)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, len(encoded)))
+	p.fields.filter(buf, encoded)
+	return buf.Bytes(), nil
+}
+
+// filter copies the value at the start of the compact json written by json.Marshal with the selected fields only, in their
+// order, and returns its length. A list is filtered item by item, null goes out as it is
+func (f FieldSet) filter(buf *bytes.Buffer, data []byte) int {
+	switch data[0] {
+	case '[':
+		buf.WriteByte('[')
+		i := 1
+		for data[i] != ']' {
+			if data[i] == ',' {
+				buf.WriteByte(',')
+				i++
+			}
+			i += f.filter(buf, data[i:])
+		}
+		buf.WriteByte(']')
+		return i + 1
+	case '{':
+		buf.WriteByte('{')
+		i, written := 1, 0
+		for data[i] != '}' {
+			if data[i] == ',' {
+				i++
+			}
+			key := data[i : i+skipValue(data[i:])]
+			i += len(key) + 1                               // and the colon
+			child, selected := f[string(key[1:len(key)-1])] // the field names need no escaping
+			switch {
+			case !selected:
+				i += skipValue(data[i:])
+				continue
+			case written > 0:
+				buf.WriteByte(',')
+			}
+			written++
+			buf.Write(key)
+			buf.WriteByte(':')
+			if child != nil {
+				i += child.filter(buf, data[i:])
+				continue
+			}
+			n := skipValue(data[i:])
+			buf.Write(data[i : i+n])
+			i += n
+		}
+		buf.WriteByte('}')
+		return i + 1
+	}
+	n := skipValue(data)
+	buf.Write(data[:n])
+	return n
+}
+
+// skipValue returns the length of the json value at the start of data
+func skipValue(data []byte) int {
+	depth := 0
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '"':
+			for i++; data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+			if depth == 0 {
+				return i + 1
+			}
+		case '{', '[':
+			depth++
+		case '}', ']':
+			if depth == 0 {
+				return i // the end of the object or list holding the number or literal
+			}
+			if depth--; depth == 0 {
+				return i + 1
+			}
+		case ',':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(data)
+}
@@ -0,0 +1,282 @@
+package schema
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testSchedule() *P2PSchedule {
+	return &P2PSchedule{
+		ScheduleID: "0123456789abcdef", Scac: "MAEU", PointFrom: "CNSHA", PointTo: "DEHAM",
+		Etd: "2024-03-10T08:00:00", Eta: "2024-04-12T06:00:00", EtdUTC: "2024-03-10T00:00:00Z", EtaUTC: "2024-04-12T04:00:00Z",
+		TransitTime: 33, Transshipment: true,
+		Legs: []*Leg{
+			{
+				PointFrom: &PointBase{LocationName: "Shanghai", LocationCode: "CNSHA", TimeZone: "Asia/Shanghai"},
+				PointTo:   &PointBase{LocationName: "Singapore", LocationCode: "SGSIN"},
+				Etd:       "2024-03-10T08:00:00", Eta: "2024-03-16T06:00:00", TransitTime: 6,
+				Cutoffs:         &Cutoff{CyCutoffDate: "2024-03-08T12:00:00"},
+				Transportations: Transportation{TransportType: Vessel, TransportName: "EVER ACE", ReferenceType: "IMO", Reference: "9893890"},
+				Voyages:         &Voyage{InternalVoyage: "001W"},
+				Services:        &Service{ServiceCode: "AE7"},
+			},
+			{
+				PointFrom: &PointBase{LocationCode: "SGSIN"},
+				PointTo:   &PointBase{LocationName: "Hamburg <Waltershof> & Co", LocationCode: "DEHAM"},
+				Etd:       "2024-03-17T08:00:00", Eta: "2024-04-12T06:00:00", TransitTime: 26,
+				Transportations: Transportation{TransportType: Feeder, TransportName: "NORDIC \"LIGHT\"\t\x01\xff"},
+			},
+		},
+		OfferedBy: []string{"MAEU", "MSCU"},
+	}
+}
+
+func TestParseFieldSet(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		want  FieldSet
+		err   string
+	}{
+		{name: "no paths", paths: nil, want: nil},
+		{name: "blank paths", paths: []string{" ", ""}, want: nil},
+		{name: "top level", paths: []string{"scac", " etd "}, want: FieldSet{"scac": nil, "etd": nil}},
+		{
+			name:  "nested",
+			paths: []string{"legs.transportations.transportName", "legs.pointFrom"},
+			want:  FieldSet{"legs": FieldSet{"transportations": FieldSet{"transportName": nil}, "pointFrom": nil}},
+		},
+		{name: "whole field wins over the subfields before", paths: []string{"legs.etd", "legs"}, want: FieldSet{"legs": nil}},
+		{name: "whole field wins over the subfields after", paths: []string{"legs", "legs.etd"}, want: FieldSet{"legs": nil}},
+		{name: "unknown field", paths: []string{"vessel"}, err: "invalid field value in 'fields': vessel"},
+		{name: "unknown subfield", paths: []string{"legs.vessel"}, err: "invalid field value in 'fields': legs.vessel"},
+		{name: "subfield of a leaf", paths: []string{"etd.day"}, err: "etd has no subfields"},
+		{name: "go name", paths: []string{"TransitTime"}, err: "invalid field value"},
+		{
+			name:  "every subfield is the whole field",
+			paths: []string{"scac", "legs.voyages.internalVoyage", "legs.voyages.externalVoyage", "legs.etd"},
+			want:  FieldSet{"scac": nil, "legs": FieldSet{"voyages": nil, "etd": nil}},
+		},
+		{
+			name: "every field is the whole schedule",
+			paths: []string{"scheduleId", "scac", "pointFrom", "pointTo", "etd", "eta", "etdUtc", "etaUtc", "transitTime",
+				"transshipment", "legs", "offeredBy", "lane", "interline"},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFieldSet(tt.paths, P2PSchedule{})
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("ParseFieldSet() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFieldSet() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFieldSet() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		want  string
+	}{
+		{
+			name:  "top level in struct order",
+			paths: []string{"transitTime", "scac"},
+			want:  `{"scac":"MAEU","transitTime":33}`,
+		},
+		{
+			name:  "omitempty is kept",
+			paths: []string{"lane", "scac"},
+			want:  `{"scac":"MAEU"}`,
+		},
+		{
+			name:  "nested through the list and the pointers",
+			paths: []string{"legs.pointTo.locationCode", "legs.cutoffs.cyCutoffDate"},
+			want:  `{"legs":[{"pointTo":{"locationCode":"SGSIN"},"cutoffs":{"cyCutoffDate":"2024-03-08T12:00:00"}},{"pointTo":{"locationCode":"DEHAM"}}]}`,
+		},
+		{
+			name:  "nil pointer without omitempty",
+			paths: []string{"legs.voyages.internalVoyage"},
+			want:  `{"legs":[{"voyages":{"internalVoyage":"001W"}},{"voyages":null}]}`,
+		},
+		{
+			name:  "whole subtree",
+			paths: []string{"legs.services"},
+			want:  `{"legs":[{"services":{"serviceCode":"AE7"}},{}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := ParseFieldSet(tt.paths, P2PSchedule{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.Marshal(fields.Apply(testSchedule()))
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("json.Marshal() = %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+type leafs struct {
+	Text     string            `json:"text"`
+	Numbers  []float64         `json:"numbers"`
+	Small    float32           `json:"small"`
+	Unsigned uint8             `json:"unsigned"`
+	Negative int64             `json:"negative,omitempty"`
+	Bytes    []byte            `json:"bytes"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Any      any               `json:"any"`
+	Nested   *leafs            `json:"nested,omitempty"`
+	Skipped  string            `json:"-"`
+	hidden   string
+	Embedded
+}
+
+type Embedded struct {
+	Promoted string `json:"promoted"`
+	Bound    Bound  `json:"bound"`
+}
+
+// Bound marshals itself through a pointer receiver
+type Bound string
+
+func (b *Bound) MarshalText() ([]byte, error) {
+	return []byte(strings.ToLower(string(*b))), nil
+}
+
+// TestApplyMatchesMarshal selects every field, the projection has to write what encoding/json writes
+func TestApplyMatchesMarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		model any
+	}{
+		{name: "p2p schedule", value: testSchedule(), model: P2PSchedule{}},
+		{name: "p2p schedules", value: []*P2PSchedule{testSchedule(), nil}, model: P2PSchedule{}},
+		{
+			name: "master vessel schedule",
+			value: &MasterVesselSchedule{
+				Scac: "MAEU", Voyage: "412W", Vessel: &VesselDetails{VesselName: "MAERSK ESSEN", Imo: "9456771"},
+				Calls: []PortCalls{{Seq: 1, Key: 7, Bound: nil, Voyage: []string{"412W"}, Port: &Port{PortCode: "SGSIN"}}},
+			},
+			model: MasterVesselSchedule{},
+		},
+		{
+			name: "leafs",
+			value: &leafs{
+				Text: "<a href=\"x\">&amp;</a> \b\f\r\n\x7f", Numbers: []float64{0, -1.5, 1e21, 1e-7, 123456789, math.SmallestNonzeroFloat64},
+				Small: 3.4e-7, Unsigned: 255, Negative: -42, Bytes: []byte("hub"), Labels: map[string]string{"b": "2", "a": "1"},
+				Any: []any{1, "two", map[string]any{"three": 3.0}}, Nested: &leafs{Text: "inner"}, Skipped: "no", hidden: "no",
+				Embedded: Embedded{Promoted: "yes", Bound: "WEST"},
+			},
+			model: leafs{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := FieldSet{} // by hand, ParseFieldSet collapses every field to the plain marshal
+			for _, field := range jsonFields(elemType(reflect.TypeOf(tt.model))) {
+				fields[field.name] = nil
+			}
+			got, err := json.Marshal(fields.Apply(tt.value))
+			if err != nil {
+				t.Fatalf("json.Marshal(projection) error = %v", err)
+			}
+			want, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("json.Marshal(projection) = %s\nwant %s", got, want)
+			}
+		})
+	}
+}
+
+// TestApplySkipsEveryValue leaves out strings holding quotes and brackets, nested lists and objects and the literals
+func TestApplySkipsEveryValue(t *testing.T) {
+	value := &leafs{
+		Text: `"}],{[" \\`, Numbers: []float64{-1.5, 1e21}, Unsigned: 7, Labels: map[string]string{"a]": "{"},
+		Any: []any{[]any{}, map[string]any{"x": []any{nil, true, false}}}, Nested: &leafs{Text: `inner "quoted"`, Unsigned: 1},
+		Embedded: Embedded{Bound: "WEST"},
+	}
+	fields := FieldSet{"unsigned": nil, "nested": FieldSet{"text": nil}, "bound": nil}
+	got, err := json.Marshal(fields.Apply(value))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if want := `{"unsigned":7,"nested":{"text":"inner \"quoted\""},"bound":"west"}`; string(got) != want {
+		t.Errorf("json.Marshal() = %s\nwant %s", got, want)
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	fields := FieldSet{"numbers": nil}
+	if _, err := json.Marshal(fields.Apply(&leafs{Numbers: []float64{math.NaN()}})); err == nil {
+		t.Error("json.Marshal(NaN) succeeded, want an error")
+	}
+}
+
+// BenchmarkApply compares a page of schedules pruned to a few fields with the full marshal the fieldset replaces
+func BenchmarkApply(b *testing.B) {
+	schedules := make([]*P2PSchedule, 50)
+	for i := range schedules {
+		schedules[i] = testSchedule()
+	}
+	fields, err := ParseFieldSet([]string{"scac", "etd", "eta", "transitTime", "legs.transportations", "legs.pointTo.locationCode"}, P2PSchedule{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	every := FieldSet{}
+	for _, field := range jsonFields(reflect.TypeOf(P2PSchedule{})) {
+		every[field.name] = nil
+	}
+	b.Run("json.Marshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, schedule := range schedules {
+				if _, err := json.Marshal(schedule); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("pruned", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, schedule := range schedules {
+				if _, err := json.Marshal(fields.Apply(schedule)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("every field uncollapsed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, schedule := range schedules {
+				if _, err := json.Marshal(every.Apply(schedule)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
@@ -21,6 +21,14 @@ func init() {
 		return
 	}
 
+	errUTCDate := MVSResponseValidate.RegisterValidation("isValidUTCDate", func(fl validator.FieldLevel) bool {
+		_, err := time.Parse("2006-01-02T15:04:05Z", fl.Field().String())
+		return err == nil
+	})
+	if errUTCDate != nil {
+		return
+	}
+
 	errPort := MVSResponseValidate.RegisterValidation("portCodeValidation", func(fl validator.FieldLevel) bool {
 		regex := regexp.MustCompile(`^[A-Z]{2}[A-Z0-9]{3}$`)
 		value := fl.Field().String()
@@ -52,6 +60,7 @@ type Port struct {
 	PortCode     string `json:"portCode" validate:"required,portCodeValidation"`
 	TerminalName string `json:"terminalName,omitempty" validate:"omitempty"`
 	TerminalCode string `json:"terminalCode,omitempty" validate:"omitempty"`
+	TimeZone     string `json:"timeZone,omitempty"` // IANA zone or UTC offset(e.g. +08:00) of the local event times
 }
 
 type VesselDetails struct {
@@ -65,15 +74,18 @@ type Services struct {
 }
 
 type PortCalls struct {
-	Seq                int         `json:"seq" validate:"required,numeric,gte=1"`
-	Key                interface{} `json:"key" validate:"required"`
-	Bound              interface{} `json:"bound" validate:"required"`
-	Voyage             interface{} `json:"voyage" validate:"required"`
-	Service            *Services   `json:"service" validate:"omitempty"`
-	PortEvent          string      `json:"portEvent" validate:"required,oneof=Loading Unloading Pass"`
-	Port               *Port       `json:"port" validate:"omitempty"`
-	EstimatedEventDate string      `json:"estimatedEventDate,omitempty" validate:"omitempty,isValidDate"`
-	ActualEventDate    string      `json:"actualEventDate,omitempty" validate:"omitempty,isValidDate"`
+	Seq                   int         `json:"seq" validate:"required,numeric,gte=1"`
+	Key                   interface{} `json:"key" validate:"required"`
+	Bound                 interface{} `json:"bound" validate:"required"`
+	Voyage                interface{} `json:"voyage" validate:"required"`
+	Service               *Services   `json:"service" validate:"omitempty"`
+	PortEvent             string      `json:"portEvent" validate:"required,oneof=Loading Unloading Pass"`
+	Port                  *Port       `json:"port" validate:"omitempty"`
+	PlannedEventDate      string      `json:"plannedEventDate,omitempty" validate:"omitempty,isValidDate"` // long term schedule(DCSA PLN)
+	EstimatedEventDate    string      `json:"estimatedEventDate,omitempty" validate:"omitempty,isValidDate"`
+	ActualEventDate       string      `json:"actualEventDate,omitempty" validate:"omitempty,isValidDate"`
+	EstimatedEventDateUTC string      `json:"estimatedEventDateUtc,omitempty" validate:"omitempty,isValidUTCDate"`
+	ActualEventDateUTC    string      `json:"actualEventDateUtc,omitempty" validate:"omitempty,isValidUTCDate"`
 }
 
 type MasterVesselSchedule struct {
@@ -1,6 +1,9 @@
 package schema
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"github.com/go-playground/validator/v10"
 	"regexp"
 	"time"
@@ -22,6 +25,14 @@ func init() {
 		return
 	}
 
+	errUTCDate := P2PResponseValidate.RegisterValidation("isValidUTCDate", func(fl validator.FieldLevel) bool {
+		_, err := time.Parse("2006-01-02T15:04:05Z", fl.Field().String())
+		return err == nil
+	})
+	if errUTCDate != nil {
+		return
+	}
+
 	errPort := P2PResponseValidate.RegisterValidation("portCodeValidation", func(fl validator.FieldLevel) bool {
 		regex := regexp.MustCompile(`^[A-Z]{2}[A-Z0-9]{3}$`)
 		value := fl.Field().String()
@@ -42,6 +53,7 @@ type PointBase struct {
 	LocationCode string `json:"locationCode" validate:"required,portCodeValidation" description:"Location Code"`
 	TerminalName string `json:"terminalName,omitempty"`
 	TerminalCode string `json:"terminalCode,omitempty"`
+	TimeZone     string `json:"timeZone,omitempty"` // IANA zone or UTC offset(e.g. +08:00) of the local event times
 }
 
 type Cutoff struct {
@@ -123,11 +135,14 @@ type Leg struct {
 	PointTo         *PointBase     `json:"pointTo" validate:"omitempty"`
 	Etd             string         `json:"etd" validate:"required,isValidDate"`
 	Eta             string         `json:"eta" validate:"required,isValidDate"`
+	EtdUTC          string         `json:"etdUtc,omitempty" validate:"omitempty,isValidUTCDate"`
+	EtaUTC          string         `json:"etaUtc,omitempty" validate:"omitempty,isValidUTCDate"`
 	TransitTime     int            `json:"transitTime" validate:"gte=0"`
 	Cutoffs         *Cutoff        `json:"cutoffs,omitempty" validate:"omitempty"`
 	Transportations Transportation `json:"transportations"`
 	Voyages         *Voyage        `json:"voyages" validate:"omitempty"`
 	Services        *Service       `json:"services,omitempty" validate:"omitempty"`
+	Scac            string         `json:"scac,omitempty"` // carrier operating the leg of an interline schedule
 }
 
 func LegEventDateValidation(sl validator.StructLevel) {
@@ -144,14 +159,46 @@ func LegEventDateValidation(sl validator.StructLevel) {
 
 // Schedule struct equivalent in Go
 type P2PSchedule struct {
-	Scac          string `json:"scac" validate:"required"`
-	PointFrom     string `json:"pointFrom" validate:"required,portCodeValidation"`
-	PointTo       string `json:"pointTo" validate:"required,portCodeValidation"`
-	Etd           string `json:"etd" validate:"required,isValidDate"`
-	Eta           string `json:"eta" validate:"required,isValidDate"`
-	TransitTime   int    `json:"transitTime" validate:"gte=0"`
-	Transshipment bool   `json:"transshipment"`
-	Legs          []*Leg `json:"legs" validate:"required,dive"`
+	ScheduleID    string   `json:"scheduleId,omitempty"`
+	Scac          string   `json:"scac" validate:"required"`
+	PointFrom     string   `json:"pointFrom" validate:"required,portCodeValidation"`
+	PointTo       string   `json:"pointTo" validate:"required,portCodeValidation"`
+	Etd           string   `json:"etd" validate:"required,isValidDate"`
+	Eta           string   `json:"eta" validate:"required,isValidDate"`
+	EtdUTC        string   `json:"etdUtc,omitempty" validate:"omitempty,isValidUTCDate"`
+	EtaUTC        string   `json:"etaUtc,omitempty" validate:"omitempty,isValidUTCDate"`
+	TransitTime   int      `json:"transitTime" validate:"gte=0"`
+	Transshipment bool     `json:"transshipment"`
+	Legs          []*Leg   `json:"legs" validate:"required,dive"`
+	OfferedBy     []string `json:"offeredBy,omitempty"` // every carrier selling the same sailing when dedupe is on
+	Lane          string   `json:"lane,omitempty"`      // port pair searched(e.g. CNSHA-DEHAM) when the search covers several ports
+	Interline     bool     `json:"interline,omitempty"` // stitched by us out of the schedules of several carriers meeting at a hub
+}
+
+// GenerateScheduleID derives a deterministic id from the sailing itself(carrier, ports, departure day of the first leg, vessel and
+// voyage of every leg). The departure day tells apart the weekly sailings reusing the voyage number, the times are left out so
+// the id survives a delay within the day and the client can tell that the sailing has changed.
+func (s *P2PSchedule) GenerateScheduleID() string {
+	departure := s.Etd
+	if len(s.Legs) > 0 && s.Legs[0].Etd != "" {
+		departure = s.Legs[0].Etd
+	}
+	hash := sha256.New()
+	_, _ = fmt.Fprintf(hash, "%s|%s|%s|%.10s", s.Scac, s.PointFrom, s.PointTo, departure)
+	for _, leg := range s.Legs {
+		var from, to, voyage string
+		if leg.PointFrom != nil {
+			from = leg.PointFrom.LocationCode
+		}
+		if leg.PointTo != nil {
+			to = leg.PointTo.LocationCode
+		}
+		if leg.Voyages != nil {
+			voyage = leg.Voyages.InternalVoyage
+		}
+		_, _ = fmt.Fprintf(hash, ";%s|%s|%s|%s|%s", from, to, leg.Transportations.TransportType, leg.Transportations.Reference, voyage)
+	}
+	return hex.EncodeToString(hash.Sum(nil)[:16])
 }
 
 func ScheduleEventDateValidation(sl validator.StructLevel) {
@@ -173,6 +220,53 @@ type Product struct {
 	Schedules   []*P2PSchedule `json:"schedules" validate:"dive"`
 }
 
+// PlannedSchedule is a schedule checked against the deadline of the planner. Reasons tell why an infeasible one misses it
+type PlannedSchedule struct {
+	*P2PSchedule
+	Feasible   bool     `json:"feasible"`
+	SlackHours int      `json:"slackHours"` // hours between the arrival and the deadline, negative when it arrives too late
+	LastCutoff string   `json:"lastCutoff,omitempty"`
+	Reasons    []string `json:"reasons,omitempty"`
+}
+
+type PlannerResult struct {
+	Origin         string             `json:"origin"`
+	Destination    string             `json:"destination"`
+	ArriveBy       string             `json:"arriveBy"`
+	CargoReadyDate string             `json:"cargoReadyDate,omitempty"`
+	Schedules      []*PlannedSchedule `json:"schedules"`
+	Message        string             `json:"message,omitempty"`
+}
+
+// BatchScheduleLine is one NDJSON line of the batch search. A lane sends one line per schedule followed by a closing line with
+// the total(and a message when there is nothing to show)
+type BatchScheduleLine struct {
+	Lane        int          `json:"lane"`
+	Origin      string       `json:"origin"`
+	Destination string       `json:"destination"`
+	Schedule    *P2PSchedule `json:"schedule,omitempty"`
+	Complete    bool         `json:"complete,omitempty"`
+	Total       int          `json:"total,omitempty"`
+	Message     string       `json:"message,omitempty"`
+}
+
+// CarrierStatus tells the streaming client that a carrier has answered(or failed) for a port pair
+type CarrierStatus struct {
+	Scac      string `json:"scac"`
+	Lane      string `json:"lane"`
+	Status    string `json:"status"` // ok, empty or failed
+	Schedules int    `json:"schedules"`
+}
+
+// StreamTrailer closes the NDJSON and event stream outputs. Complete is false when one of the carriers failed
+type StreamTrailer struct {
+	Done          bool   `json:"done"`
+	Total         int    `json:"total"`
+	Complete      bool   `json:"complete"`
+	NextPageToken string `json:"nextPageToken,omitempty"`
+	Message       string `json:"message,omitempty"`
+}
+
 // HealthCheck struct equivalent in Go
 type HealthCheck struct {
 	Status string `json:"status" validate:"required"`
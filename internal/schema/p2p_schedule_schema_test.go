@@ -0,0 +1,33 @@
+package schema
+
+import "testing"
+
+func TestGenerateScheduleID(t *testing.T) {
+	schedule := func(etd string) *P2PSchedule {
+		return &P2PSchedule{
+			Scac: "MAEU", PointFrom: "CNSHA", PointTo: "DEHAM", Etd: etd,
+			Legs: []*Leg{{
+				PointFrom: &PointBase{LocationCode: "CNSHA"}, PointTo: &PointBase{LocationCode: "DEHAM"}, Etd: etd,
+				Transportations: Transportation{TransportType: Vessel, Reference: "9893890"},
+				Voyages:         &Voyage{InternalVoyage: "412W"},
+			}},
+		}
+	}
+	base := schedule("2024-03-10T08:00:00").GenerateScheduleID()
+	tests := []struct {
+		name string
+		etd  string
+		same bool
+	}{
+		{name: "delayed within the day", etd: "2024-03-10T22:00:00", same: true},
+		{name: "delayed to the next day", etd: "2024-03-11T02:00:00"},
+		{name: "same voyage number a week later", etd: "2024-03-17T08:00:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if same := schedule(tt.etd).GenerateScheduleID() == base; same != tt.same {
+				t.Errorf("same id = %v, want %v", same, tt.same)
+			}
+		})
+	}
+}
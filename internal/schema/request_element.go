@@ -8,6 +8,18 @@ const (
 	Arrival   StartDateType = "Arrival"
 )
 
+// Enum for SortBy
+type SortBy string
+
+const (
+	SortByEtd            SortBy = "etd"
+	SortByEta            SortBy = "eta"
+	SortByTransitTime    SortBy = "transitTime"
+	SortByTransshipments SortBy = "transshipments"
+	SortByCutoff         SortBy = "cutoff"
+	SortByBest           SortBy = "best"
+)
+
 type CarrierCode string
 
 const (
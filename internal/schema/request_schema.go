@@ -1,8 +1,17 @@
 package schema
 
 import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"github.com/go-playground/validator/v10"
+	"net/url"
 	"regexp"
+	"slices"
+	"strconv"
 	"time"
 )
 
@@ -42,6 +51,26 @@ func init() {
 		return
 	}
 
+	errDateTime := RequestValidate.RegisterValidation("isValidDateTime", func(fl validator.FieldLevel) bool {
+		value := fl.Field().String()
+		_, errDateTime := time.Parse("2006-01-02T15:04:05", value)
+		_, errDate := time.Parse("2006-01-02", value)
+		return errDateTime == nil || errDate == nil
+	})
+	if errDateTime != nil {
+		return
+	}
+
+	// Function to check if the value is either a port code or a country code
+	errPortOrCountry := RequestValidate.RegisterValidation("portOrCountryValidation", func(fl validator.FieldLevel) bool {
+		regex := regexp.MustCompile(`^[A-Z]{2}([A-Z0-9]{3})?$`)
+		value := fl.Field().String()
+		return regex.MatchString(value)
+	})
+	if errPortOrCountry != nil {
+		return
+	}
+
 	errIMO := RequestValidate.RegisterValidation("isValidIMO", func(fl validator.FieldLevel) bool {
 		regex := regexp.MustCompile(`^[0-9]{7}$`)
 		value := fl.Field().String()
@@ -55,16 +84,223 @@ func init() {
 
 // Define the struct with field validations using Go tags
 type QueryParams struct {
-	PointFrom     string        `json:"pointFrom" validate:"required_with=PointTo,portCodeValidation" description:"Port Of Loading" `
-	PointTo       string        `json:"pointTo" validate:"required_with=PointFrom,portCodeValidation" description:"Port Of Discharge" `
-	StartDateType StartDateType `json:"startDateType" validate:"required,oneof=Departure Arrival" description:"Search by either Departure or Arrival"`
-	StartDate     string        `json:"startDate" validate:"required,isValidDate" description:"YYYY-MM-DD"`
-	SearchRange   int           `json:"searchRange" validate:"required,oneof=1 2 3 4" description:"Search range based on start date and type, max 4 weeks"`
-	SCAC          []CarrierCode `json:"scac" validate:"omitempty" example:"MSC,CMA"`
-	DirectOnly    bool          `json:"directOnly" validate:"omitempty" description:"Direct means only show direct schedule else show both (direct/transshipment)"`
-	TSP           string        `json:"transhipmentPort" validate:"omitempty,portCodeValidation" description:"Port Of Transshipment" example:"SGSIN"`
-	VesselIMO     string        `json:"vesselIMO" validate:"omitempty,max=7" description:"Restricts the search to a particular vessel IMO lloyds code"`
-	Service       string        `json:"service" validate:"omitempty" description:"Service code or service name"`
+	PointFrom     string          `json:"pointFrom" validate:"required_with=PointTo,portCodeValidation" description:"Port Of Loading" `
+	PointTo       string          `json:"pointTo" validate:"required_with=PointFrom,portCodeValidation" description:"Port Of Discharge" `
+	StartDateType StartDateType   `json:"startDateType" validate:"required,oneof=Departure Arrival" description:"Search by either Departure or Arrival"`
+	StartDate     string          `json:"startDate" validate:"required,isValidDate" description:"YYYY-MM-DD"`
+	SearchRange   int             `json:"searchRange" validate:"required,min=1,max=16" description:"Search range based on start date and type, max 16 weeks"`
+	SCAC          []CarrierCode   `json:"scac" validate:"omitempty" example:"MSC,CMA"`
+	DirectOnly    bool            `json:"directOnly" validate:"omitempty" description:"Direct means only show direct schedule else show both (direct/transshipment)"`
+	TSP           string          `json:"transhipmentPort" validate:"omitempty,portCodeValidation" description:"Port Of Transshipment" example:"SGSIN"`
+	VesselIMO     string          `json:"vesselIMO" validate:"omitempty,max=7" description:"Restricts the search to a particular vessel IMO lloyds code"`
+	Service       string          `json:"service" validate:"omitempty" description:"Service code or service name"`
+	ServiceName   string          `json:"serviceName" validate:"omitempty" description:"Part of the service name, case insensitive"`
+	VesselName    string          `json:"vesselName" validate:"omitempty" description:"Part of the vessel name, case insensitive"`
+	MaxTransit    *int            `json:"maxTransitTime" validate:"omitempty,gte=0" description:"Max transit time in days"`
+	MaxTSP        *int            `json:"maxTransshipments" validate:"omitempty,gte=0" description:"Max number of transshipments"`
+	ExcludeTSP    []string        `json:"excludeTransshipment" validate:"omitempty,dive,portOrCountryValidation" description:"Transshipment ports or countries to avoid" example:"SGSIN,LK"`
+	Modes         []TransportType `json:"transportModes" validate:"omitempty,dive,oneof=Vessel Barge Feeder Truck Rail Truckrail Roadrail Road Intermodal" description:"Transport modes allowed on the legs" example:"Vessel,Feeder"`
+	CutoffAfter   string          `json:"cutoffAfter" validate:"omitempty,isValidDateTime" description:"YYYY-MM-DD or YYYY-MM-DDTHH:MM:SS, the earliest cutoff has to be after it"`
+	SortBy        SortBy          `json:"sortBy" validate:"omitempty,oneof=etd eta transitTime transshipments cutoff best" description:"Sort the schedules before streaming them, best ranks them by the configured weights"`
+	Dedupe        bool            `json:"dedupe" validate:"omitempty" description:"Merge the same sailing sold by several carriers into one schedule"`
+	Connections   bool            `json:"connections" validate:"omitempty" description:"Also stitch the schedules of two carriers meeting at one of the configured hubs"`
+	Format        string          `json:"format" validate:"omitempty,oneof=json ndjson csv xlsx dcsa" description:"Output format, overrides the Accept header"`
+	Layout        string          `json:"layout" validate:"omitempty,oneof=schedules legs" description:"csv/xlsx rows, one per schedule(default) or one per leg"`
+	Limit         int             `json:"limit" validate:"omitempty,gte=1,lte=500" description:"Max number of schedules per page"`
+	PageToken     string          `json:"pageToken" validate:"omitempty" description:"nextPageToken returned by the previous page"`
+	Fields        []string        `json:"fields" validate:"omitempty" description:"json/ndjson/event-stream only, the schedule fields to keep, dotted for the leg fields" example:"scac,etd,eta,transitTime,legs.transportations"`
+	FieldSet      FieldSet        `json:"-"`                                              // fields checked against P2PSchedule
+	Offset        int             `json:"-"`                                              // position decoded from the page token
+	Origins       []string        `json:"-" validate:"omitempty,dive,portCodeValidation"` // every port pointFrom expands to(port group or list)
+	Destinations  []string        `json:"-" validate:"omitempty,dive,portCodeValidation"`
+}
+
+// PostFilters tells whether the carrier schedules have to go through the filters before being streamed
+func (q *QueryParams) PostFilters() bool {
+	return q.DirectOnly || q.TSP != "" || q.VesselIMO != "" || q.Service != "" || q.ServiceName != "" || q.VesselName != "" ||
+		q.MaxTransit != nil || q.MaxTSP != nil || len(q.ExcludeTSP) > 0 || len(q.Modes) > 0 || q.CutoffAfter != ""
+}
+
+// OriginPorts returns the ports the search starts from, a plain request only has pointFrom
+func (q *QueryParams) OriginPorts() []string {
+	if len(q.Origins) > 0 {
+		return q.Origins
+	}
+	return []string{q.PointFrom}
+}
+
+func (q *QueryParams) DestinationPorts() []string {
+	if len(q.Destinations) > 0 {
+		return q.Destinations
+	}
+	return []string{q.PointTo}
+}
+
+// MultiPort tells whether the search covers more than one port pair
+func (q *QueryParams) MultiPort() bool {
+	return len(q.OriginPorts()) > 1 || len(q.DestinationPorts()) > 1
+}
+
+// PortPairs expands the origins and destinations into one single pair query per port pair
+func (q *QueryParams) PortPairs() []*QueryParams {
+	pairs := make([]*QueryParams, 0, len(q.OriginPorts())*len(q.DestinationPorts()))
+	for _, origin := range q.OriginPorts() {
+		for _, destination := range q.DestinationPorts() {
+			if origin == destination {
+				continue
+			}
+			pair := *q
+			pair.PointFrom, pair.PointTo = origin, destination
+			pair.Origins, pair.Destinations = nil, nil
+			pairs = append(pairs, &pair)
+		}
+	}
+	return pairs
+}
+
+type pageCursor struct {
+	Query  string `json:"q"`
+	Offset int    `json:"o"`
+}
+
+// Paginated tells whether a page of the product is asked for, the product is then collected in full before the page is cut
+func (q *QueryParams) Paginated() bool {
+	return q.Limit > 0 || q.PageToken != ""
+}
+
+// pageQueryHash identifies the query a page token belongs to. The pages depend on sortBy and dedupe so they are part of the hash
+func (q *QueryParams) pageQueryHash() string {
+	hash := sha256.Sum256([]byte(q.CanonicalKey() + "&sortBy=" + string(q.SortBy) + "&dedupe=" + strconv.FormatBool(q.Dedupe)))
+	return hex.EncodeToString(hash[:8])
+}
+
+// EncodePageToken builds the cursor of the page starting at offset
+func (q *QueryParams) EncodePageToken(offset int) string {
+	token, _ := json.Marshal(pageCursor{Query: q.pageQueryHash(), Offset: offset})
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+// DecodePageToken returns the position encoded in the page token and makes sure the token was issued for the same query
+func (q *QueryParams) DecodePageToken() (int, error) {
+	if q.PageToken == "" {
+		return 0, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(q.PageToken)
+	if err != nil {
+		return 0, errors.New("invalid pageToken")
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(decoded, &cursor); err != nil || cursor.Offset < 0 {
+		return 0, errors.New("invalid pageToken")
+	}
+	if cursor.Query != q.pageQueryHash() {
+		return 0, errors.New("pageToken does not belong to the requested query")
+	}
+	return cursor.Offset, nil
+}
+
+// CanonicalKey identifies the normalized query no matter in which order the client sent the parameters or carriers
+func (q *QueryParams) CanonicalKey() string {
+	scacs := make([]string, 0, len(q.SCAC))
+	for _, scac := range q.SCAC {
+		scacs = append(scacs, string(scac))
+	}
+	slices.Sort(scacs)
+	origins, destinations := slices.Clone(q.OriginPorts()), slices.Clone(q.DestinationPorts())
+	slices.Sort(origins)
+	slices.Sort(destinations)
+	values := url.Values{
+		"pointFrom":        origins,
+		"pointTo":          destinations,
+		"startDateType":    {string(q.StartDateType)},
+		"startDate":        {q.StartDate},
+		"searchRange":      {strconv.Itoa(q.SearchRange)},
+		"scac":             slices.Compact(scacs),
+		"directOnly":       {strconv.FormatBool(q.DirectOnly)},
+		"transhipmentPort": {q.TSP},
+		"vesselIMO":        {q.VesselIMO},
+		"service":          {q.Service},
+	}
+	optional := map[string]string{"serviceName": q.ServiceName, "vesselName": q.VesselName, "cutoffAfter": q.CutoffAfter}
+	if q.Connections {
+		optional["connections"] = "true"
+	}
+	if q.MaxTransit != nil {
+		optional["maxTransitTime"] = strconv.Itoa(*q.MaxTransit)
+	}
+	if q.MaxTSP != nil {
+		optional["maxTransshipments"] = strconv.Itoa(*q.MaxTSP)
+	}
+	// the filters added later only show up in the key when they are used so the existing cache keys stay the same
+	for key, value := range optional {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	if len(q.ExcludeTSP) > 0 {
+		excluded := slices.Clone(q.ExcludeTSP)
+		slices.Sort(excluded)
+		values["excludeTransshipment"] = slices.Compact(excluded)
+	}
+	if len(q.Modes) > 0 {
+		modes := make([]string, 0, len(q.Modes))
+		for _, mode := range q.Modes {
+			modes = append(modes, string(mode))
+		}
+		slices.Sort(modes)
+		values["transportModes"] = slices.Compact(modes)
+	}
+	return values.Encode()
+}
+
+// PlannerQueryParams asks for the schedules arriving by a deadline
+type PlannerQueryParams struct {
+	PointFrom      string        `json:"pointFrom" validate:"required,portCodeValidation" description:"Port Of Loading"`
+	PointTo        string        `json:"pointTo" validate:"required,portCodeValidation" description:"Port Of Discharge"`
+	ArriveBy       string        `json:"arriveBy" validate:"required,isValidDate" description:"YYYY-MM-DD, the cargo has to arrive by the end of this day"`
+	CargoReadyDate string        `json:"cargoReadyDate" validate:"omitempty,isValidDate" description:"YYYY-MM-DD, the cargo can not be delivered to the terminal before this day"`
+	SearchRange    int           `json:"searchRange" validate:"omitempty,min=1,max=16" description:"Weeks before the deadline to look at, 4 by default"`
+	StartDateType  StartDateType `json:"startDateType" validate:"omitempty,oneof=Departure Arrival" description:"Arrival by default, Departure also fetches the sailings missing the deadline"`
+	MaxTransit     *int          `json:"maxTransitTime" validate:"omitempty,gte=1" description:"Max transit time in days, a Departure search starts this much earlier"`
+	SCAC           []CarrierCode `json:"scac" validate:"omitempty" example:"MSC,CMA"`
+	DirectOnly     bool          `json:"directOnly" validate:"omitempty"`
+	Origins        []string      `json:"-" validate:"omitempty,dive,portCodeValidation"`
+	Destinations   []string      `json:"-" validate:"omitempty,dive,portCodeValidation"`
+}
+
+// SearchQuery is the search over the weeks before the deadline. An Arrival search only returns the sailings arriving by then,
+// a Departure search starts the longest transit earlier(the requested maxTransitTime, otherwise maxTransitDays) so the sailings
+// leaving before the deadline but arriving after it are fetched too and can be flagged
+func (p *PlannerQueryParams) SearchQuery(maxTransitDays int) QueryParams {
+	searchRange := p.SearchRange
+	if searchRange == 0 {
+		searchRange = 4
+	}
+	startDateType := cmp.Or(p.StartDateType, Arrival)
+	if startDateType == Departure {
+		if p.MaxTransit != nil {
+			maxTransitDays = *p.MaxTransit
+		}
+		searchRange += (maxTransitDays + 6) / 7
+	}
+	arriveBy, _ := time.Parse("2006-01-02", p.ArriveBy)
+	return QueryParams{
+		PointFrom:     p.PointFrom,
+		PointTo:       p.PointTo,
+		StartDateType: startDateType,
+		StartDate:     arriveBy.AddDate(0, 0, -searchRange*7).Format("2006-01-02"),
+		SearchRange:   searchRange,
+		SCAC:          p.SCAC,
+		DirectOnly:    p.DirectOnly,
+		MaxTransit:    p.MaxTransit,
+		Origins:       p.Origins,
+		Destinations:  p.Destinations,
+	}
+}
+
+// BatchQuery is the body of the batch search, every lane takes the same fields as a single p2p request
+type BatchQuery struct {
+	Lanes []QueryParams `json:"lanes" validate:"required,dive"`
 }
 
 type QueryParamsForVesselVoyage struct {
@@ -73,4 +309,7 @@ type QueryParamsForVesselVoyage struct {
 	StartDate string        `json:"startDate" validate:"omitempty,isValidDate" description:"YYYY-MM-DD"`
 	DateRange int           `json:"dateRange" validate:"required_with=StartDate,gte=0" description:"Date Tolerance"`
 	Voyage    string        `json:"voyageNum"  validate:"omitempty" description:"Voyage Number"`
+	Format    string        `json:"format" validate:"omitempty,oneof=json ics dcsa" description:"Output format, ics returns the port calls as an iCalendar, dcsa as DCSA Operational Vessel Schedules"`
+	Fields    []string      `json:"fields" validate:"omitempty" description:"json only, the vessel schedule fields to keep, dotted for the port call fields" example:"scac,voyage,calls.port"`
+	FieldSet  FieldSet      `json:"-"` // fields checked against MasterVesselSchedule
 }
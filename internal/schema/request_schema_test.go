@@ -0,0 +1,145 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func testQuery() QueryParams {
+	return QueryParams{
+		PointFrom: "CNSHA", PointTo: "DEHAM", StartDateType: Departure, StartDate: "2024-03-10", SearchRange: 4,
+		SCAC: []CarrierCode{"MAEU", "CMDU"},
+	}
+}
+
+func intValue(value int) *int {
+	return &value
+}
+
+func TestCanonicalKey(t *testing.T) {
+	base := testQuery()
+	tests := []struct {
+		name   string
+		modify func(q *QueryParams)
+		same   bool
+	}{
+		{name: "carrier order", modify: func(q *QueryParams) { q.SCAC = []CarrierCode{"CMDU", "MAEU"} }, same: true},
+		{name: "repeated carrier", modify: func(q *QueryParams) { q.SCAC = []CarrierCode{"MAEU", "CMDU", "MAEU"} }, same: true},
+		{name: "single port group of one", modify: func(q *QueryParams) { q.Origins = []string{"CNSHA"} }, same: true},
+		{name: "presentation", modify: func(q *QueryParams) {
+			q.Format, q.Limit, q.Fields, q.SortBy, q.Dedupe = "csv", 10, []string{"scac"}, "etd", true
+		}, same: true},
+		{name: "another carrier", modify: func(q *QueryParams) { q.SCAC = []CarrierCode{"MAEU"} }},
+		{name: "another date", modify: func(q *QueryParams) { q.StartDate = "2024-03-11" }},
+		{name: "arrival", modify: func(q *QueryParams) { q.StartDateType = Arrival }},
+		{name: "another range", modify: func(q *QueryParams) { q.SearchRange = 5 }},
+		{name: "another destination", modify: func(q *QueryParams) { q.Destinations = []string{"DEHAM", "NLRTM"} }},
+		{name: "direct only", modify: func(q *QueryParams) { q.DirectOnly = true }},
+		{name: "zero transit time", modify: func(q *QueryParams) { q.MaxTransit = intValue(0) }},
+		{name: "zero transshipments", modify: func(q *QueryParams) { q.MaxTSP = intValue(0) }},
+		{name: "service name", modify: func(q *QueryParams) { q.ServiceName = "asia" }},
+		{name: "excluded port", modify: func(q *QueryParams) { q.ExcludeTSP = []string{"LK"} }},
+		{name: "connections", modify: func(q *QueryParams) { q.Connections = true }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := testQuery()
+			tt.modify(&query)
+			if same := query.CanonicalKey() == base.CanonicalKey(); same != tt.same {
+				t.Errorf("same key = %v, want %v\n%s\n%s", same, tt.same, query.CanonicalKey(), base.CanonicalKey())
+			}
+		})
+	}
+}
+
+func TestCanonicalKeyOrder(t *testing.T) {
+	first, second := testQuery(), testQuery()
+	first.Origins, second.Origins = []string{"CNSHA", "CNNGB"}, []string{"CNNGB", "CNSHA"}
+	first.ExcludeTSP, second.ExcludeTSP = []string{"SGSIN", "LK"}, []string{"LK", "SGSIN", "LK"}
+	first.Modes, second.Modes = []TransportType{Vessel, Feeder}, []TransportType{Feeder, Vessel}
+	if first.CanonicalKey() != second.CanonicalKey() {
+		t.Errorf("keys differ\n%s\n%s", first.CanonicalKey(), second.CanonicalKey())
+	}
+}
+
+// TestCanonicalKeyUnusedFilters keeps the keys cached before the later filters were added
+func TestCanonicalKeyUnusedFilters(t *testing.T) {
+	query := testQuery()
+	want := "directOnly=false&pointFrom=CNSHA&pointTo=DEHAM&scac=CMDU&scac=MAEU&searchRange=4&service=&startDate=2024-03-10" +
+		"&startDateType=Departure&transhipmentPort=&vesselIMO="
+	if got := query.CanonicalKey(); got != want {
+		t.Errorf("CanonicalKey() = %s\nwant %s", got, want)
+	}
+	for _, filter := range []string{"serviceName", "vesselName", "cutoffAfter", "connections", "maxTransitTime", "excludeTransshipment"} {
+		if strings.Contains(query.CanonicalKey(), filter) {
+			t.Errorf("the unused %s is part of the key", filter)
+		}
+	}
+}
+
+func TestPageToken(t *testing.T) {
+	issuer := testQuery()
+	issuer.Limit = 20
+	token := issuer.EncodePageToken(40)
+	tests := []struct {
+		name   string
+		modify func(q *QueryParams)
+		token  string
+		offset int
+		err    string
+	}{
+		{name: "first page", token: "", offset: 0},
+		{name: "same query", token: token, offset: 40},
+		{name: "carriers reordered and another limit", modify: func(q *QueryParams) { q.SCAC, q.Limit = []CarrierCode{"CMDU", "MAEU"}, 50 }, token: token, offset: 40},
+		{name: "another sort", modify: func(q *QueryParams) { q.SortBy = "eta" }, token: token, err: "does not belong to the requested query"},
+		{name: "dedupe", modify: func(q *QueryParams) { q.Dedupe = true }, token: token, err: "does not belong to the requested query"},
+		{name: "another lane", modify: func(q *QueryParams) { q.PointTo = "NLRTM" }, token: token, err: "does not belong to the requested query"},
+		{name: "not base64", token: "not a token!", err: "invalid pageToken"},
+		{name: "not json", token: "bm90IGpzb24", err: "invalid pageToken"},
+		{name: "negative offset", token: "eyJxIjoiIiwibyI6LTF9", err: "invalid pageToken"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := testQuery()
+			if tt.modify != nil {
+				tt.modify(&query)
+			}
+			query.PageToken = tt.token
+			offset, err := query.DecodePageToken()
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("DecodePageToken() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodePageToken() error = %v", err)
+			}
+			if offset != tt.offset {
+				t.Errorf("DecodePageToken() = %d, want %d", offset, tt.offset)
+			}
+		})
+	}
+}
+
+func TestPlannerSearchQuery(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      PlannerQueryParams
+		startDate   string
+		searchRange int
+	}{
+		{name: "arrival", params: PlannerQueryParams{ArriveBy: "2024-03-31"}, startDate: "2024-03-03", searchRange: 4},
+		{name: "arrival ignores the transit time", params: PlannerQueryParams{ArriveBy: "2024-03-31", SearchRange: 2, MaxTransit: intValue(30)}, startDate: "2024-03-17", searchRange: 2},
+		{name: "departure with the configured transit time", params: PlannerQueryParams{ArriveBy: "2024-03-31", StartDateType: Departure}, startDate: "2024-01-21", searchRange: 10},
+		{name: "departure with the requested transit time", params: PlannerQueryParams{ArriveBy: "2024-03-31", StartDateType: Departure, MaxTransit: intValue(15)}, startDate: "2024-02-11", searchRange: 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := tt.params.SearchQuery(42)
+			if query.StartDate != tt.startDate || query.SearchRange != tt.searchRange {
+				t.Errorf("SearchQuery() = %s + %d weeks, want %s + %d weeks", query.StartDate, query.SearchRange, tt.startDate, tt.searchRange)
+			}
+		})
+	}
+}
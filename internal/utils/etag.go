@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// ResponseBuffer keeps the body of a response that is only written once it is complete, the headers still go to the response.
+// It does not implement http.Flusher so the flushes of the encoders are no-ops
+type ResponseBuffer struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func NewResponseBuffer(w http.ResponseWriter) *ResponseBuffer {
+	return &ResponseBuffer{ResponseWriter: w}
+}
+
+func (b *ResponseBuffer) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+func (b *ResponseBuffer) Bytes() []byte {
+	return b.body.Bytes()
+}
+
+// StrongETag is derived from the bytes of the body, two responses with the same ETag are byte for byte identical
+func StrongETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches compares If-None-Match with the weak comparison RFC 9110 asks for on GET
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteWithETag writes the complete body with its strong ETag, or 304 Not Modified when the client already holds it
+func WriteWithETag(w http.ResponseWriter, r *http.Request, body []byte) {
+	etag := StrongETag(body)
+	w.Header().Set("ETag", etag)
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" && etagMatches(ifNoneMatch, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	_, _ = w.Write(body)
+}
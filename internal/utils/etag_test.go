@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriteWithETag(t *testing.T) {
+	body := []byte(`{"schedules":[]}`)
+	etag := StrongETag(body)
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		status      int
+	}{
+		{name: "no validator", status: http.StatusOK},
+		{name: "match", ifNoneMatch: etag, status: http.StatusNotModified},
+		{name: "weak match", ifNoneMatch: "W/" + etag, status: http.StatusNotModified},
+		{name: "one of a list", ifNoneMatch: `"stale", ` + etag, status: http.StatusNotModified},
+		{name: "any", ifNoneMatch: "*", status: http.StatusNotModified},
+		{name: "stale", ifNoneMatch: `"stale"`, status: http.StatusOK},
+		{name: "unquoted", ifNoneMatch: etag[1 : len(etag)-1], status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/schedules/p2p", nil)
+			if tt.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			rsp := httptest.NewRecorder()
+			WriteWithETag(rsp, r, body)
+			if rsp.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rsp.Code, tt.status)
+			}
+			if got := rsp.Header().Get("ETag"); got != etag {
+				t.Errorf("ETag = %s, want %s", got, etag)
+			}
+			wantBody := string(body)
+			if tt.status == http.StatusNotModified {
+				wantBody = ""
+			}
+			if got := rsp.Body.String(); got != wantBody {
+				t.Errorf("body = %q, want %q", got, wantBody)
+			}
+		})
+	}
+}
+
+func TestStrongETag(t *testing.T) {
+	first, second := StrongETag([]byte("a")), StrongETag([]byte("b"))
+	if first == second {
+		t.Error("two bodies share an ETag")
+	}
+	if first != StrongETag([]byte("a")) {
+		t.Error("the ETag of the same body changed")
+	}
+	if len(first) != 34 || first[0] != '"' || first[33] != '"' {
+		t.Errorf("ETag %s is not a quoted 32 digit hex", first)
+	}
+}
@@ -0,0 +1,72 @@
+package utils
+
+import "strconv"
+
+// The helpers below read typed values out of the merged yaml config map. yaml keeps numbers as int/float64 and lists as []interface{}
+// so every reader has to deal with the conversion, a missing key falls back to the default value.
+
+func IntSetting(settings map[string]interface{}, key string, fallback int) int {
+	switch v := settings[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case string:
+		if i, err := strconv.Atoi(v); err == nil {
+			return i
+		}
+	}
+	return fallback
+}
+
+func FloatSetting(settings map[string]interface{}, key string, fallback float64) float64 {
+	switch v := settings[key].(type) {
+	case int:
+		return float64(v)
+	case float64:
+		return v
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return fallback
+}
+
+func BoolSetting(settings map[string]interface{}, key string, fallback bool) bool {
+	if v, ok := settings[key].(bool); ok {
+		return v
+	}
+	return fallback
+}
+
+func StringSetting(settings map[string]interface{}, key string, fallback string) string {
+	if v, ok := settings[key].(string); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+func StringListSetting(settings map[string]interface{}, key string) []string {
+	raw, ok := settings[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	values := make([]string, 0, len(raw))
+	for _, item := range raw {
+		switch v := item.(type) {
+		case string:
+			values = append(values, v)
+		case int:
+			values = append(values, strconv.Itoa(v))
+		}
+	}
+	return values
+}
+
+func MapSetting(settings map[string]interface{}, key string) map[string]interface{} {
+	if v, ok := settings[key].(map[string]interface{}); ok {
+		return v
+	}
+	return map[string]interface{}{}
+}